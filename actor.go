@@ -0,0 +1,507 @@
+package clockpro
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"maps"
+	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// Actor owns a [Cache] within a single goroutine,
+	// which applies all modifications in the order they
+	// are received. Hits are served from a snapshot of
+	// the resident values, which is replaced by the owner
+	// after each batch of modifications. See [actorSnapshot].
+	// Constructed by [NewActor].
+	//
+	// [Actor.Set] and [Actor.SetWithTTL] return once the
+	// modification is queued, and are visible to lookups
+	// once applied; other methods wait for the owner.
+	// Lookups served from the snapshot only set the page's
+	// reference bit, like [Synced].
+	Actor[Key comparable, Value any] struct {
+		snapshot atomic.Pointer[actorSnapshot[Key, Value]]
+		cache    *Cache[Key, Value]
+		// base is shared with snapshots, and is replaced
+		// rather than modified. changes are owned.
+		base, changes map[Key]*syncedEntry[Key, Value]
+		messages      chan func()
+		stop          chan struct{}
+		done          chan struct{}
+		closer        sync.Once
+		lookups       unlockedLookups
+		dirty         bool // Owned.
+	}
+	// actorSnapshot holds the resident entries of an [Actor].
+	// Entries modified since base was copied are held
+	// by changes, in which removed keys hold nil,
+	// so that replacing a snapshot copies the changes
+	// rather than every entry.
+	actorSnapshot[Key comparable, Value any] struct {
+		base, changes map[Key]*syncedEntry[Key, Value]
+	}
+)
+
+// actorQueueSize is the default amount of messages
+// that may be queued for the owner. See [WithWriteBuffer].
+const actorQueueSize = 64
+
+// actorMinChanges is the amount of changes
+// a snapshot may hold regardless of its size.
+const actorMinChanges = 64
+
+// NewActor constructs an [Actor] and starts its owner goroutine,
+// which runs until [Actor.Close] is called.
+// Parameters are the same as [New].
+// [WithWriteBuffer] sets the amount of modifications
+// that may be queued before callers must wait for the owner.
+func NewActor[Key comparable, Value any](capacity int, options ...Option[Key, Value]) (*Actor[Key, Value], error) {
+	var (
+		actor = &Actor[Key, Value]{
+			base:    make(map[Key]*syncedEntry[Key, Value]),
+			changes: make(map[Key]*syncedEntry[Key, Value]),
+			stop:    make(chan struct{}),
+			done:    make(chan struct{}),
+		}
+		all = append(options[:len(options):len(options)],
+			func(set *settings[Key, Value]) error {
+				set.residency = actor
+				return nil
+			})
+		cache, err = New(capacity, all...)
+	)
+	if err != nil {
+		return nil, err
+	}
+	queueSize := actorQueueSize
+	if size := cache.writeBuffer; size != 0 {
+		queueSize = size
+	}
+	actor.cache = cache
+	actor.messages = make(chan func(), queueSize)
+	actor.publish()
+	go actor.run()
+	return actor, nil
+}
+
+// Get is like [Cache.Get], but is served from the snapshot.
+func (a *Actor[Key, Value]) Get(key Key) (Value, bool) {
+	value, ok := a.lookup(key)
+	if !ok {
+		a.lookups.misses.Add(1)
+	}
+	return value, ok
+}
+
+// lookup returns the value of key from the snapshot,
+// if it is resident, counting only hits.
+func (a *Actor[Key, Value]) lookup(key Key) (Value, bool) {
+	var zero Value
+	entry, ok := a.snapshot.Load().get(key)
+	if !ok ||
+		(entry.deadline != 0 && entry.deadline <= a.cache.now().UnixNano()) {
+		return zero, false
+	}
+	entry.touch()
+	a.lookups.hits.Add(1)
+	return a.cache.decoded(entry.value), true
+}
+
+// GetOrSet is like [Cache.GetOrSet].
+func (a *Actor[Key, Value]) GetOrSet(key Key, value Value) (actual Value, loaded bool) {
+	if actual, ok := a.lookup(key); ok {
+		return actual, true
+	}
+	a.Do(func(cache *Cache[Key, Value]) {
+		actual, loaded = cache.GetOrSet(key, value)
+	})
+	return actual, loaded
+}
+
+// SetIf is like [Cache.SetIf]. cond is called
+// from the owner goroutine, and must not call
+// methods of the Actor. After [Actor.Close],
+// SetIf returns false without calling cond.
+func (a *Actor[Key, Value]) SetIf(key Key, value Value, cond func(old Value, exists bool) bool) (stored bool) {
+	a.Do(func(cache *Cache[Key, Value]) {
+		stored = cache.SetIf(key, value, cond)
+	})
+	return stored
+}
+
+// Compute is like [Cache.Compute], and calls f
+// like the condition of [Actor.SetIf].
+// After [Actor.Close], Compute returns the zero value
+// and false without calling f.
+func (a *Actor[Key, Value]) Compute(key Key, f func(old Value, exists bool) (value Value, keep bool)) (actual Value, resident bool) {
+	a.Do(func(cache *Cache[Key, Value]) {
+		actual, resident = cache.Compute(key, f)
+	})
+	return actual, resident
+}
+
+// Mutate is like [Cache.Mutate], and calls mutate
+// like the condition of [Actor.SetIf].
+// After [Actor.Close], Mutate returns false
+// without calling mutate.
+func (a *Actor[Key, Value]) Mutate(key Key, mutate func(*Value)) (resident bool) {
+	a.Do(func(cache *Cache[Key, Value]) {
+		resident = cache.Mutate(key, mutate)
+	})
+	return resident
+}
+
+// Load is like [Synced.Load]. If the actor is closed,
+// the fetched value is returned with [ErrClosed],
+// and is not cached.
+func (a *Actor[Key, Value]) Load(key Key, fetch func() (Value, error)) (Value, error) {
+	return a.load(context.Background(), key, fetcher[Value]{plain: fetch})
+}
+
+// LoadContext is like [Synced.LoadContext] and [Actor.Load].
+func (a *Actor[Key, Value]) LoadContext(ctx context.Context, key Key, fetch func(context.Context) (Value, error)) (Value, error) {
+	return a.load(ctx, key, fetcher[Value]{withContext: fetch})
+}
+
+func (a *Actor[Key, Value]) load(ctx context.Context, key Key, fetch fetcher[Value]) (value Value, err error) {
+	ctx, end := a.cache.startLoad(ctx, key)
+	var hit bool
+	if end != nil {
+		defer func() { end(hit, err) }()
+	}
+	if value, hit = a.Get(key); hit {
+		return value, nil
+	}
+	if value, err = a.cache.fetch(ctx, key, fetch); err != nil {
+		return value, fetchError(err)
+	}
+	var (
+		actual = value
+		stored bool
+	)
+	a.Do(func(cache *Cache[Key, Value]) {
+		actual, _ = cache.setIfAbsent(key, value)
+		stored = true
+	})
+	if !stored {
+		return value, ErrClosed
+	}
+	return actual, nil
+}
+
+// Set is like [Cache.Set], but returns once queued.
+func (a *Actor[Key, Value]) Set(key Key, value Value) {
+	a.send(func() { a.cache.Set(key, value) })
+}
+
+// SetWithTTL is like [Cache.SetWithTTL], but returns once queued.
+func (a *Actor[Key, Value]) SetWithTTL(key Key, value Value, ttl time.Duration) {
+	a.send(func() { a.cache.SetWithTTL(key, value, ttl) })
+}
+
+// Delete is like [Cache.Delete].
+func (a *Actor[Key, Value]) Delete(key Key) (resident bool) {
+	a.Do(func(cache *Cache[Key, Value]) {
+		resident = cache.Delete(key)
+	})
+	return resident
+}
+
+// Remove is like [Cache.Remove].
+func (a *Actor[Key, Value]) Remove(key Key) (value Value, resident bool) {
+	a.Do(func(cache *Cache[Key, Value]) {
+		value, resident = cache.Remove(key)
+	})
+	return value, resident
+}
+
+// Apply is like [Cache.Apply], and is performed
+// by the owner as a single message.
+func (a *Actor[Key, Value]) Apply(ops []Op[Key, Value]) (results []OpResult[Value]) {
+	a.Do(func(cache *Cache[Key, Value]) {
+		results = cache.Apply(ops)
+	})
+	if results == nil { // Closed.
+		results = make([]OpResult[Value], len(ops))
+	}
+	return results
+}
+
+// Purge is like [Cache.Purge].
+// onPurge is called by the owner.
+func (a *Actor[Key, Value]) Purge(onPurge func(Key, Value)) {
+	a.Do(func(cache *Cache[Key, Value]) {
+		cache.Purge(onPurge)
+	})
+}
+
+// Len is like [Cache.Len].
+func (a *Actor[Key, Value]) Len() (length int) {
+	a.Do(func(cache *Cache[Key, Value]) {
+		length = cache.Len()
+	})
+	return length
+}
+
+// ResidentHot is like [Cache.ResidentHot].
+func (a *Actor[Key, Value]) ResidentHot() (count int) {
+	a.Do(func(cache *Cache[Key, Value]) {
+		count = cache.ResidentHot()
+	})
+	return count
+}
+
+// ResidentCold is like [Cache.ResidentCold].
+func (a *Actor[Key, Value]) ResidentCold() (count int) {
+	a.Do(func(cache *Cache[Key, Value]) {
+		count = cache.ResidentCold()
+	})
+	return count
+}
+
+// NonResident is like [Cache.NonResident].
+func (a *Actor[Key, Value]) NonResident() (count int) {
+	a.Do(func(cache *Cache[Key, Value]) {
+		count = cache.NonResident()
+	})
+	return count
+}
+
+// Keys is like [Synced.Keys].
+func (a *Actor[Key, Value]) Keys() iter.Seq[Key] {
+	var keys []Key
+	a.Do(func(cache *Cache[Key, Value]) {
+		keys = slices.Collect(cache.Keys())
+	})
+	return slices.Values(keys)
+}
+
+// Range is like [Synced.Range].
+func (a *Actor[Key, Value]) Range(f func(Key, Value) bool) {
+	var (
+		keys   []Key
+		values []Value
+	)
+	a.Do(func(cache *Cache[Key, Value]) {
+		keys, values = collectEntries(cache, nil)
+	})
+	rangeEntries(keys, values, f)
+}
+
+// Flush waits for the owner to apply all previously
+// queued modifications, and then is like [Cache.Flush].
+func (a *Actor[Key, Value]) Flush() {
+	a.Do(func(*Cache[Key, Value]) {})
+	a.cache.Flush()
+}
+
+// Snapshot is like [Cache.Snapshot].
+// The snapshot is taken by the owner.
+func (a *Actor[Key, Value]) Snapshot() (view *ReadOnlyView[Key, Value]) {
+	a.Do(func(cache *Cache[Key, Value]) {
+		view = cache.Snapshot()
+	})
+	if view == nil { // Closed; the cache is no longer modified.
+		<-a.done
+		view = a.cache.Snapshot()
+	}
+	return view
+}
+
+// Stats is like [Cache.Stats], including
+// lookups served from the snapshot.
+func (a *Actor[Key, Value]) Stats() (stats Stats) {
+	a.Do(func(cache *Cache[Key, Value]) {
+		a.lookups.fold(&cache.stats.recent)
+		stats = cache.Stats()
+		a.lookups.addTotal(&stats)
+	})
+	return stats
+}
+
+// IntervalStats is like [Cache.IntervalStats], including
+// lookups served from the snapshot.
+func (a *Actor[Key, Value]) IntervalStats() (stats Stats) {
+	a.Do(func(cache *Cache[Key, Value]) {
+		a.lookups.fold(&cache.stats.recent)
+		stats = cache.IntervalStats()
+		a.lookups.addInterval(&stats)
+	})
+	return stats
+}
+
+// ResetStats is like [Cache.ResetStats], including
+// lookups served from the snapshot.
+func (a *Actor[Key, Value]) ResetStats() (stats Stats) {
+	a.Do(func(cache *Cache[Key, Value]) {
+		a.lookups.fold(&cache.stats.recent)
+		stats = cache.ResetStats()
+		a.lookups.addInterval(&stats)
+		a.lookups.reset()
+	})
+	return stats
+}
+
+// CheckInvariants is like [Cache.CheckInvariants],
+// and also checks the entries of the snapshot.
+func (a *Actor[Key, Value]) CheckInvariants() (err error) {
+	a.Do(func(cache *Cache[Key, Value]) {
+		a.publish()
+		err = errors.Join(
+			cache.CheckInvariants(),
+			cache.checkEntries(a.snapshot.Load().all()),
+		)
+	})
+	return err
+}
+
+// Do calls fn with the cache from the owner goroutine,
+// after all previously queued modifications,
+// and waits for it to return.
+// The snapshot is replaced before Do returns.
+// fn must not retain the cache, nor call methods of the Actor.
+// After [Actor.Close], Do returns without calling fn.
+func (a *Actor[Key, Value]) Do(fn func(*Cache[Key, Value])) {
+	reply := make(chan struct{})
+	if !a.send(func() {
+		fn(a.cache)
+		a.publish()
+		close(reply)
+	}) {
+		return
+	}
+	select {
+	case <-reply:
+	case <-a.done:
+	}
+}
+
+// Close applies any queued modifications
+// and stops the owner goroutine.
+// Subsequent modifications are discarded,
+// but lookups continue to be served from the last snapshot.
+// Close returns [ErrClosed] if the actor was already closed.
+func (a *Actor[_, _]) Close() error {
+	closed := true
+	a.closer.Do(func() {
+		close(a.stop)
+		<-a.done
+		closed = false
+	})
+	if closed {
+		return ErrClosed
+	}
+	return nil
+}
+
+func (a *Actor[_, _]) send(message func()) bool {
+	select {
+	case <-a.stop:
+		return false
+	default:
+	}
+	select {
+	case a.messages <- message:
+		return true
+	case <-a.stop:
+		return false
+	}
+}
+
+func (a *Actor[_, _]) run() {
+	defer close(a.done)
+	for {
+		select {
+		case message := <-a.messages:
+			message()
+			a.receive()
+		case <-a.stop:
+			a.receive()
+			return
+		}
+	}
+}
+
+// receive processes queued messages,
+// and then replaces the snapshot if needed.
+func (a *Actor[_, _]) receive() {
+	for {
+		select {
+		case message := <-a.messages:
+			message()
+		default:
+			a.lookups.fold(&a.cache.stats.recent)
+			a.publish()
+			return
+		}
+	}
+}
+
+// publish replaces the snapshot if it was modified.
+// Once the changes outgrow the square root of twice
+// the base's size, they are applied to a copy of the base,
+// balancing the cost of copying the changes for each
+// snapshot against the amortized cost of the copy.
+func (a *Actor[Key, Value]) publish() {
+	if !a.dirty && a.snapshot.Load() != nil {
+		return
+	}
+	limit := max(actorMinChanges, int(math.Sqrt(float64(2*len(a.base)))))
+	if len(a.changes) > limit {
+		base := maps.Clone(a.base)
+		for key, entry := range a.changes {
+			if entry == nil {
+				delete(base, key)
+			} else {
+				base[key] = entry
+			}
+		}
+		a.base = base
+		clear(a.changes)
+	}
+	a.snapshot.Store(&actorSnapshot[Key, Value]{
+		base:    a.base,
+		changes: maps.Clone(a.changes),
+	})
+	a.dirty = false
+}
+
+func (a *Actor[Key, Value]) stored(page *page[Key, Value], deadline int64) {
+	a.changes[page.Name] = newSyncedEntry(page, deadline)
+	a.dirty = true
+}
+
+func (a *Actor[Key, _]) dropped(key Key) {
+	a.changes[key] = nil
+	a.dirty = true
+}
+
+func (as *actorSnapshot[Key, Value]) get(key Key) (*syncedEntry[Key, Value], bool) {
+	if entry, changed := as.changes[key]; changed {
+		return entry, entry != nil
+	}
+	entry, ok := as.base[key]
+	return entry, ok
+}
+
+// all returns an iterator over the entries of the snapshot.
+func (as *actorSnapshot[Key, Value]) all() iter.Seq2[Key, *syncedEntry[Key, Value]] {
+	return func(yield func(Key, *syncedEntry[Key, Value]) bool) {
+		for key, entry := range as.changes {
+			if entry != nil && !yield(key, entry) {
+				return
+			}
+		}
+		for key, entry := range as.base {
+			if _, changed := as.changes[key]; !changed && !yield(key, entry) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package clockpro_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestActor(t *testing.T) {
+	t.Run("invalid capacity", actorInvalidCapacity)
+	t.Run("basic", actorBasic)
+	t.Run("order", actorOrder)
+	t.Run("close", actorClose)
+	t.Run("concurrent", actorConcurrent)
+	t.Run("snapshots", actorSnapshots)
+}
+
+func newActor(tb testing.TB, capacity int, options ...clockpro.Option[int, int]) *clockpro.Actor[int, int] {
+	tb.Helper()
+	actor, err := clockpro.NewActor(capacity, options...)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { actor.Close() })
+	return actor
+}
+
+func actorInvalidCapacity(t *testing.T) {
+	t.Parallel()
+	actor, err := clockpro.NewActor[int, int](1)
+	if actor != nil || err == nil {
+		t.Error("NewActor did not return an error when passed an invalid capacity")
+	}
+}
+
+// waitFor returns once the actor has applied
+// all previously queued modifications.
+func waitFor(actor *clockpro.Actor[int, int]) {
+	actor.Do(func(*clockpro.Cache[int, int]) {})
+}
+
+func actorBasic(t *testing.T) {
+	t.Parallel()
+	const capacity = 16
+	actor := newActor(t, capacity)
+	mustMiss(t, actor, 0, "empty cache")
+	for key := range capacity * 2 {
+		actor.Set(key, key)
+	}
+	waitFor(actor)
+	checkSize(t, actor, capacity, "after inserting twice the capacity")
+	keys := slices.Sorted(actor.Keys())
+	for _, key := range keys {
+		checkGet(t, actor, key, key, "resident key")
+	}
+	stats := actor.Stats()
+	if want := uint64(len(keys)); stats.Hits != want || stats.Misses != 1 {
+		t.Errorf(
+			"unexpected hits and misses"+
+				"\n\tgot: %d, %d"+
+				"\n\twant: %d, %d",
+			stats.Hits, stats.Misses, want, 1,
+		)
+	}
+}
+
+// actorSnapshots publishes a snapshot after each modification,
+// such that their changes are applied to a new base
+// several times, and expects each to match the cache.
+func actorSnapshots(t *testing.T) {
+	t.Parallel()
+	const capacity = 512
+	actor := newActor(t, capacity)
+	for key := range capacity * 4 {
+		actor.Set(key, key)
+		if key%3 == 0 {
+			actor.Delete(key / 2)
+		}
+		waitFor(actor)
+		if err := actor.CheckInvariants(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for key := range actor.Keys() {
+		checkGet(t, actor, key, key, "resident key")
+	}
+}
+
+func actorOrder(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 16
+		key      = 1
+	)
+	actor := newActor(t, capacity,
+		clockpro.WithWriteBuffer[int, int](capacity),
+	)
+	for value := range capacity {
+		actor.Set(key, value)
+	}
+	if !actor.Delete(key) {
+		t.Fatal("Delete did not observe queued modifications")
+	}
+	mustMiss(t, actor, key, "deletion after queued sets")
+	if got, loaded := actor.GetOrSet(key, key); loaded || got != key {
+		t.Errorf(
+			"GetOrSet loaded a deleted key"+
+				"\n\tgot: %d, %t"+
+				"\n\twant: %d, %t",
+			got, loaded, key, false,
+		)
+	}
+	checkGet(t, actor, key, key, "GetOrSet value")
+}
+
+func actorClose(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 4
+		key      = 1
+	)
+	actor := newActor(t, capacity)
+	actor.Set(key, key)
+	if err := actor.Close(); err != nil {
+		t.Fatal(err)
+	}
+	checkGet(t, actor, key, key, "value queued before close")
+	actor.Set(key+1, key+1)
+	mustMiss(t, actor, key+1, "modification after close")
+	if actor.Delete(key) {
+		t.Error("Delete modified a closed actor")
+	}
+	checkGet(t, actor, key, key, "value after close")
+	value, err := actor.Load(key+2, func() (int, error) { return key + 2, nil })
+	if value != key+2 || !errors.Is(err, clockpro.ErrClosed) {
+		t.Errorf(
+			"unexpected Load result after close"+
+				"\n\tgot: %d, %v"+
+				"\n\twant: %d, %v",
+			value, err, key+2, clockpro.ErrClosed,
+		)
+	}
+	if err := actor.Close(); !errors.Is(err, clockpro.ErrClosed) {
+		t.Errorf(
+			"expected error to match"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			err, clockpro.ErrClosed,
+		)
+	}
+}
+
+func actorConcurrent(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity   = 64
+		upperBound = capacity * 2
+		workers    = 8
+		accesses   = 4096
+	)
+	actor := newActor(t, capacity,
+		clockpro.WithTimeSource[int, int](time.Now),
+	)
+	exerciseConcurrently(t, actor, upperBound, workers, accesses)
+	if length := actor.Len(); length > capacity {
+		t.Errorf(
+			"cache exceeded capacity"+
+				"\n\tgot: %d"+
+				"\n\twant: <=%d",
+			length, capacity,
+		)
+	}
+}
+
+// BenchmarkActorSet measures modifications which are
+// each published before the next, such that the cost
+// of replacing the snapshot is not amortized by batching.
+func BenchmarkActorSet(b *testing.B) {
+	for _, capacity := range []int{1 << 10, 1 << 16} {
+		b.Run(fmt.Sprintf("Cap%d", capacity), func(b *testing.B) {
+			actor := newActor(b, capacity)
+			for key := range capacity {
+				actor.Set(key, key)
+			}
+			waitFor(actor)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := range b.N {
+				actor.Set(i%(capacity*2), i)
+				waitFor(actor)
+			}
+		})
+	}
+}
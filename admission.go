@@ -0,0 +1,68 @@
+package clockpro
+
+import (
+	"fmt"
+	"hash/maphash"
+)
+
+// doorkeeper remembers keys which were
+// recently denied admission to a full cache.
+// It is cleared after remembering as many keys
+// as it was sized for, so that it only retains
+// keys which were seen recently.
+type doorkeeper[Key comparable] struct {
+	filter        bloom[Key]
+	added, length int
+}
+
+// WithDoorkeeper filters the admission of new keys
+// while the cache is full. A key which is not tracked
+// by the cache must be set twice within a window
+// of size distinct rejected keys before it is admitted,
+// so that keys which are only accessed once
+// do not displace resident pages.
+// Keys which are rejected are not stored,
+// and are reported as [SetRejected] by [Cache.SetReport].
+// Caches which filter admission cannot be checked by [Replay].
+func WithDoorkeeper[Key comparable, Value any](size int) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if size < 1 {
+			return fmt.Errorf(
+				"%w: doorkeeper size must be >=1 but %d was provided",
+				ErrInvalidOption, size,
+			)
+		}
+		set.doorkeeper = &doorkeeper[Key]{
+			filter: newBloom[Key](maphash.MakeSeed(), size),
+			length: size,
+		}
+		return nil
+	}
+}
+
+// admit reports whether key was rejected recently,
+// and remembers it otherwise.
+func (dk *doorkeeper[Key]) admit(key Key) bool {
+	if dk.filter.insert(key) {
+		return true
+	}
+	if dk.added++; dk.added == dk.length {
+		dk.filter.reset()
+		dk.added = 0
+	}
+	return false
+}
+
+func (dk *doorkeeper[Key]) clone() *doorkeeper[Key] {
+	clone := *dk
+	clone.filter = dk.filter.clone()
+	return &clone
+}
+
+// admit reports whether a key without metadata
+// may be inserted into the cache.
+func (c *Cache[Key, _]) admit(key Key) bool {
+	return c.doorkeeper == nil ||
+		!c.atCapacity() ||
+		c.doorkeeper.admit(key)
+}
@@ -0,0 +1,75 @@
+package clockpro_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestDoorkeeper(t *testing.T) {
+	t.Run("invalid size", doorkeeperInvalid)
+	t.Run("second chance", doorkeeperSecondChance)
+	t.Run("one hit wonders", doorkeeperOneHitWonders)
+}
+
+func doorkeeperInvalid(t *testing.T) {
+	t.Parallel()
+	_, err := clockpro.New(2, clockpro.WithDoorkeeper[int, int](0))
+	if !errors.Is(err, clockpro.ErrInvalidOption) {
+		t.Errorf(
+			"expected error to match"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			err, clockpro.ErrInvalidOption)
+	}
+}
+
+func doorkeeperSecondChance(t *testing.T) {
+	t.Parallel()
+	const capacity = 4
+	cache := newDoorkeeperCache(t, capacity)
+	for _, test := range []struct {
+		key  int
+		want clockpro.SetOutcome
+	}{
+		{1, clockpro.SetInserted}, // Not full.
+		{2, clockpro.SetInserted},
+		{3, clockpro.SetInserted},
+		{4, clockpro.SetInserted},
+		{5, clockpro.SetRejected},
+		{5, clockpro.SetInserted},
+	} {
+		if got := cache.SetReport(test.key, test.key); got != test.want {
+			t.Errorf(
+				"unexpected outcome for key %d"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				test.key, got, test.want)
+		}
+	}
+	checkCount(t, "rejections", cache.Stats().Rejections, 1)
+}
+
+func doorkeeperOneHitWonders(t *testing.T) {
+	t.Parallel()
+	const capacity = 16
+	cache := newDoorkeeperCache(t, capacity)
+	addIncrementingInts(cache, capacity)
+	want := slices.Collect(cache.Keys())
+	for key := capacity + 1; key <= capacity*4; key++ {
+		cache.Set(key, key)
+	}
+	keysMatch(t, cache, want, "residents after a stream of unique keys")
+}
+
+func newDoorkeeperCache(tb testing.TB, capacity int) *clockpro.Cache[int, int] {
+	tb.Helper()
+	const size = 1024
+	cache, err := clockpro.New(capacity, clockpro.WithDoorkeeper[int, int](size))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return cache
+}
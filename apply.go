@@ -0,0 +1,144 @@
+package clockpro
+
+import "strconv"
+
+type (
+	// OpKind identifies the method an [Op] corresponds to.
+	OpKind uint8
+	// Op is a single operation within a batch.
+	// See [Cache.Apply].
+	Op[Key comparable, Value any] struct {
+		Key Key
+		// Value is only used by [OpSet].
+		Value Value
+		Kind  OpKind
+	}
+	// OpResult is the result of an [Op].
+	OpResult[Value any] struct {
+		// Value is the value retrieved by [OpGet],
+		// or removed by [OpDelete].
+		Value Value
+		// OK reports whether the key was resident:
+		// before [OpGet] and [OpDelete],
+		// or after [OpSet].
+		OK bool
+	}
+)
+
+const (
+	// OpGet corresponds to [Cache.Get].
+	OpGet OpKind = iota + 1
+	// OpSet corresponds to [Cache.Set].
+	OpSet
+	// OpDelete corresponds to [Cache.Remove].
+	OpDelete
+)
+
+// Apply performs each operation in order, and returns their results.
+// Before the first operation, expired entries are evicted,
+// and room is made for the keys which the batch inserts
+// by a single sweep of the hands, as if by [Cache.EvictN],
+// rather than by a sweep for each insertion.
+// At most the resident cold pages are evicted by the sweep,
+// as a sequence of insertions would replace cold pages
+// before hot ones; room for any further keys is made
+// as they are inserted.
+// Resident pages which the batch gets or sets are referenced
+// before the sweep, so that it passes over them.
+// If the cache was constructed with [WithDoorkeeper],
+// insertions may be rejected, so room is made for each
+// insertion as it is performed instead.
+// Otherwise, each operation behaves as the method
+// it corresponds to.
+// Operations of an unknown kind have a zero result.
+func (c *Cache[Key, Value]) Apply(ops []Op[Key, Value]) []OpResult[Value] {
+	results := make([]OpResult[Value], len(ops))
+	c.Expire()
+	c.reserve(ops)
+	c.batching = true
+	defer func() { c.batching = false }()
+	for i, op := range ops {
+		results[i] = c.apply(op)
+	}
+	return results
+}
+
+// reserve evicts pages until the cache has room
+// for the keys which ops insert, referencing
+// the resident pages which ops get or set.
+func (c *Cache[Key, Value]) reserve(ops []Op[Key, Value]) {
+	if c.doorkeeper != nil {
+		return
+	}
+	inserts := make(map[Key]struct{})
+	for _, op := range ops {
+		if op.Kind != OpGet && op.Kind != OpSet {
+			continue
+		}
+		if page, ok := c.index.get(op.Key); ok && page.Resident {
+			page.Referenced = true
+		} else if op.Kind == OpSet {
+			inserts[op.Key] = struct{}{}
+		}
+	}
+	excess := min(c.Len()+len(inserts)-c.capacity, c.coldCount)
+	if excess > 0 {
+		c.EvictN(excess)
+	}
+}
+
+func (c *Cache[Key, Value]) apply(op Op[Key, Value]) (result OpResult[Value]) {
+	switch op.Kind {
+	case OpGet:
+		result.Value, result.OK = c.Get(op.Key)
+	case OpSet:
+		result.OK = c.set(op.Key, op.Value, 0).outcome != SetRejected
+	case OpDelete:
+		result.Value, result.OK = c.Remove(op.Key)
+	}
+	return result
+}
+
+// applyPartitioned applies ops to each partition in turn,
+// passing apply the operations whose keys belong to it,
+// and returns the results in the order of ops.
+func applyPartitioned[Key comparable, Value any](
+	ops []Op[Key, Value], partitions int, partition func(Key) int,
+	apply func(partition int, ops []Op[Key, Value]) []OpResult[Value],
+) []OpResult[Value] {
+	indices := make([][]int, partitions)
+	for i, op := range ops {
+		target := partition(op.Key)
+		indices[target] = append(indices[target], i)
+	}
+	var (
+		results = make([]OpResult[Value], len(ops))
+		batch   []Op[Key, Value]
+	)
+	for target, opIndices := range indices {
+		if len(opIndices) == 0 {
+			continue
+		}
+		batch = batch[:0]
+		for _, i := range opIndices {
+			batch = append(batch, ops[i])
+		}
+		for j, result := range apply(target, batch) {
+			results[opIndices[j]] = result
+		}
+	}
+	return results
+}
+
+func (kind OpKind) String() string {
+	switch kind {
+	case OpGet:
+		return "get"
+	case OpSet:
+		return "set"
+	case OpDelete:
+		return "delete"
+	default:
+		return "OpKind(" + strconv.Itoa(int(kind)) + ")"
+	}
+}
@@ -0,0 +1,214 @@
+package clockpro_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+type (
+	intOp     = clockpro.Op[int, int]
+	intResult = clockpro.OpResult[int]
+	applier   interface {
+		Apply([]intOp) []intResult
+	}
+)
+
+func TestApply(t *testing.T) {
+	t.Run("results", applyResults)
+	t.Run("matches sequential", applyMatchesSequential)
+	t.Run("single sweep", applySingleSweep)
+	t.Run("op kind string", opKindString)
+}
+
+func applyResults(t *testing.T) {
+	t.Parallel()
+	const capacity = 16
+	var (
+		ops = []intOp{
+			{Kind: clockpro.OpGet, Key: 1},
+			{Kind: clockpro.OpSet, Key: 1, Value: 10},
+			{Kind: clockpro.OpSet, Key: 2, Value: 20},
+			{Kind: clockpro.OpGet, Key: 1},
+			{Kind: clockpro.OpDelete, Key: 1},
+			{Kind: clockpro.OpGet, Key: 1},
+			{Kind: clockpro.OpDelete, Key: 3},
+			{Kind: clockpro.OpGet, Key: 2},
+			{Key: 2}, // Unknown kind.
+		}
+		want = []intResult{
+			{},
+			{OK: true},
+			{OK: true},
+			{Value: 10, OK: true},
+			{Value: 10, OK: true},
+			{},
+			{},
+			{Value: 20, OK: true},
+			{},
+		}
+	)
+	for _, test := range []struct {
+		name string
+		new  func(testing.TB) applier
+	}{
+		{"cache", func(tb testing.TB) applier {
+			return newCache[int, int](tb, capacity).(*clockpro.Cache[int, int])
+		}},
+		{"synced", func(tb testing.TB) applier {
+			return newSynced(tb, capacity)
+		}},
+		{"sharded", func(tb testing.TB) applier {
+			return newSharded(tb, capacity, 4)
+		}},
+		{"striped", func(tb testing.TB) applier {
+			return newStriped(tb, capacity, 4)
+		}},
+		{"actor", func(tb testing.TB) applier {
+			return newActor(tb, capacity)
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			got := test.new(t).Apply(ops)
+			if !slices.Equal(got, want) {
+				t.Errorf(
+					"unexpected results"+
+						"\n\tgot: %v"+
+						"\n\twant: %v",
+					got, want,
+				)
+			}
+		})
+	}
+}
+
+// applyMatchesSequential checks that a batch
+// leaves the cache in the same state as calling
+// the corresponding methods one at a time,
+// when the batch starts from an empty cache,
+// so that it does not need to make room.
+func applyMatchesSequential(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity   = 32
+		upperBound = capacity * 3
+		count      = capacity * 32
+	)
+	var (
+		rng        = newReproducibleRNG()
+		batched    = newCache[int, int](t, capacity).(*clockpro.Cache[int, int])
+		sequential = newCache[int, int](t, capacity).(*clockpro.Cache[int, int])
+		ops        = make([]intOp, count)
+	)
+	for i := range ops {
+		ops[i] = intOp{
+			Kind:  clockpro.OpKind(rng.Intn(3) + 1),
+			Key:   rng.Intn(upperBound),
+			Value: i,
+		}
+	}
+	results := batched.Apply(ops)
+	for i, op := range ops {
+		var want intResult
+		switch op.Kind {
+		case clockpro.OpGet:
+			want.Value, want.OK = sequential.Get(op.Key)
+		case clockpro.OpSet:
+			sequential.Set(op.Key, op.Value)
+			want.OK = true
+		case clockpro.OpDelete:
+			want.Value, want.OK = sequential.Remove(op.Key)
+		}
+		if got := results[i]; got != want {
+			t.Fatalf(
+				"result %d (%v %d) differs"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				i, op.Kind, op.Key, got, want,
+			)
+		}
+	}
+	var (
+		got  = slices.Sorted(batched.Keys())
+		want = slices.Sorted(sequential.Keys())
+	)
+	if !slices.Equal(got, want) {
+		t.Errorf(
+			"resident keys differ"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			got, want,
+		)
+	}
+}
+
+// applySingleSweep inserts as many keys as the cache has
+// cold pages, and gets a cold key after the insertions.
+// Calling the methods one at a time evicts that key before
+// it is referenced, but the batch references it before
+// making room, and evicts the other cold pages instead.
+func applySingleSweep(t *testing.T) {
+	t.Parallel()
+	const capacity = 16
+	var (
+		batched    = newCache[int, int](t, capacity).(*clockpro.Cache[int, int])
+		sequential = newCache[int, int](t, capacity).(*clockpro.Cache[int, int])
+	)
+	for _, cache := range []*clockpro.Cache[int, int]{batched, sequential} {
+		addIncrementingInts(cache, capacity)
+	}
+	var (
+		cold = slices.Collect(batched.ColdKeys())
+		kept = cold[0] // Under the cold hand.
+		ops  []intOp
+	)
+	for i := range cold {
+		ops = append(ops, intOp{Kind: clockpro.OpSet, Key: capacity + 1 + i, Value: i})
+	}
+	ops = append(ops, intOp{Kind: clockpro.OpGet, Key: kept})
+	for _, op := range ops[:len(ops)-1] {
+		sequential.Set(op.Key, op.Value)
+	}
+	mustMiss(t, sequential, kept, "insertions one at a time")
+	results := batched.Apply(ops)
+	if got, want := results[len(ops)-1], (intResult{Value: kept, OK: true}); got != want {
+		t.Errorf(
+			"key referenced by the batch was evicted"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			got, want,
+		)
+	}
+	if got, want := batched.Stats().Evictions, uint64(len(cold)); got != want {
+		t.Errorf(
+			"unexpected evictions"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			got, want,
+		)
+	}
+	if err := batched.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+}
+
+func opKindString(t *testing.T) {
+	t.Parallel()
+	for kind, want := range map[clockpro.OpKind]string{
+		clockpro.OpGet:    "get",
+		clockpro.OpSet:    "set",
+		clockpro.OpDelete: "delete",
+		0:                 "OpKind(0)",
+	} {
+		if got := kind.String(); got != want {
+			t.Errorf(
+				"unexpected string"+
+					"\n\tgot: %s"+
+					"\n\twant: %s",
+				got, want,
+			)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+// Package arenacache provides a CLOCK-Pro+ cache of byte slices
+// whose values are stored within a single preallocated arena.
+// The cache holds only the position of each value within the arena,
+// so values are not allocated individually, and since the arena
+// holds no pointers, it is not scanned by the garbage collector.
+package arenacache
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/djdv/go-clockpro"
+)
+
+type (
+	// Cache is a [clockpro.Cache] which copies values
+	// into an arena of a fixed size. Space is allocated
+	// from the first free span which fits, and freed
+	// spans are merged with their free neighbours.
+	// Cache is not safe for concurrent use.
+	Cache[Key comparable] struct {
+		clock *clockpro.Cache[Key, span]
+		arena []byte
+		free  []span // Ordered by offset.
+	}
+	// span is a range of bytes within the arena.
+	span struct {
+		offset, length int
+	}
+)
+
+// New constructs a [Cache] with an arena of size bytes,
+// which holds up to entries values. The amount of entries
+// must be valid for [clockpro.New].
+func New[Key comparable](size, entries int) (*Cache[Key], error) {
+	if size < 1 {
+		return nil, fmt.Errorf(
+			"%w: arena must be >=1 byte but %d was requested",
+			clockpro.ErrInvalidCapacity, size,
+		)
+	}
+	cache := &Cache[Key]{
+		arena: make([]byte, size),
+		free:  []span{{offset: 0, length: size}},
+	}
+	clock, err := clockpro.New(entries,
+		clockpro.WithFinalizer(func(_ Key, value span) {
+			cache.deallocate(value)
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	cache.clock = clock
+	return cache, nil
+}
+
+// Get is like [clockpro.Cache.Get],
+// but returns a copy of the value.
+func (c *Cache[Key]) Get(key Key) ([]byte, bool) {
+	return c.GetAppend(nil, key)
+}
+
+// GetAppend appends the value of key to dst,
+// if it is resident, and returns the extended slice.
+// Otherwise it returns dst and false.
+func (c *Cache[Key]) GetAppend(dst []byte, key Key) ([]byte, bool) {
+	value, ok := c.clock.Get(key)
+	if !ok {
+		return dst, false
+	}
+	return append(dst, c.bytes(value)...), true
+}
+
+// Set copies value into the arena for key, evicting
+// entries until there is a free span which fits it.
+// If value is larger than the arena, it is not stored,
+// any previous value for key is deleted, and Set returns false.
+func (c *Cache[Key]) Set(key Key, value []byte) bool {
+	if len(value) > len(c.arena) {
+		c.clock.Delete(key)
+		return false
+	}
+	allocated, ok := c.allocate(len(value))
+	for !ok && c.clock.EvictN(1) != 0 {
+		allocated, ok = c.allocate(len(value))
+	}
+	if !ok { // The previous value of key remains allocated.
+		c.clock.Delete(key)
+		allocated, _ = c.allocate(len(value))
+	}
+	copy(c.bytes(allocated), value)
+	c.clock.Set(key, allocated)
+	return true
+}
+
+// Delete is like [clockpro.Cache.Delete].
+func (c *Cache[Key]) Delete(key Key) bool { return c.clock.Delete(key) }
+
+// Len returns the amount of resident entries.
+func (c *Cache[_]) Len() int { return c.clock.Len() }
+
+// Size returns the size of the arena in bytes.
+func (c *Cache[_]) Size() int { return len(c.arena) }
+
+// Free returns the amount of bytes in the arena
+// which are not allocated to values.
+func (c *Cache[_]) Free() int {
+	var free int
+	for _, span := range c.free {
+		free += span.length
+	}
+	return free
+}
+
+// Stats is like [clockpro.Cache.Stats].
+func (c *Cache[_]) Stats() clockpro.Stats { return c.clock.Stats() }
+
+// CheckInvariants is like [clockpro.Cache.CheckInvariants],
+// and also checks that the spans of the resident values
+// and the free spans cover the arena, without overlapping.
+func (c *Cache[Key]) CheckInvariants() error {
+	if err := c.clock.CheckInvariants(); err != nil {
+		return err
+	}
+	spans := slices.Clone(c.free)
+	c.clock.Export(func(_ Key, value span, _ clockpro.EntryInfo) bool {
+		if value.length != 0 {
+			spans = append(spans, value)
+		}
+		return true
+	})
+	slices.SortFunc(spans, compareOffsets)
+	var end int
+	for _, span := range spans {
+		if span.offset != end || span.length < 1 {
+			return fmt.Errorf(
+				"%w: span [%d,%d) does not follow offset %d",
+				clockpro.ErrInvariant, span.offset, span.offset+span.length, end,
+			)
+		}
+		end += span.length
+	}
+	if end != len(c.arena) {
+		return fmt.Errorf(
+			"%w: spans end at %d rather than %d",
+			clockpro.ErrInvariant, end, len(c.arena),
+		)
+	}
+	return nil
+}
+
+func (c *Cache[_]) bytes(value span) []byte {
+	return c.arena[value.offset : value.offset+value.length]
+}
+
+// allocate returns the first span of length bytes which is free.
+func (c *Cache[_]) allocate(length int) (span, bool) {
+	if length == 0 {
+		return span{}, true
+	}
+	for i, free := range c.free {
+		if free.length < length {
+			continue
+		}
+		allocated := span{offset: free.offset, length: length}
+		if free.length == length {
+			c.free = slices.Delete(c.free, i, i+1)
+		} else {
+			c.free[i] = span{offset: free.offset + length, length: free.length - length}
+		}
+		return allocated, true
+	}
+	return span{}, false
+}
+
+// deallocate frees the span, merging it
+// with the free spans which it adjoins.
+func (c *Cache[_]) deallocate(freed span) {
+	if freed.length == 0 {
+		return
+	}
+	var (
+		i, _     = slices.BinarySearchFunc(c.free, freed, compareOffsets)
+		previous = i > 0 && c.free[i-1].offset+c.free[i-1].length == freed.offset
+		next     = i < len(c.free) && freed.offset+freed.length == c.free[i].offset
+	)
+	switch {
+	case previous && next:
+		c.free[i-1].length += freed.length + c.free[i].length
+		c.free = slices.Delete(c.free, i, i+1)
+	case previous:
+		c.free[i-1].length += freed.length
+	case next:
+		c.free[i] = span{offset: freed.offset, length: freed.length + c.free[i].length}
+	default:
+		c.free = slices.Insert(c.free, i, freed)
+	}
+}
+
+func compareOffsets(a, b span) int { return cmp.Compare(a.offset, b.offset) }
@@ -0,0 +1,125 @@
+package arenacache_test
+
+import (
+	"bytes"
+	"errors"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+	"github.com/djdv/go-clockpro/arenacache"
+)
+
+func TestCache(t *testing.T) {
+	t.Run("invalid", invalid)
+	t.Run("contents", contents)
+	t.Run("oversized", oversized)
+}
+
+const (
+	size    = 1 << 10
+	entries = 64
+)
+
+func newCache(tb testing.TB) *arenacache.Cache[int] {
+	tb.Helper()
+	cache, err := arenacache.New[int](size, entries)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return cache
+}
+
+func checkInvariants(tb testing.TB, cache *arenacache.Cache[int]) {
+	tb.Helper()
+	if err := cache.CheckInvariants(); err != nil {
+		tb.Fatal(err)
+	}
+}
+
+func invalid(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name          string
+		size, entries int
+	}{
+		{"size", 0, entries},
+		{"entries", size, 0},
+	} {
+		if _, err := arenacache.New[int](test.size, test.entries); !errors.Is(err, clockpro.ErrInvalidCapacity) {
+			t.Errorf(
+				"%s: expected error to match"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				test.name, err, clockpro.ErrInvalidCapacity,
+			)
+		}
+	}
+}
+
+func contents(t *testing.T) {
+	t.Parallel()
+	var (
+		cache  = newCache(t)
+		values = make(map[int][]byte)
+		random = rand.New(rand.NewPCG(1, 2))
+	)
+	for range 4096 {
+		key := random.IntN(entries * 2)
+		if random.IntN(8) == 0 {
+			cache.Delete(key)
+			delete(values, key)
+			checkInvariants(t, cache)
+			continue
+		}
+		value := bytes.Repeat([]byte{byte(key)}, random.IntN(size/8))
+		if !cache.Set(key, value) {
+			t.Fatalf("value of %d bytes was not stored", len(value))
+		}
+		values[key] = value
+		checkInvariants(t, cache)
+		for key, want := range values {
+			got, ok := cache.Get(key)
+			if !ok {
+				delete(values, key) // Evicted.
+				continue
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf(
+					"value of %d did not match"+
+						"\n\tgot: %v"+
+						"\n\twant: %v",
+					key, got, want,
+				)
+			}
+		}
+	}
+	for key := range values {
+		cache.Delete(key)
+	}
+	if got, want := cache.Free(), cache.Size(); got != want {
+		t.Errorf(
+			"arena was not freed"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			got, want,
+		)
+	}
+	checkInvariants(t, cache)
+}
+
+func oversized(t *testing.T) {
+	t.Parallel()
+	cache := newCache(t)
+	cache.Set(1, []byte("small"))
+	if cache.Set(1, make([]byte, size+1)) {
+		t.Error("oversized value was stored")
+	}
+	if _, ok := cache.Get(1); ok {
+		t.Error("previous value remained after oversized value")
+	}
+	if !cache.Set(2, make([]byte, size)) {
+		t.Error("value the size of the arena was not stored")
+	}
+	checkInvariants(t, cache)
+}
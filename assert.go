@@ -0,0 +1,51 @@
+package clockpro
+
+import "fmt"
+
+// WithAssertions enables the consistency checks
+// which are otherwise only compiled into caches
+// built with the clockpro_debug tag.
+// A failed check panics, rather than letting
+// a corrupted cache continue to operate.
+// See [WithAssertionHandler] to handle failures without panicking.
+func WithAssertions[Key comparable, Value any]() Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		set.assertions = true
+		return nil
+	}
+}
+
+// WithAssertionHandler enables consistency checks
+// like [WithAssertions], but rather than panicking,
+// a failed check calls handle with an error wrapping
+// [ErrInvariant], from within the offending operation.
+// The cache should be discarded once handle is called;
+// its later behavior is unspecified.
+// handle must not call back into the cache.
+func WithAssertionHandler[Key comparable, Value any](handle func(error)) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if handle == nil {
+			return fmt.Errorf("%w: assertion handler must not be nil", ErrInvalidOption)
+		}
+		set.assertions = true
+		set.assertionHandler = handle
+		return nil
+	}
+}
+
+// asserting reports whether consistency checks are enabled.
+func (c *Cache[_, _]) asserting() bool {
+	return debugging || c.assertions
+}
+
+func (c *Cache[_, _]) assert(cond bool, message string) {
+	if cond {
+		return
+	}
+	err := fmt.Errorf("%w: %s", ErrInvariant, message)
+	if handle := c.assertionHandler; handle != nil {
+		handle(err)
+		return
+	}
+	panic(err)
+}
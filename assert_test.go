@@ -0,0 +1,48 @@
+package clockpro_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestAssertionHandler(t *testing.T) {
+	t.Run("invalid", assertionHandlerInvalid)
+	t.Run("consistent", assertionHandlerConsistent)
+}
+
+func assertionHandlerInvalid(t *testing.T) {
+	t.Parallel()
+	_, err := clockpro.New(2, clockpro.WithAssertionHandler[int, int](nil))
+	if !errors.Is(err, clockpro.ErrInvalidOption) {
+		t.Errorf(
+			"expected error to match"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			err, clockpro.ErrInvalidOption)
+	}
+}
+
+func assertionHandlerConsistent(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity   = 16
+		operations = 1 << 12
+	)
+	var (
+		rng     = newReproducibleRNG()
+		ops     = make([]uint16, operations)
+		options = []clockpro.Option[int, int]{
+			clockpro.WithAssertionHandler[int, int](func(err error) {
+				t.Errorf("assertion failed for a consistent cache: %v", err)
+			}),
+		}
+	)
+	for i := range ops {
+		ops[i] = uint16(rng.Intn(1 << 16))
+	}
+	if err := checkProperties(capacity, ops, options); err != nil {
+		t.Error(err)
+	}
+}
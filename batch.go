@@ -0,0 +1,122 @@
+package clockpro
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type (
+	// Batcher coalesces the misses of concurrent loads of
+	// different keys from a [Synced] cache, which occur within
+	// a window of time, into a single call of a batch fetch
+	// function, such as for backends which serve many keys
+	// in one round trip. Loads of the same key within
+	// a window share its result.
+	// Constructed by [NewBatcher].
+	Batcher[Key comparable, Value any] struct {
+		cache   *Synced[Key, Value]
+		fetch   func(missing []Key) (map[Key]Value, error)
+		pending *batch[Key, Value]
+		window  time.Duration
+		mu      sync.Mutex
+	}
+	// batch holds the keys which missed within a window,
+	// and the result of fetching them once done is closed.
+	batch[Key comparable, Value any] struct {
+		requested map[Key]struct{}
+		keys      []Key
+		values    map[Key]Value
+		err       error
+		done      chan struct{}
+	}
+)
+
+// NewBatcher constructs a [Batcher] which loads from cache,
+// calling fetch with the keys which missed within each window.
+// Like [Cache.LoadMany], keys missing from the result of fetch
+// are not cached.
+func NewBatcher[Key comparable, Value any](
+	cache *Synced[Key, Value], window time.Duration,
+	fetch func(missing []Key) (map[Key]Value, error),
+) (*Batcher[Key, Value], error) {
+	if window <= 0 {
+		return nil, fmt.Errorf(
+			"%w: batch window must be >0 but %v was provided",
+			ErrInvalidOption, window,
+		)
+	}
+	if fetch == nil {
+		return nil, fmt.Errorf(
+			"%w: batch fetch function must not be nil",
+			ErrInvalidOption,
+		)
+	}
+	return &Batcher[Key, Value]{
+		cache:  cache,
+		fetch:  fetch,
+		window: window,
+	}, nil
+}
+
+// Load returns the cached value for key (if resident).
+// Otherwise, it waits for the window of the current batch
+// to end, and for the batch to be fetched, and returns the
+// value fetched for key. If fetch returns an error, it is
+// returned wrapped by [ErrFetchFailed]; if fetch omits key,
+// Load returns [ErrNotFound]. Like [Synced.Load], if another
+// caller stored a value for key while fetch was running,
+// that value is returned instead.
+func (b *Batcher[Key, Value]) Load(key Key) (Value, error) {
+	if value, ok := b.cache.Get(key); ok {
+		return value, nil
+	}
+	b.mu.Lock()
+	pending := b.pending
+	if pending == nil {
+		pending = &batch[Key, Value]{
+			requested: make(map[Key]struct{}),
+			done:      make(chan struct{}),
+		}
+		b.pending = pending
+		time.AfterFunc(b.window, func() { b.dispatch(pending) })
+	}
+	if _, ok := pending.requested[key]; !ok {
+		pending.requested[key] = struct{}{}
+		pending.keys = append(pending.keys, key)
+	}
+	b.mu.Unlock()
+	<-pending.done
+	var zero Value
+	if pending.err != nil {
+		return zero, pending.err
+	}
+	value, ok := pending.values[key]
+	if !ok {
+		return zero, ErrNotFound
+	}
+	return value, nil
+}
+
+// dispatch closes the window of pending,
+// and fetches and stores its keys.
+func (b *Batcher[Key, Value]) dispatch(pending *batch[Key, Value]) {
+	b.mu.Lock()
+	b.pending = nil
+	b.mu.Unlock()
+	defer close(pending.done)
+	fetched, err := b.fetch(pending.keys)
+	if err != nil {
+		pending.err = fetchError(err)
+		return
+	}
+	pending.values = make(map[Key]Value, len(pending.keys))
+	cache := b.cache
+	cache.lock()
+	defer cache.mu.Unlock()
+	for _, key := range pending.keys {
+		if value, ok := fetched[key]; ok {
+			pending.values[key], _ = cache.cache.setIfAbsent(key, value)
+		}
+	}
+}
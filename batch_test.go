@@ -0,0 +1,128 @@
+package clockpro_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestBatcher(t *testing.T) {
+	t.Run("invalid", batcherInvalid)
+	t.Run("coalesce", batcherCoalesce)
+	t.Run("errors", batcherErrors)
+}
+
+func batcherInvalid(t *testing.T) {
+	t.Parallel()
+	var (
+		synced = newSynced(t, 4)
+		fetch  = func([]int) (map[int]int, error) { return nil, nil }
+	)
+	if _, err := clockpro.NewBatcher(synced, 0, fetch); !errors.Is(err, clockpro.ErrInvalidOption) {
+		t.Errorf("unexpected error for window: %v", err)
+	}
+	if _, err := clockpro.NewBatcher(synced, time.Millisecond, nil); !errors.Is(err, clockpro.ErrInvalidOption) {
+		t.Errorf("unexpected error for fetch: %v", err)
+	}
+}
+
+func batcherCoalesce(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 16
+		keys     = 8
+	)
+	var (
+		synced  = newSynced(t, capacity)
+		calls   atomic.Int32
+		batcher = newBatcher(t, synced, func(missing []int) (map[int]int, error) {
+			calls.Add(1)
+			values := make(map[int]int, len(missing))
+			for _, key := range missing {
+				values[key] = key * 2
+			}
+			return values, nil
+		})
+		wg sync.WaitGroup
+	)
+	for key := range keys {
+		wg.Go(func() {
+			// Same key twice, to share its result.
+			for range 2 {
+				value, err := batcher.Load(key)
+				if err != nil {
+					t.Error(err)
+				}
+				if value != key*2 {
+					t.Errorf(
+						"unexpected value for key %d"+
+							"\n\tgot: %d"+
+							"\n\twant: %d",
+						key, value, key*2)
+				}
+			}
+		})
+	}
+	wg.Wait()
+	if got := calls.Load(); got >= keys {
+		t.Errorf(
+			"loads were not coalesced"+
+				"\n\tgot: %d fetches"+
+				"\n\twant: <%d",
+			got, keys)
+	}
+	if got := synced.Len(); got != keys {
+		t.Errorf(
+			"unexpected amount of entries stored"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			got, keys)
+	}
+	// Hits do not fetch.
+	before := calls.Load()
+	if _, err := batcher.Load(0); err != nil {
+		t.Fatal(err)
+	}
+	if got := calls.Load(); got != before {
+		t.Errorf("hit was fetched: %d fetches, want %d", got, before)
+	}
+}
+
+func batcherErrors(t *testing.T) {
+	t.Parallel()
+	var (
+		errBackend = errors.New("backend failed")
+		synced     = newSynced(t, 4)
+		failing    = newBatcher(t, synced, func([]int) (map[int]int, error) {
+			return nil, errBackend
+		})
+	)
+	if _, err := failing.Load(1); !errors.Is(err, clockpro.ErrFetchFailed) ||
+		!errors.Is(err, errBackend) {
+		t.Errorf("unexpected error from failed fetch: %v", err)
+	}
+	omitting := newBatcher(t, synced, func([]int) (map[int]int, error) {
+		return nil, nil
+	})
+	if _, err := omitting.Load(1); !errors.Is(err, clockpro.ErrNotFound) {
+		t.Errorf("unexpected error for omitted key: %v", err)
+	}
+	if synced.Len() != 0 {
+		t.Error("failed fetches were stored")
+	}
+}
+
+func newBatcher(tb testing.TB, synced *clockpro.Synced[int, int],
+	fetch func([]int) (map[int]int, error),
+) *clockpro.Batcher[int, int] {
+	tb.Helper()
+	batcher, err := clockpro.NewBatcher(synced, 50*time.Millisecond, fetch)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return batcher
+}
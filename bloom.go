@@ -0,0 +1,81 @@
+package clockpro
+
+import (
+	"hash/maphash"
+	"math/bits"
+)
+
+// bloom is a bloom filter of keys.
+type bloom[Key comparable] struct {
+	seed maphash.Seed
+	bits []uint64
+	mask uint64
+}
+
+const (
+	// bloomHashes is the amount of bits set per key.
+	bloomHashes = 4
+	// bloomBitsPerKey approximates a 2% false positive rate
+	// when the filter holds as many keys as it was sized for.
+	bloomBitsPerKey = 8
+)
+
+func newBloom[Key comparable](seed maphash.Seed, size int) bloom[Key] {
+	const minimumBits = 64
+	var (
+		length = max(size*bloomBitsPerKey, minimumBits)
+		count  = 1 << bits.Len(uint(length-1)) // Power of two for cheap masking.
+	)
+	return bloom[Key]{
+		seed: seed,
+		bits: make([]uint64, count/64),
+		mask: uint64(count - 1),
+	}
+}
+
+// insert adds key to the filter,
+// and reports whether it was (probably) present.
+func (bl *bloom[Key]) insert(key Key) bool {
+	present := true
+	bl.each(key, func(word *uint64, flag uint64) {
+		if *word&flag == 0 {
+			present = false
+			*word |= flag
+		}
+	})
+	return present
+}
+
+// contains reports whether key is (probably) present.
+func (bl *bloom[Key]) contains(key Key) bool {
+	present := true
+	bl.each(key, func(word *uint64, flag uint64) {
+		present = present && *word&flag != 0
+	})
+	return present
+}
+
+func (bl *bloom[Key]) each(key Key, fn func(word *uint64, flag uint64)) {
+	hash := maphash.Comparable(bl.seed, key)
+	for range bloomHashes {
+		hash = mix(hash)
+		bit := hash & bl.mask
+		fn(&bl.bits[bit/64], uint64(1)<<(bit%64))
+	}
+}
+
+// mix derives a new hash from hash (SplitMix64),
+// so that bit positions are independent of each other.
+func mix(hash uint64) uint64 {
+	hash += 0x9e3779b97f4a7c15
+	hash = (hash ^ hash>>30) * 0xbf58476d1ce4e5b9
+	hash = (hash ^ hash>>27) * 0x94d049bb133111eb
+	return hash ^ hash>>31
+}
+
+func (bl *bloom[_]) reset() { clear(bl.bits) }
+
+func (bl bloom[Key]) clone() bloom[Key] {
+	bl.bits = append([]uint64(nil), bl.bits...)
+	return bl
+}
@@ -0,0 +1,38 @@
+package clockpro
+
+import "fmt"
+
+// WithColdTargetBounds bounds the cold target
+// to the range [minimum, maximum], expressed
+// as fractions of the cache's capacity.
+// By default, the cold target is bound to [1, capacity/2].
+// Regardless of the fractions, at least one page
+// is targeted for each of the hot and cold regions.
+func WithColdTargetBounds[Key comparable, Value any](minimum, maximum float64) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if !(minimum >= 0 && minimum <= maximum && maximum <= 1) {
+			return fmt.Errorf(
+				"%w: cold target bounds must satisfy 0 <= minimum <= maximum <= 1"+
+					" but [%f, %f] was provided",
+				ErrInvalidOption, minimum, maximum,
+			)
+		}
+		set.coldRatios = &[2]float64{minimum, maximum}
+		return nil
+	}
+}
+
+// coldBounds returns the range of the cold target
+// for a cache of capacity.
+func (set *settings[_, _]) coldBounds(capacity int) (minimum, maximum int) {
+	if set.coldRatios == nil {
+		return 1, capacity / 2
+	}
+	var (
+		ratios = set.coldRatios
+		size   = float64(capacity)
+	)
+	minimum = min(max(int(size*ratios[0]), 1), capacity-1)
+	maximum = min(max(int(size*ratios[1]), minimum), capacity-1)
+	return minimum, maximum
+}
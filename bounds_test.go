@@ -0,0 +1,76 @@
+package clockpro_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestColdTargetBounds(t *testing.T) {
+	t.Run("invalid", coldBoundsInvalid)
+	t.Run("bound", coldBoundsBound)
+}
+
+func coldBoundsInvalid(t *testing.T) {
+	t.Parallel()
+	for _, bounds := range [][2]float64{
+		{-0.1, 0.5},
+		{0.6, 0.5},
+		{0.5, 1.1},
+	} {
+		_, err := clockpro.New(2, clockpro.WithColdTargetBounds[int, int](bounds[0], bounds[1]))
+		if !errors.Is(err, clockpro.ErrInvalidOption) {
+			t.Errorf(
+				"expected error to match for bounds %v"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				bounds, err, clockpro.ErrInvalidOption)
+		}
+	}
+}
+
+func coldBoundsBound(t *testing.T) {
+	t.Parallel()
+	const capacity = 16
+	for _, test := range []struct {
+		options  []clockpro.Option[int, int]
+		min, max int
+	}{
+		{nil, 1, capacity / 2},
+		{[]clockpro.Option[int, int]{
+			clockpro.WithColdTargetBounds[int, int](0.5, 0.9),
+		}, capacity / 2, capacity * 9 / 10},
+	} {
+		t.Run(fmt.Sprintf("[%d, %d]", test.min, test.max), func(t *testing.T) {
+			t.Parallel()
+			var (
+				lowest, highest = capacity, 0
+				recorder        = func(sample clockpro.AdaptationSample) {
+					lowest = min(lowest, sample.ColdTarget)
+					highest = max(highest, sample.ColdTarget)
+				}
+				options    = append(test.options, clockpro.WithAdaptationRecorder[int, int](recorder))
+				cache, err = clockpro.New(capacity, options...)
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rng := newReproducibleRNG()
+			for range capacity * 256 {
+				key := rng.Intn(capacity * 2)
+				if _, ok := cache.Get(key); !ok {
+					cache.Set(key, key)
+				}
+			}
+			if lowest < test.min || highest != test.max {
+				t.Errorf(
+					"unexpected cold target range"+
+						"\n\tgot: [%d, %d]"+
+						"\n\twant: [>=%d, %d]",
+					lowest, highest, test.min, test.max)
+			}
+		})
+	}
+}
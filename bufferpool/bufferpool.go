@@ -0,0 +1,197 @@
+// Package bufferpool provides a pool of fixed-size
+// block buffers, such as for the pages of a file or disk,
+// which are replaced by the CLOCK-Pro+ policy.
+package bufferpool
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/djdv/go-clockpro"
+)
+
+type (
+	// Pool holds up to a capacity of blocks in memory,
+	// reading them on demand and writing modified blocks
+	// back before their buffers are reused.
+	// Pool is safe for concurrent use, but reads and
+	// writes are performed while the pool is locked.
+	Pool struct {
+		clock *clockpro.Cache[uint64, *Frame]
+		read,
+		write func(id uint64, block []byte) error
+		// detached holds the frames which were evicted
+		// while pinned, or which could not be written,
+		// until they are unpinned or written.
+		detached  map[uint64]*Frame
+		free      [][]byte
+		errs      []error
+		blockSize int
+		mu        sync.Mutex
+	}
+	// Frame holds the buffer of a pinned block.
+	Frame struct {
+		data  []byte
+		id    uint64
+		pins  int
+		dirty bool
+	}
+)
+
+// New constructs a [Pool] of capacity blocks of blockSize bytes.
+// Capacity must be valid for [clockpro.New].
+// read fills a buffer with the block's contents,
+// and write stores the contents of a modified block.
+func New(capacity, blockSize int, read, write func(id uint64, block []byte) error) (*Pool, error) {
+	if blockSize < 1 {
+		return nil, fmt.Errorf(
+			"%w: block size must be >=1 but %d was requested",
+			clockpro.ErrInvalidSize, blockSize,
+		)
+	}
+	pool := &Pool{
+		read:      read,
+		write:     write,
+		detached:  make(map[uint64]*Frame),
+		blockSize: blockSize,
+	}
+	clock, err := clockpro.New(capacity,
+		clockpro.WithIntegerIndex[uint64, *Frame](),
+		clockpro.WithFinalizer(func(_ uint64, frame *Frame) {
+			pool.evicted(frame)
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	pool.clock = clock
+	return pool, nil
+}
+
+// Pin returns the frame of block id, reading it
+// if it is not in memory, and prevents its buffer
+// from being reused until it is unpinned.
+// Every call must be paired with a call to [Pool.Unpin].
+// The error of writing back evicted blocks, if any,
+// is returned along with the frame.
+func (p *Pool) Pin(id uint64) (*Frame, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	frame, ok := p.clock.Get(id)
+	if !ok {
+		if frame, ok = p.detached[id]; ok {
+			delete(p.detached, id)
+		} else {
+			frame = &Frame{id: id, data: p.buffer()}
+			if err := p.read(id, frame.data); err != nil {
+				p.free = append(p.free, frame.data)
+				return nil, fmt.Errorf("reading block %d: %w", id, err)
+			}
+		}
+		frame.pins++ // Pinned before it may be evicted.
+		p.clock.Set(id, frame)
+	} else {
+		frame.pins++
+	}
+	err := errors.Join(p.errs...)
+	p.errs = nil
+	return frame, err
+}
+
+// Unpin releases a pin of frame, and marks it as
+// modified if dirty is true. Once a frame is unpinned,
+// it must not be used.
+func (p *Pool) Unpin(frame *Frame, dirty bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if frame.pins == 0 {
+		panic("bufferpool: frame was not pinned")
+	}
+	frame.pins--
+	frame.dirty = frame.dirty || dirty
+	if frame.pins == 0 && p.detached[frame.id] == frame {
+		delete(p.detached, frame.id)
+		p.evicted(frame)
+	}
+}
+
+// Flush writes every modified block which is not pinned,
+// and returns the errors of any writes which failed.
+func (p *Pool) Flush() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	errs := p.errs
+	p.errs = nil
+	writeBack := func(frame *Frame) {
+		if frame.dirty && frame.pins == 0 {
+			errs = append(errs, p.writeBack(frame))
+		}
+	}
+	p.clock.Export(func(_ uint64, frame *Frame, _ clockpro.EntryInfo) bool {
+		writeBack(frame)
+		return true
+	})
+	for id, frame := range p.detached {
+		if writeBack(frame); !frame.dirty && frame.pins == 0 {
+			delete(p.detached, id)
+			p.free = append(p.free, frame.data)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Len returns the amount of blocks in memory.
+func (p *Pool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.clock.Len() + len(p.detached)
+}
+
+// Stats is like [clockpro.Cache.Stats].
+func (p *Pool) Stats() clockpro.Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.clock.Stats()
+}
+
+// evicted writes back the frame if it was modified,
+// and recycles its buffer, unless it is pinned
+// or could not be written; then it is detached.
+func (p *Pool) evicted(frame *Frame) {
+	if frame.pins == 0 && frame.dirty {
+		if err := p.writeBack(frame); err != nil {
+			p.errs = append(p.errs, err)
+		}
+	}
+	if frame.pins != 0 || frame.dirty {
+		p.detached[frame.id] = frame
+		return
+	}
+	p.free = append(p.free, frame.data)
+}
+
+func (p *Pool) writeBack(frame *Frame) error {
+	if err := p.write(frame.id, frame.data); err != nil {
+		return fmt.Errorf("writing block %d: %w", frame.id, err)
+	}
+	frame.dirty = false
+	return nil
+}
+
+func (p *Pool) buffer() []byte {
+	if last := len(p.free) - 1; last >= 0 {
+		buffer := p.free[last]
+		p.free = p.free[:last]
+		return buffer
+	}
+	return make([]byte, p.blockSize)
+}
+
+// ID returns the identifier of the frame's block.
+func (f *Frame) ID() uint64 { return f.id }
+
+// Data returns the buffer of the frame's block,
+// which may be modified while the frame is pinned.
+// Modifications must be reported to [Pool.Unpin].
+func (f *Frame) Data() []byte { return f.data }
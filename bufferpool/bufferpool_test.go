@@ -0,0 +1,210 @@
+package bufferpool_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+	"github.com/djdv/go-clockpro/bufferpool"
+)
+
+const (
+	capacity  = 4
+	blockSize = 8
+	blocks    = capacity * 4
+)
+
+// storage is a device of blocks, which initially
+// hold their own identifier, with injectable failures.
+type storage struct {
+	blocks map[uint64][]byte
+	reads  int
+	failReads,
+	failWrites bool
+}
+
+var errDevice = errors.New("device failed")
+
+func (st *storage) read(id uint64, block []byte) error {
+	if st.failReads {
+		return errDevice
+	}
+	st.reads++
+	if data, ok := st.blocks[id]; ok {
+		copy(block, data)
+		return nil
+	}
+	binary.LittleEndian.PutUint64(block, id)
+	return nil
+}
+
+func (st *storage) write(id uint64, block []byte) error {
+	if st.failWrites {
+		return errDevice
+	}
+	st.blocks[id] = append([]byte(nil), block...)
+	return nil
+}
+
+func newPool(t *testing.T) (*bufferpool.Pool, *storage) {
+	t.Helper()
+	st := &storage{blocks: make(map[uint64][]byte)}
+	pool, err := bufferpool.New(capacity, blockSize, st.read, st.write)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pool, st
+}
+
+func TestPool(t *testing.T) {
+	t.Run("invalid", invalid)
+	t.Run("write back", writeBack)
+	t.Run("pinned", pinned)
+	t.Run("failures", failures)
+}
+
+func invalid(t *testing.T) {
+	t.Parallel()
+	st := &storage{}
+	for _, test := range []struct {
+		capacity, blockSize int
+		want                error
+	}{
+		{capacity, 0, clockpro.ErrInvalidSize},
+		{0, blockSize, clockpro.ErrInvalidCapacity},
+	} {
+		_, err := bufferpool.New(test.capacity, test.blockSize, st.read, st.write)
+		if !errors.Is(err, test.want) {
+			t.Errorf(
+				"expected error to match"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				err, test.want,
+			)
+		}
+	}
+}
+
+func pin(t *testing.T, pool *bufferpool.Pool, id uint64) *bufferpool.Frame {
+	t.Helper()
+	frame, err := pool.Pin(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return frame
+}
+
+// writeBack adds 1 to the contents of each block,
+// for every round, and checks they were written back.
+func writeBack(t *testing.T) {
+	t.Parallel()
+	const rounds = 3
+	pool, st := newPool(t)
+	for range rounds {
+		for id := range uint64(blocks) {
+			frame := pin(t, pool, id)
+			data := frame.Data()
+			binary.LittleEndian.PutUint64(data, binary.LittleEndian.Uint64(data)+1)
+			pool.Unpin(frame, true)
+		}
+	}
+	if err := pool.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	for id := range uint64(blocks) {
+		if got, want := binary.LittleEndian.Uint64(st.blocks[id]), id+rounds; got != want {
+			t.Errorf(
+				"unexpected contents of block %d"+
+					"\n\tgot: %d"+
+					"\n\twant: %d",
+				id, got, want,
+			)
+		}
+	}
+	if length := pool.Len(); length > capacity {
+		t.Errorf(
+			"pool exceeded capacity"+
+				"\n\tgot: %d"+
+				"\n\twant: <=%d",
+			length, capacity,
+		)
+	}
+}
+
+func pinned(t *testing.T) {
+	t.Parallel()
+	pool, st := newPool(t)
+	held := pin(t, pool, 0)
+	copy(held.Data(), "modified")
+	for id := range uint64(blocks) {
+		pool.Unpin(pin(t, pool, id+1), false)
+	}
+	if string(held.Data()) != "modified" {
+		t.Errorf("pinned buffer was reused: %q", held.Data())
+	}
+	reads := st.reads
+	if again := pin(t, pool, 0); again != held {
+		t.Error("pinned block was read again")
+	} else {
+		pool.Unpin(again, false)
+	}
+	pool.Unpin(held, true)
+	if st.reads != reads {
+		t.Errorf("unexpected reads: %d", st.reads-reads)
+	}
+	if err := pool.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(st.blocks[0]); got != "modified" {
+		t.Errorf("pinned block was not written back: %q", got)
+	}
+}
+
+func failures(t *testing.T) {
+	t.Parallel()
+	pool, st := newPool(t)
+	st.failReads = true
+	if _, err := pool.Pin(0); !errors.Is(err, errDevice) {
+		t.Errorf("expected read error, got: %v", err)
+	}
+	st.failReads = false
+	st.failWrites = true
+	var writeErr error
+	for id := range uint64(blocks) {
+		frame, err := pool.Pin(id)
+		if err != nil {
+			writeErr = err
+		}
+		binary.LittleEndian.PutUint64(frame.Data(), id*2)
+		pool.Unpin(frame, true)
+	}
+	if !errors.Is(writeErr, errDevice) {
+		t.Errorf("expected write error, got: %v", writeErr)
+	}
+	if err := pool.Flush(); !errors.Is(err, errDevice) {
+		t.Errorf("expected write error from flush, got: %v", err)
+	}
+	st.failWrites = false
+	if err := pool.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	for id := range uint64(blocks) {
+		if got, want := binary.LittleEndian.Uint64(st.blocks[id]), id*2; got != want {
+			t.Errorf(
+				"failed write of block %d was not retried"+
+					"\n\tgot: %d"+
+					"\n\twant: %d",
+				id, got, want,
+			)
+		}
+	}
+	if length := pool.Len(); length > capacity {
+		t.Errorf(
+			"detached blocks were retained after they were written"+
+				"\n\tgot: %d"+
+				"\n\twant: <=%d",
+			length, capacity,
+		)
+	}
+}
@@ -0,0 +1,125 @@
+// Package bytecache provides a CLOCK-Pro+ cache of byte slices
+// keyed by strings, whose capacity is expressed in bytes,
+// such as for caching chunks of content.
+package bytecache
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/djdv/go-clockpro"
+)
+
+// Cache is a [clockpro.Cache] which holds up to
+// a capacity of bytes, counting the length of
+// each resident key and value.
+// The cache owns copies of stored values.
+// Cache is not safe for concurrent use.
+type Cache struct {
+	clock    *clockpro.Cache[string, []byte]
+	size     int
+	capacity int
+}
+
+// New constructs a [Cache] which holds up to capacity bytes,
+// within up to entries entries. The amount of entries
+// must be valid for [clockpro.New], and bounds
+// the metadata that the policy retains.
+func New(capacity, entries int) (*Cache, error) {
+	if capacity < 1 {
+		return nil, fmt.Errorf(
+			"%w: must be >=1 byte but %d was requested",
+			clockpro.ErrInvalidCapacity, capacity,
+		)
+	}
+	var (
+		cache = &Cache{capacity: capacity}
+		hooks = clockpro.Hooks[string, []byte]{
+			OnRelease: func(key string, value []byte, _ clockpro.EntryInfo) {
+				cache.size -= sizeOf(key, value)
+			},
+		}
+		clock, err = clockpro.New(entries, clockpro.WithHooks(hooks))
+	)
+	if err != nil {
+		return nil, err
+	}
+	cache.clock = clock
+	return cache, nil
+}
+
+func sizeOf(key string, value []byte) int { return len(key) + len(value) }
+
+// Get is like [clockpro.Cache.Get],
+// but returns a copy of the value.
+func (c *Cache) Get(key string) ([]byte, bool) {
+	return c.GetAppend(nil, key)
+}
+
+// GetAppend appends the value of key to dst,
+// if it is resident, and returns the extended slice,
+// without allocating if dst has enough capacity.
+// Otherwise it returns dst and false.
+func (c *Cache) GetAppend(dst []byte, key string) ([]byte, bool) {
+	value, ok := c.clock.Get(key)
+	if !ok {
+		return dst, false
+	}
+	return append(dst, value...), true
+}
+
+// Set stores a copy of value for key, evicting
+// entries until the cache is within its capacity.
+// If key and value are larger than the capacity,
+// the value is not stored, and any previous value
+// for key is deleted.
+func (c *Cache) Set(key string, value []byte) {
+	size := sizeOf(key, value)
+	if size > c.capacity {
+		c.clock.Delete(key)
+		return
+	}
+	c.clock.Set(key, bytes.Clone(value))
+	c.size += size
+	for c.size > c.capacity {
+		if c.clock.EvictN(1) == 0 {
+			break
+		}
+	}
+}
+
+// Delete is like [clockpro.Cache.Delete].
+func (c *Cache) Delete(key string) bool { return c.clock.Delete(key) }
+
+// Len returns the amount of resident entries.
+func (c *Cache) Len() int { return c.clock.Len() }
+
+// Size returns the amount of bytes held by resident entries.
+func (c *Cache) Size() int { return c.size }
+
+// Capacity returns the amount of bytes
+// that the cache was constructed with.
+func (c *Cache) Capacity() int { return c.capacity }
+
+// Stats is like [clockpro.Cache.Stats].
+func (c *Cache) Stats() clockpro.Stats { return c.clock.Stats() }
+
+// CheckInvariants is like [clockpro.Cache.CheckInvariants],
+// and also checks the size of the resident entries.
+func (c *Cache) CheckInvariants() error {
+	if err := c.clock.CheckInvariants(); err != nil {
+		return err
+	}
+	var size int
+	c.clock.Export(func(key string, value []byte, _ clockpro.EntryInfo) bool {
+		size += sizeOf(key, value)
+		return true
+	})
+	if size != c.size || size > c.capacity {
+		return fmt.Errorf(
+			"%w: resident entries hold %d bytes, but %d (<=%d) are counted",
+			clockpro.ErrInvariant, size, c.size, c.capacity,
+		)
+	}
+	return nil
+}
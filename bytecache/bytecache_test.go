@@ -0,0 +1,152 @@
+package bytecache_test
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+	"github.com/djdv/go-clockpro/bytecache"
+)
+
+func TestCache(t *testing.T) {
+	t.Run("invalid", invalid)
+	t.Run("budget", budget)
+	t.Run("update", update)
+	t.Run("oversized", oversized)
+	t.Run("get append", getAppend)
+}
+
+const (
+	capacity = 1 << 10
+	entries  = 64
+)
+
+func newCache(tb testing.TB) *bytecache.Cache {
+	tb.Helper()
+	cache, err := bytecache.New(capacity, entries)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return cache
+}
+
+func checkInvariants(tb testing.TB, cache *bytecache.Cache) {
+	tb.Helper()
+	if err := cache.CheckInvariants(); err != nil {
+		tb.Fatal(err)
+	}
+}
+
+func invalid(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name              string
+		capacity, entries int
+	}{
+		{"bytes", 0, entries},
+		{"entries", capacity, 0},
+	} {
+		if _, err := bytecache.New(test.capacity, test.entries); !errors.Is(err, clockpro.ErrInvalidCapacity) {
+			t.Errorf(
+				"%s: expected error to match"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				test.name, err, clockpro.ErrInvalidCapacity,
+			)
+		}
+	}
+}
+
+func budget(t *testing.T) {
+	t.Parallel()
+	cache := newCache(t)
+	for i := range entries * 4 {
+		key := strconv.Itoa(i)
+		cache.Set(key, bytes.Repeat([]byte{byte(i)}, i%100))
+		checkInvariants(t, cache)
+	}
+	if size := cache.Size(); size > capacity || size < capacity/2 {
+		t.Errorf(
+			"unexpected size"+
+				"\n\tgot: %d"+
+				"\n\twant: [%d,%d]",
+			size, capacity/2, capacity,
+		)
+	}
+}
+
+func update(t *testing.T) {
+	t.Parallel()
+	cache := newCache(t)
+	cache.Set("key", make([]byte, 100))
+	cache.Set("key", make([]byte, 10))
+	checkInvariants(t, cache)
+	if size, want := cache.Size(), len("key")+10; size != want {
+		t.Errorf(
+			"unexpected size after update"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			size, want,
+		)
+	}
+	cache.Delete("key")
+	checkInvariants(t, cache)
+	if size := cache.Size(); size != 0 {
+		t.Errorf(
+			"unexpected size after delete"+
+				"\n\tgot: %d"+
+				"\n\twant: 0",
+			size,
+		)
+	}
+}
+
+func oversized(t *testing.T) {
+	t.Parallel()
+	cache := newCache(t)
+	cache.Set("key", []byte("small"))
+	cache.Set("key", make([]byte, capacity))
+	checkInvariants(t, cache)
+	if value, ok := cache.Get("key"); ok {
+		t.Errorf("oversized value was stored: %d bytes", len(value))
+	}
+}
+
+func getAppend(t *testing.T) {
+	var (
+		cache = newCache(t)
+		value = []byte("value")
+	)
+	cache.Set("key", value)
+	value[0] = 'V' // The cache must own a copy.
+	dst := make([]byte, 0, 64)
+	dst = append(dst, "prefix:"...)
+	got, ok := cache.GetAppend(dst, "key")
+	if want := "prefix:value"; !ok || string(got) != want {
+		t.Errorf(
+			"unexpected value"+
+				"\n\tgot: %q, %t"+
+				"\n\twant: %q, true",
+			got, ok, want,
+		)
+	}
+	if &got[0] != &dst[0] {
+		t.Error("value was not appended in place")
+	}
+	if got, ok := cache.GetAppend(dst, "missing"); ok || len(got) != len(dst) {
+		t.Errorf("unexpected value for missing key: %q", got)
+	}
+	allocations := testing.AllocsPerRun(16, func() {
+		cache.GetAppend(dst, "key")
+	})
+	if allocations != 0 {
+		t.Errorf(
+			"unexpected allocations"+
+				"\n\tgot: %v"+
+				"\n\twant: 0",
+			allocations,
+		)
+	}
+}
@@ -0,0 +1,30 @@
+package clockpro
+
+import (
+	"fmt"
+	"math"
+)
+
+// WithSecondChances limits the amount of times
+// a referenced cold page outside of the stack
+// is restacked by the cold hand, rather than evicted.
+// By default, such pages are restacked every time they
+// are referenced, which gives them another test period.
+// Once a page has exhausted its chances, its reference
+// is ignored by the cold hand, and it is evicted next.
+// Chances are restored when a page is promoted.
+// The amount must be within [0, 255].
+// Caches which limit chances cannot be checked by [Replay].
+func WithSecondChances[Key comparable, Value any](chances int) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if chances < 0 || chances > math.MaxUint8 {
+			return fmt.Errorf(
+				"%w: second chances must be within [0, %d] but %d was provided",
+				ErrInvalidOption, math.MaxUint8, chances,
+			)
+		}
+		set.secondChances = chances
+		set.limitChances = true
+		return nil
+	}
+}
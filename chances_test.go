@@ -0,0 +1,60 @@
+package clockpro_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestSecondChances(t *testing.T) {
+	t.Run("invalid", secondChancesInvalid)
+	t.Run("restack", secondChancesRestack)
+}
+
+func secondChancesInvalid(t *testing.T) {
+	t.Parallel()
+	for _, chances := range []int{-1, 256} {
+		_, err := clockpro.New(2, clockpro.WithSecondChances[int, int](chances))
+		if !errors.Is(err, clockpro.ErrInvalidOption) {
+			t.Errorf(
+				"expected error to match for %d chances"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				chances, err, clockpro.ErrInvalidOption)
+		}
+	}
+}
+
+func secondChancesRestack(t *testing.T) {
+	t.Parallel()
+	const capacity = 4
+	for _, test := range []struct {
+		name    string
+		options []clockpro.Option[int, int]
+		evicted int
+	}{
+		{"unlimited", nil, 2},
+		{"none", []clockpro.Option[int, int]{
+			clockpro.WithSecondChances[int, int](0),
+		}, 1},
+	} {
+		options := append(test.options,
+			// Insert every page as cold, outside of the stack.
+			clockpro.WithScanDetection[int, int](1),
+		)
+		cache, err := clockpro.New(capacity, options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addIncrementingInts(cache, capacity)
+		mustGet(t, cache, 1)
+		cache.Set(capacity+1, capacity+1)
+		if keys := slices.Collect(cache.Keys()); slices.Contains(keys, test.evicted) {
+			t.Errorf(
+				"%s: expected %d to be evicted, residents: %v",
+				test.name, test.evicted, keys)
+		}
+	}
+}
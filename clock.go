@@ -1,24 +1,72 @@
 package clockpro
 
 import (
+	"cmp"
+	"context"
 	"iter"
+	"math"
+	"slices"
+	"time"
 
-	"github.com/djdv/go-clockpro/internal/ring"
+	"github.com/djdv/go-clockpro/internal/list"
 )
 
 type (
-	page[Key comparable, Value any] = ring.Ring[Key, Value]
-	metadata[Key comparable]        = ring.Metadata[Key]
+	page[Key comparable, Value any] = list.Element[Key, Value]
+	metadata[Key comparable]        = list.Metadata[Key]
 	// Cache utilizes the Cache-Pro+ replacement algorithm.
-	// Concurrent access must be guarded by the caller.
+	// Concurrent access must be guarded by the caller,
+	// or see [Synced], [Sharded], [Striped], and [Actor].
 	// Constructed by [New].
 	Cache[Key comparable, Value any] struct {
-		index map[Key]*page[Key, Value]
-		hot, cold,
-		test, lru *page[Key, Value]
+		index pageIndex[Key, Value]
+		// clock holds every page, from the least
+		// to the most recently inserted (the "lru").
+		clock           list.List[Key, Value]
+		hot, cold, test *page[Key, Value]
 		capacity, coldTarget, hotTarget,
+		coldMinimum, coldMaximum,
 		coldCount, hotCount, testCount,
-		demotions int
+		demotions, scanRun int
+		weight     int // See [WithMaxWeight].
+		operations uint64
+		// modifications counts changes to the clock
+		// and to the residency of its pages.
+		// Iterators use it to detect modifications by yield.
+		modifications uint64
+		meanCost      float64
+		// adaptationOrigin is the cold target as of
+		// adaptationStart, the operation count at which
+		// the current window of [WithAdaptationRateLimit]
+		// started, or 0 before the first window.
+		adaptationOrigin int
+		adaptationStart  uint64
+		// tuned holds the reuse distances as of
+		// the last call to [Cache.TuneCapacity].
+		tuned    Histogram
+		expiry   expirations[Key]
+		versions versions[Key]             // See [Cache.SetVersioned].
+		groups   entryGroups[Key]          // See [Cache.SetTagged].
+		tags     map[Key]any               // See [Cache.SetWithTag].
+		restored *restoredKeys[Key, Value] // See [Cache.LoadMeta].
+		views    *viewLog[Key, Value]      // See [Cache.Snapshot].
+		ghosts   *ghostSketch[Key]
+		sampled  *residentSet[Key, Value]
+		stats    statistics
+		settings[Key, Value]
+		// batching is set by [Cache.Apply], so that
+		// insertions do not evict expired entries.
+		batching bool
+		// paused is set while adaptation of the cold
+		// target is paused. See [Cache.PauseAdaptation].
+		paused bool
+		// returning is set while a value which
+		// is returned to the caller is released.
+		returning bool
+		// returnEvicted is set while the first page
+		// evicted by a modification is returned to the caller,
+		// and is cleared once that page is evicted.
+		returnEvicted *setResult[Key, Value]
 	}
 )
 
@@ -27,83 +75,511 @@ const MinimumCapacity = 2
 
 // New creates a [Cache] with the given capacity.
 // Capacity must be at least [MinimumCapacity] to allow both hot and cold cache pages.
-func New[Key comparable, Value any](capacity int) (*Cache[Key, Value], error) {
+func New[Key comparable, Value any](capacity int, options ...Option[Key, Value]) (*Cache[Key, Value], error) {
 	const minimumColdRatio = 0.01
 	if capacity < MinimumCapacity {
 		return nil, minCapacityError(capacity)
 	}
-	var ( // Range: [1,half-capacity]
-		coldInitial = max(float64(capacity)*minimumColdRatio, 1)
-		coldTarget  = min(int(coldInitial), capacity/2)
-		hotTarget   = capacity - coldTarget
+	settings, err := applyOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	if recording := settings.recording; recording != nil {
+		recording.Capacity = capacity
+	}
+	var ( // Range: [coldMinimum,coldMaximum]
+		coldMinimum, coldMaximum = settings.coldBounds(capacity)
+		coldInitial              = max(float64(capacity)*minimumColdRatio, 1)
+		coldTarget               = min(max(int(coldInitial), coldMinimum), coldMaximum)
+		hotTarget                = capacity - coldTarget
 	)
-	return &Cache[Key, Value]{
-		capacity:   capacity,
-		index:      make(map[Key]*page[Key, Value], hotTarget),
-		coldTarget: coldTarget,
-		hotTarget:  hotTarget,
-	}, nil
+	cache := &Cache[Key, Value]{
+		capacity:    capacity,
+		coldTarget:  coldTarget,
+		hotTarget:   hotTarget,
+		coldMinimum: coldMinimum,
+		coldMaximum: coldMaximum,
+		settings:    settings,
+	}
+	cache.index = newPageIndex(&cache.clock, settings.indexHash, hotTarget)
+	cache.expiry.idle = int64(settings.maxIdle)
+	if settings.ghostSketch {
+		cache.ghosts = newGhostSketch[Key](capacity)
+	}
+	if settings.sampling {
+		cache.sampled = &residentSet[Key, Value]{clock: &cache.clock}
+	}
+	if releaser := settings.releaser; releaser != nil {
+		releaser.release = settings.hooks.OnRelease
+		releaser.finalize = settings.finalizer
+	}
+	created := cache.now()
+	cache.stats.created = created
+	cache.stats.resetAt = created
+	return cache, nil
 }
 
 // Load returns the cached value for key (if resident). Otherwise, it calls fetch,
 // inserts and returns the value on success.
-// If fetch returns an error, the value is not cached.
+// If fetch returns an error, the value is not cached,
+// and the error is returned wrapped by [ErrFetchFailed].
+// fetch is called between modifications of the cache,
+// so it may use the cache, such as to load other keys.
+// If fetch sets key itself, that value is retained and returned.
 func (c *Cache[Key, Value]) Load(key Key, fetch func() (Value, error)) (Value, error) {
-	if value, hadPage := c.Get(key); hadPage {
-		return value, nil
+	value, _, err := c.load(context.Background(), key, fetcher[Value]{plain: fetch})
+	return value, err
+}
+
+// LoadContext is like [Cache.Load], but fetch is called
+// with ctx, or the context returned by [WithLoadSpans].
+func (c *Cache[Key, Value]) LoadContext(ctx context.Context, key Key, fetch func(context.Context) (Value, error)) (Value, error) {
+	value, _, err := c.load(ctx, key, fetcher[Value]{withContext: fetch})
+	return value, err
+}
+
+// LoadReport is like [Cache.Load] but also returns
+// true if the value was resident, or false if it was fetched.
+func (c *Cache[Key, Value]) LoadReport(key Key, fetch func() (Value, error)) (value Value, hit bool, err error) {
+	return c.load(context.Background(), key, fetcher[Value]{plain: fetch})
+}
+
+func (c *Cache[Key, Value]) load(ctx context.Context, key Key, fetch fetcher[Value]) (value Value, hit bool, err error) {
+	ctx, end := c.startLoad(ctx, key)
+	if end != nil {
+		defer func() { end(hit, err) }()
 	}
-	value, err := fetch()
-	if err != nil {
-		return value, err
+	if value, hit = c.Get(key); hit {
+		return value, true, nil
+	}
+	if value, err = c.fetch(ctx, key, fetch); err != nil {
+		return value, false, fetchError(err)
 	}
-	const hadMetadata = false
-	c.handleMiss(key, value, hadMetadata)
-	return value, nil
+	value, _ = c.setIfAbsent(key, value) // fetch may have set key.
+	return value, false, nil
 }
 
 // Get returns the Value for key if it is resident
 // in the cache, and marks it as referenced;
 // otherwise it returns the zero value and false.
 func (c *Cache[Key, Value]) Get(key Key) (Value, bool) {
-	if page, ok := c.index[key]; ok &&
-		page.Resident {
+	if c.latencies != nil {
+		defer c.latencies.get.record(time.Now())
+	}
+	page, ok := c.index.get(key)
+	if ok && page.Resident && c.expired(key) {
+		c.expirePage(page)
+		ok = false
+	}
+	c.recordOperation(OperationGet, key)
+	c.access(key)
+	if ok && page.Resident {
+		c.stats.total.hits++
+		c.stats.recent.observe(true)
+		c.touch(page)
+		c.countHit(page)
 		page.Referenced = true
-		return page.Value, true
+		if c.maxIdle != 0 {
+			c.stored(key)
+		}
+		return c.decoded(page.Value), true
+	}
+	c.stats.total.misses++
+	c.stats.recent.observe(false)
+	if c.restored != nil {
+		return c.fetchRestored(key)
 	}
 	var zero Value
 	return zero, false
 }
 
+// peek returns the value of key if it is resident,
+// without modifying the cache.
+func (c *Cache[Key, Value]) peek(key Key) (Value, bool) {
+	page, ok := c.index.get(key)
+	if !ok || !page.Resident || c.expired(key) {
+		var zero Value
+		return zero, false
+	}
+	return c.decoded(page.Value), true
+}
+
+// reference marks key as referenced if it is still resident,
+// as if it was hit by [Cache.Get], without counting the hit.
+func (c *Cache[Key, _]) reference(key Key) {
+	page, ok := c.index.get(key)
+	if !ok || !page.Resident {
+		return
+	}
+	c.access(key)
+	c.touch(page)
+	c.countHit(page)
+	page.Referenced = true
+}
+
+// Fetch is like [Cache.Get] but returns
+// [ErrNotFound] if key is not resident.
+func (c *Cache[Key, Value]) Fetch(key Key) (Value, error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+	var zero Value
+	return zero, ErrNotFound
+}
+
+// GetOrSet returns the resident value for key and true, if present.
+// Otherwise, it stores value and returns it with false.
+func (c *Cache[Key, Value]) GetOrSet(key Key, value Value) (actual Value, loaded bool) {
+	if actual, loaded := c.Get(key); loaded {
+		return actual, true
+	}
+	c.insert(key, value)
+	return value, false
+}
+
+// setIfAbsent is like [Cache.GetOrSet],
+// but does not count as a lookup.
+func (c *Cache[Key, Value]) setIfAbsent(key Key, value Value) (actual Value, loaded bool) {
+	if page, ok := c.index.get(key); ok && page.Resident {
+		if !c.expired(key) {
+			return c.decoded(page.Value), true
+		}
+		c.expirePage(page)
+	}
+	c.insert(key, value)
+	return value, false
+}
+
 // Set inserts or updates key with value
 // and marks it as referenced.
 func (c *Cache[Key, Value]) Set(key Key, value Value) {
-	page, found := c.index[key]
-	if found && page.Resident {
-		page.Referenced = true
-		page.Value = value
+	c.set(key, value, 0)
+}
+
+// SetIf is like [Cache.Set], but only stores value if cond
+// returns true when called with the resident value of key,
+// or the zero value and false if key is not resident.
+// Calling cond does not count as an access.
+// SetIf reports whether value was stored.
+func (c *Cache[Key, Value]) SetIf(key Key, value Value, cond func(old Value, exists bool) bool) bool {
+	if !cond(c.peek(key)) {
+		return false
+	}
+	c.Set(key, value)
+	return true
+}
+
+// Compute calls f with the resident value of key,
+// or the zero value and false if key is not resident,
+// and stores the value which f returns if keep is true,
+// like [Cache.Set], or deletes key otherwise,
+// like [Cache.Delete]. Calling f does not count as an access.
+// Compute returns the value of key and whether
+// it is resident once f was applied.
+func (c *Cache[Key, Value]) Compute(key Key, f func(old Value, exists bool) (value Value, keep bool)) (Value, bool) {
+	old, exists := c.peek(key)
+	value, keep := f(old, exists)
+	if !keep {
+		if exists {
+			c.Delete(key)
+		}
+		var zero Value
+		return zero, false
+	}
+	c.Set(key, value)
+	if page, ok := c.index.get(key); !ok || !page.Resident {
+		var zero Value
+		return zero, false // Admission was denied.
+	}
+	return value, true
+}
+
+// Mutate calls mutate with a pointer to the resident value of key,
+// so that a large value may be modified in place, rather than
+// copied out of the cache and stored again, and reports
+// whether key was resident. The entry retains its expiry, and
+// the modification counts as an access, like [Cache.Set].
+// mutate must not retain the pointer. If the cache transforms
+// its values (see [WithEncode]), mutate receives a decoded copy
+// of the value, which is encoded and stored once mutate returns.
+func (c *Cache[Key, Value]) Mutate(key Key, mutate func(*Value)) bool {
+	page, ok := c.index.get(key)
+	if !ok || !page.Resident {
+		return false
+	}
+	if c.expired(key) {
+		c.expirePage(page)
+		return false
+	}
+	var weight int
+	if c.weigh != nil {
+		weight = c.weigh(key, page.Value)
+	}
+	c.recordOperation(OperationSet, key)
+	c.access(key)
+	c.touch(page)
+	page.Referenced = true
+	if c.encode != nil || c.decode != nil {
+		value := c.decoded(page.Value)
+		mutate(&value)
+		page.Value = c.encoded(value)
+	} else {
+		mutate(&page.Value)
+	}
+	if c.weigh != nil {
+		c.weight += c.weigh(key, page.Value) - weight
+	}
+	c.versions.drop(key)
+	c.stored(key)
+	c.trimWeight()
+	return true
+}
+
+// Delete removes key from the cache,
+// including its test page if it was evicted,
+// and reports whether its value was resident.
+func (c *Cache[Key, Value]) Delete(key Key) bool {
+	_, resident := c.removeKey(key, false)
+	return resident
+}
+
+// Remove is like [Cache.Delete] but also returns
+// the value that was resident, if any.
+func (c *Cache[Key, Value]) Remove(key Key) (Value, bool) {
+	return c.removeKey(key, true)
+}
+
+func (c *Cache[Key, Value]) removeKey(key Key, returning bool) (Value, bool) {
+	var zero Value
+	c.forgetRestored(key)
+	page, ok := c.index.get(key)
+	if !ok {
+		return zero, false
+	}
+	if page.Resident && c.expired(key) {
+		c.stats.total.expirations++
+		c.remove(page)
+		return zero, false
+	}
+	value, resident := page.Value, page.Resident
+	c.returning = returning
+	c.remove(page)
+	c.returning = false
+	if !resident {
+		return zero, false
+	}
+	return c.decoded(value), true
+}
+
+// Purge removes all entries from the cache,
+// including test pages. If onPurge is not nil,
+// it is called with each resident entry being removed,
+// and must not modify the cache.
+// Adaptation state, such as the cold target, is retained.
+func (c *Cache[Key, Value]) Purge(onPurge func(Key, Value)) {
+	if onPurge != nil || c.residency != nil ||
+		c.hooks.OnRelease != nil || c.finalizer != nil ||
+		c.journal != nil {
+		for page := range c.residents() {
+			if onPurge != nil {
+				onPurge(page.Name, c.decoded(page.Value))
+			}
+			c.dropped(page)
+		}
+	}
+	if c.recording != nil {
+		for key := range c.index.all() {
+			c.recordOperation(OperationRemove, key)
+		}
+	}
+	if c.sampled != nil {
+		c.sampled.reset()
+	}
+	c.index.clear()
+	c.modifications++
+	c.hot, c.cold, c.test = nil, nil, nil
+	c.clock.Init()
+	c.hotCount, c.coldCount, c.testCount = 0, 0, 0
+	c.demotions = 0
+	c.weight = 0
+	c.expiry = expirations[Key]{idle: c.expiry.idle}
+	c.versions.reset()
+	c.groups.reset()
+	c.tags = nil
+	c.restored = nil
+	c.views = nil // Shared by snapshots, which are unaffected.
+	if c.ghosts != nil {
+		c.ghosts.reset()
+	}
+}
+
+// Invalidate drops the resident value of key,
+// retaining its metadata as a test page, as if it was evicted.
+// If key is re-inserted during its test period,
+// it is treated as a resurrection by the policy.
+// Invalidate reports whether a value was resident.
+func (c *Cache[Key, Value]) Invalidate(key Key) bool {
+	page, ok := c.index.get(key)
+	if !ok || !page.Resident {
+		return false
+	}
+	if c.expired(key) {
+		c.expirePage(page)
+		return false
+	}
+	c.recordOperation(OperationInvalidate, key)
+	c.invalidate(page)
+	return true
+}
+
+// invalidate evicts a resident page,
+// retaining its metadata as a test page.
+func (c *Cache[Key, Value]) invalidate(page *page[Key, Value]) {
+	if !page.LIR {
+		c.evict(page)
+		return
+	}
+	// Hot pages are moved to the top of the stack as cold,
+	// so that they receive a full test period.
+	if page == c.hot {
+		c.hot = c.clock.Next(page)
+	}
+	page.LIR = false
+	c.hotCount--
+	c.coldCount++
+	c.moveToLRU(page)
+	c.evict(page)
+	c.sweepHot()
+}
+
+// NewEpoch clears the reference and demotion state
+// of every page without evicting any, so that adaptation
+// restarts from the current residency.
+// Useful at known workload boundaries.
+func (c *Cache[Key, Value]) NewEpoch() {
+	var noKey Key
+	c.recordOperation(OperationEpoch, noKey)
+	for page := range c.clock.All() {
+		page.Referenced = false
+		page.Demoted = false
+		page.Untouch()
+	}
+	c.demotions = 0
+	if c.shifts != nil {
+		c.shifts.reset()
+	}
+}
+
+// EvictN evicts up to n resident pages,
+// as if room was being made for new pages,
+// and returns how many were evicted.
+func (c *Cache[Key, Value]) EvictN(n int) int {
+	var (
+		evicted int
+		noKey   Key
+	)
+	for ; evicted < n && c.Len() != 0; evicted++ {
+		c.recordOperation(OperationEvict, noKey)
+		c.sweepHot()
+		c.sweepCold()
+		if c.coldCount == 0 { // Pages may have been removed explicitly.
+			c.sweepHot()
+			c.demoteHot()
+			c.sweepCold()
+		}
+		c.evictCold()
+	}
+	return evicted
+}
+
+// resize changes the capacity of the cache,
+// evicting pages as needed if it shrank.
+// The cold target is retained within the new bounds.
+func (c *Cache[_, _]) resize(capacity int) {
+	c.capacity = capacity
+	c.coldMinimum, c.coldMaximum = c.coldBounds(capacity)
+	c.adjustColdTarget(0)
+	c.EvictN(c.Len() - capacity)
+	for c.hotCount > c.hotTarget {
+		c.demoteHot()
+	}
+	c.pruneTest()
+}
+
+// update replaces the value of a resident page
+// and marks it as referenced.
+func (c *Cache[Key, Value]) update(page *page[Key, Value], value Value) {
+	c.recordOperation(OperationSet, page.Name)
+	c.access(page.Name)
+	c.touch(page)
+	page.Referenced = true
+	c.release(page, false)
+	c.reweigh(page, value)
+	c.versions.drop(page.Name)
+	page.Value = value
+}
+
+// insert should be called with a value for a key
+// which is known to not be resident.
+func (c *Cache[Key, Value]) insert(key Key, value Value) {
+	value = c.encoded(value)
+	if c.overweight(key, value) {
 		return
 	}
-	c.handleMiss(key, value, found)
+	_, hadMetadata := c.index.get(key)
+	c.handleMiss(key, value, hadMetadata, 0)
+	c.stored(key)
+	c.trimWeight()
 }
 
 // handleMiss should be called after a page access misses.
 // Caller must provide if the page's metadata was present
-// (even if the page's value was not resident).
-func (c *Cache[Key, Value]) handleMiss(key Key, value Value, hadMetadata bool) {
+// (even if the page's value was not resident),
+// and the cost of the miss, or 0 if it is not known.
+func (c *Cache[Key, Value]) handleMiss(key Key, value Value, hadMetadata bool, cost float64) (result setResult[Key, Value]) {
+	if !c.batching {
+		c.Expire()
+	}
+	c.recordOperation(OperationSet, key)
+	c.recordCost(cost)
+	c.access(key)
+	ghost := !hadMetadata && c.ghosts != nil && c.ghosts.contains(key)
+	if !hadMetadata && !ghost && !c.admit(key) {
+		c.stats.total.rejections++
+		result.outcome = SetRejected
+		return result
+	}
+	c.observeInsertion(hadMetadata || ghost)
 	c.sweepHot()
 	c.sweepCold()
 	if hadMetadata {
 		// If a page for the key was found and not evicted
 		// by the hand sweeps above, it is resurrected as resident.
-		if test, hit := c.index[key]; hit {
-			c.promoteTest(test, value)
-			return
+		if test, hit := c.index.get(key); hit {
+			result = c.promoteTest(test, value, c.costWeight(cost))
+			result.outcome = SetResurrected
+			return result
 		}
 	}
+	if ghost {
+		result = c.resurrectGhost(key, value, c.costWeight(cost))
+		result.outcome = SetResurrected
+		return result
+	}
+	c.observeShift(false)
 	if c.atCapacity() {
-		c.evictCold()
+		result = c.evictCold()
 	}
 	c.addNew(key, value)
+	result.outcome = SetInserted
+	return result
+}
+
+// access should be called for every
+// Get and Set operation on the key.
+func (c *Cache[Key, _]) access(key Key) {
+	c.operations++
+	c.sampleReuse(key)
 }
 
 func (c *Cache[_, _]) atCapacity() bool {
@@ -114,18 +590,18 @@ func (c *Cache[_, _]) atCapacity() bool {
 // and performs hand sweeps/actions as necessary.
 func (c *Cache[Key, Value]) addNew(key Key, value Value) {
 	var (
-		lowIRR = c.coldCount == 0 &&
-			c.hotCount < c.hotTarget
-		page = &page[Key, Value]{
-			Metadata: metadata[Key]{
-				Name:     key,
-				Resident: true,
-				LIR:      lowIRR,
-				Stacked:  true,
-			},
-			Value: value,
-		}
+		scanning = c.scanning()
+		lowIRR   = c.coldCount == 0 &&
+			c.hotCount < c.hotTarget &&
+			!scanning
+		page = c.newPage(metadata[Key]{
+			Name:     key,
+			Resident: true,
+			LIR:      lowIRR,
+			Stacked:  !scanning,
+		}, value)
 	)
+	c.touch(page)
 	c.addToClock(page)
 	if lowIRR {
 		c.hotCount++
@@ -135,32 +611,45 @@ func (c *Cache[Key, Value]) addNew(key Key, value Value) {
 		}
 		c.coldCount++
 	}
+	c.recordDecision(DecisionInsert, key)
+	c.hooks.inserted(key, value)
+	c.becameResident(page)
 	c.sweepCold()
 	c.pruneTest()
 }
 
 // promoteTest resurrects a nonresident page as resident,
-// promoting it to hot. The cache targets are also adjusted.
-func (c *Cache[Key, Value]) promoteTest(testToHot *page[Key, Value], value Value) {
-	if debugging {
-		assert(testToHot.Stacked,
+// promoting it to hot. The cache targets are also adjusted,
+// scaled by weight.
+func (c *Cache[Key, Value]) promoteTest(testToHot *page[Key, Value], value Value, weight float64) (result setResult[Key, Value]) {
+	if c.asserting() {
+		c.assert(testToHot.Stacked,
 			"hit a non-resident cold page out of the stack")
-		assert(!testToHot.Referenced,
+		c.assert(!testToHot.Referenced,
 			"hit a referenced non-resident cold page")
-		assert(c.hotCount+c.coldCount == c.capacity,
-			"cache not full")
 	}
-	c.increaseColdTarget()
-	c.evictCold()
+	c.increaseColdTarget(weight)
+	c.observeShift(true)
+	if c.atCapacity() { // Pages may have been removed explicitly.
+		result = c.evictCold()
+	}
+	c.modifications++
 	testToHot.Value = value
 	testToHot.Resident = true
+	testToHot.Hits = 0
+	c.touch(testToHot)
 	c.testCount--
 	c.coldCount++
 	if testToHot == c.test {
 		c.sweepTest()
 	}
+	c.recordDecision(DecisionResurrect, testToHot.Name)
+	c.stats.total.resurrections++
+	c.hooks.ghostHit(testToHot.Name, value)
+	c.becameResident(testToHot)
 	c.promoteCold(testToHot)
 	c.sweepCold()
+	return result
 }
 
 func (c *Cache[_, _]) sweepHot() {
@@ -168,8 +657,9 @@ func (c *Cache[_, _]) sweepHot() {
 		return
 	}
 	page := c.hot
-	for !page.LIR || page.Referenced {
-		next := page.Next()
+	c.fault(FaultSweep, page.Name)
+	for !page.LIR || referenced(page) {
+		next := c.clock.Next(page)
 		if page.LIR {
 			c.handleHotLIR(page)
 		} else {
@@ -181,35 +671,46 @@ func (c *Cache[_, _]) sweepHot() {
 }
 
 func (c *Cache[Key, Value]) handleHotLIR(page *page[Key, Value]) {
+	c.recordDecision(DecisionClear, page.Name)
+	c.trace(HandHot, DecisionClear, page.Name)
 	page.Referenced = false
-	c.lru = page
+	c.clock.Rotate(page)
 }
 
 func (c *Cache[Key, Value]) handleHotHIR(page, next *page[Key, Value]) {
 	if page.Resident {
-		if page.Referenced {
+		if referenced(page) {
+			c.recordDecision(DecisionClear, page.Name)
+			c.trace(HandHot, DecisionClear, page.Name)
 			page.Referenced = false
 			if page.Demoted {
 				c.decreaseColdTarget()
 				page.Demoted = false
 				c.demotions--
 			}
-			c.lru = page
+			c.clock.Rotate(page)
 			if page == c.cold {
 				c.cold = next
 			}
 		} else {
+			c.recordDecision(DecisionUnstack, page.Name)
+			c.trace(HandHot, DecisionUnstack, page.Name)
 			page.Stacked = false
 		}
 	} else {
+		c.trace(HandHot, DecisionForget, page.Name)
 		c.removeTest(page)
 	}
 }
 
-func (c *Cache[_, _]) increaseColdTarget() {
-	delta := max(
-		c.demotions/c.testCount,
-		1,
+func (c *Cache[_, _]) increaseColdTarget(weight float64) {
+	tests := c.testCount
+	if c.ghosts != nil {
+		tests += c.ghosts.len()
+	}
+	var (
+		base  = max(c.demotions/max(tests, 1), 1)
+		delta = max(int(math.Round(float64(base)*weight)), 1)
 	)
 	c.adjustColdTarget(delta)
 }
@@ -223,21 +724,29 @@ func (c *Cache[_, _]) decreaseColdTarget() {
 }
 
 func (c *Cache[_, _]) adjustColdTarget(delta int) {
-	var (
-		size       = c.capacity // Range: [1,half-capacity].
-		diff       = max(c.coldTarget+delta, 1)
-		coldTarget = min(diff, size/2)
+	if c.paused && delta != 0 {
+		return
+	}
+	if c.adaptationStep != 0 && delta != 0 {
+		delta = c.limitAdaptation(delta)
+	}
+	var ( // Range: [coldMinimum,coldMaximum].
+		diff       = max(c.coldTarget+delta, c.coldMinimum)
+		coldTarget = min(diff, c.coldMaximum)
 	)
+	c.setColdTarget(coldTarget)
+}
+
+func (c *Cache[_, _]) setColdTarget(coldTarget int) {
 	c.coldTarget = coldTarget
-	c.hotTarget = size - coldTarget
+	c.hotTarget = c.capacity - coldTarget
+	c.recordAdaptation()
 }
 
 func (c *Cache[Key, Value]) removeTest(test *page[Key, Value]) {
-	if test == c.test {
-		c.test = test.Next()
-	}
-	delete(c.index, test.Name)
-	test.Prev().Unlink(1)
+	c.recordDecision(DecisionForget, test.Name)
+	c.stats.total.testRemovals++
+	c.unlink(test)
 	c.testCount--
 	c.sweepTest()
 }
@@ -249,30 +758,44 @@ func (c *Cache[_, _]) sweepTest() {
 	}
 	hand := c.test
 	for hand.LIR || hand.Resident {
-		hand = hand.Next()
+		c.trace(HandTest, 0, hand.Name)
+		hand = c.clock.Next(hand)
 	}
 	c.test = hand
 }
 
+// referenced reports whether the page was referenced,
+// including by concurrent readers. See [Synced].
+func referenced[Key comparable, Value any](page *page[Key, Value]) bool {
+	if page.Untouch() {
+		page.Referenced = true
+	}
+	return page.Referenced
+}
+
 func (c *Cache[_, _]) sweepCold() {
-	if c.coldCount == 0 {
-		return
+	if c.coldCount != 0 {
+		c.fault(FaultSweep, c.cold.Name)
 	}
-	hand := c.cold
-	for hand.LIR ||
-		!hand.Resident ||
-		hand.Referenced {
-		page := hand
-		hand = hand.Next()
+	// The hand itself is advanced before handling each page,
+	// so that it is moved along if handling removes the next page.
+	for c.coldCount != 0 && // Promotions may take the last cold page.
+		(c.cold.LIR ||
+			!c.cold.Resident ||
+			referenced(c.cold)) {
+		page := c.cold
+		c.cold = c.clock.Next(page)
 		if page.LIR || !page.Referenced {
+			c.trace(HandCold, 0, page.Name)
 			continue
 		}
 		c.handleReferencedCold(page)
 	}
-	c.cold = hand
 }
 
 func (c *Cache[Key, Value]) handleReferencedCold(page *page[Key, Value]) {
+	c.recordDecision(DecisionClear, page.Name)
+	c.trace(HandCold, DecisionClear, page.Name)
 	page.Referenced = false
 	if page.Demoted {
 		c.decreaseColdTarget()
@@ -280,66 +803,136 @@ func (c *Cache[Key, Value]) handleReferencedCold(page *page[Key, Value]) {
 		c.demotions--
 	}
 	if page.Stacked {
+		c.trace(HandCold, DecisionPromote, page.Name)
 		c.promoteCold(page)
-	} else {
-		page.Stacked = true
-		c.moveToLRU(page)
+		return
+	}
+	if c.limitChances {
+		if int(page.Chances) >= c.secondChances {
+			c.cold = page // Stop the hand, to be evicted next.
+			return
+		}
+		page.Chances++
 	}
+	c.recordDecision(DecisionRestack, page.Name)
+	c.trace(HandCold, DecisionRestack, page.Name)
+	page.Stacked = true
+	c.moveToLRU(page)
 }
 
+// demotionBatch is the most hot pages which
+// are demoted by a single promotion.
+const demotionBatch = 8
+
 func (c *Cache[Key, Value]) promoteCold(coldToHot *page[Key, Value]) {
 	coldToHot.LIR = true
+	coldToHot.Chances = 0
 	c.hotCount++
 	c.coldCount--
 	c.moveToLRU(coldToHot)
-	for c.hotCount > c.hotTarget {
+	c.recordDecision(DecisionPromote, coldToHot.Name)
+	c.stats.total.promotions++
+	c.hooks.promoted(coldToHot.Name)
+	// After the hot target shrinks by more than the batch,
+	// the remaining demotions are carried forward to
+	// subsequent promotions, so that each is bounded.
+	for demoted := 0; c.hotCount > c.hotTarget &&
+		demoted < demotionBatch; demoted++ {
 		c.demoteHot()
 	}
 }
 
 func (c *Cache[Key, Value]) moveToLRU(page *page[Key, Value]) {
-	if page == c.lru {
-		return
-	}
-	leaf := page.Prev().Unlink(1)
-	c.lru.Link(leaf)
-	c.lru = leaf
+	c.modifications++
+	c.clock.MoveToBack(page)
 }
 
 func (c *Cache[_, _]) demoteHot() {
-	if debugging {
-		assert(!c.hot.Referenced,
-			"hot hand stops on a referenced page")
+	// The hand may have been left on a page
+	// which is no longer hot, if hot pages were
+	// removed explicitly, or if there were none.
+	c.sweepHot()
+	if c.asserting() {
+		c.assert(c.hot.LIR && !c.hot.Referenced,
+			"hot hand does not stop at a non-referenced hot page")
 	}
 	page := c.hot
-	c.hot = page.Next()
+	c.trace(HandHot, DecisionDemote, page.Name)
+	c.hot = c.clock.Next(page)
 	page.LIR = false
 	page.Stacked = false
 	page.Demoted = true
 	c.hotCount--
 	c.coldCount++
 	c.demotions++
+	if c.cold == nil {
+		c.cold = page
+	}
 	c.moveToLRU(page)
+	c.recordDecision(DecisionDemote, page.Name)
+	c.stats.total.demotions++
+	c.hooks.demoted(page.Name)
 	c.sweepHot()
 }
 
-// evictCold evicts the current cold hand.
-// Eviction zeros the page's Value but retains
-// metadata as a nonresident "test page" to guide adaptation.
-// If the page is not stacked, it is removed entirely.
-func (c *Cache[_, Value]) evictCold() {
-	if debugging {
-		assert(
+// evictCold evicts the current cold hand,
+// or a page near it. See [WithSizeAwareEviction].
+func (c *Cache[Key, Value]) evictCold() setResult[Key, Value] {
+	if c.asserting() {
+		c.assert(
 			!c.cold.LIR && c.cold.Resident && !c.cold.Referenced,
 			"cold hand does not stop at a non-referenced resident cold page")
 	}
-	var (
-		zero Value
-		page = c.cold
-	)
-	c.cold = page.Next()
+	page := c.victim()
+	c.fault(FaultEvict, page.Name)
+	c.trace(HandCold, DecisionEvict, page.Name)
+	result := setResult[Key, Value]{
+		evictedKey: page.Name,
+		evicted:    true,
+	}
+	returned := c.returnEvicted
+	c.returnEvicted = nil // Later victims are released as usual.
+	if returned != nil || c.evictions != nil {
+		// Only decoded if it is read, since decoding may
+		// be expensive, such as with [WithCompression].
+		result.evictedValue = c.decoded(page.Value)
+	}
+	c.stats.total.evictions++
+	c.recordEvictionAge(page)
+	if ec := c.evictions; ec != nil {
+		ec.send(Eviction[Key, Value]{
+			Key:   page.Name,
+			Value: result.evictedValue,
+			Info:  c.infoOf(page),
+		})
+	}
+	if returned != nil {
+		*returned = result
+	}
+	c.returning = returned != nil
+	c.evict(page)
+	c.returning = false
+	return result
+}
+
+// evict makes a resident cold page nonresident.
+// Eviction zeros the page's Value (see [WithRetainedValues]) but retains
+// metadata as a nonresident "test page" to guide adaptation.
+// If the page is not stacked, it is removed entirely.
+func (c *Cache[Key, Value]) evict(page *page[Key, Value]) {
+	if page == c.cold {
+		c.cold = c.clock.Next(page)
+	}
+	c.recordDecision(DecisionEvict, page.Name)
+	c.modifications++
+	c.expiry.cancel(page.Name)
+	c.dropped(page)
 	page.Resident = false
-	page.Value = zero
+	page.Referenced = false
+	if !c.retainValues {
+		var zero Value
+		page.Value = zero
+	}
 	c.coldCount--
 	c.testCount++
 	if page.Demoted {
@@ -349,54 +942,230 @@ func (c *Cache[_, Value]) evictCold() {
 	if c.test == nil {
 		c.test = page
 	}
+	if c.ghosts != nil && page.Stacked {
+		c.ghosts.add(page.Name)
+		page.Stacked = false
+	}
 	if !page.Stacked {
-		if page == c.lru {
-			c.lru = page.Prev()
-		}
 		c.removeTest(page)
 	}
 }
 
+// newPage allocates a page from the clock,
+// to be linked to it by [Cache.addToClock].
+func (c *Cache[Key, Value]) newPage(metadata metadata[Key], value Value) *page[Key, Value] {
+	page := c.clock.New()
+	page.Metadata, page.Value = metadata, value
+	return page
+}
+
 // addToClock links the page to the clock
 // as well as the page index.
 func (c *Cache[Key, Value]) addToClock(page *page[Key, Value]) {
-	if c.lru == nil {
-		c.lru = page
+	c.modifications++
+	if c.clock.Len() == 0 {
 		c.hot = page
-	} else {
-		c.lru.Link(page)
-		c.lru = page // == c.lru.Next().
 	}
-	c.index[page.Name] = page
+	c.clock.PushBack(page)
+	c.index.put(page)
+}
+
+// remove removes the page from the cache entirely.
+func (c *Cache[Key, Value]) remove(page *page[Key, Value]) {
+	c.recordOperation(OperationRemove, page.Name)
+	if page.Resident {
+		c.dropped(page)
+	}
+	switch {
+	case page.LIR:
+		c.hotCount--
+	case page.Resident:
+		c.coldCount--
+	default:
+		c.testCount--
+	}
+	if page.Demoted {
+		page.Demoted = false
+		c.demotions--
+	}
+	c.expiry.cancel(page.Name)
+	c.unlink(page)
+	c.sweepTest()
+}
+
+// unlink removes the page from the clock and the page index,
+// moving any hands that reference it to the next page.
+func (c *Cache[Key, Value]) unlink(page *page[Key, Value]) {
+	c.modifications++
+	next := c.clock.Next(page)
+	if next == page {
+		next = nil // Removing the last page.
+	}
+	if page == c.hot {
+		c.hot = next
+	}
+	if page == c.cold {
+		c.cold = next
+	}
+	if page == c.test {
+		c.test = next
+	}
+	c.index.delete(page.Name)
+	c.clock.Remove(page)
+	c.clock.Free(page)
 }
 
 func (c *Cache[_, _]) pruneTest() {
 	metadataLimit := c.capacity * 2
 	for c.coldCount+c.hotCount+c.testCount > metadataLimit {
-		if debugging {
-			assert(
+		if c.asserting() {
+			c.assert(
 				c.test.Stacked && !c.test.LIR && !c.test.Resident,
 				"test hand does not stop at a test page")
 		}
+		c.trace(HandTest, DecisionForget, c.test.Name)
 		c.removeTest(c.test)
 	}
 }
 
 // Len returns the number of resident pages.
+// Expired pages are counted until they are evicted.
 func (c *Cache[_, _]) Len() int {
 	return c.hotCount + c.coldCount
 }
 
+// ResidentHot returns the number of resident hot pages.
+func (c *Cache[_, _]) ResidentHot() int { return c.hotCount }
+
+// ResidentCold returns the number of resident cold pages.
+func (c *Cache[_, _]) ResidentCold() int { return c.coldCount }
+
+// NonResident returns the number of nonresident test pages.
+// Keys forgotten into the filter of [WithGhosts] are not counted.
+func (c *Cache[_, _]) NonResident() int { return c.testCount }
+
 // Keys returns an iterator over the (unordered) keys of resident pages.
-func (c *Cache[Key, _]) Keys() iter.Seq[Key] {
+// The cache may be modified during iteration; keys which
+// are removed before they are reached are not yielded,
+// and keys which are inserted may or may not be.
+func (c *Cache[Key, Value]) Keys() iter.Seq[Key] {
+	return c.keysWhere(c.Len, func(page *page[Key, Value]) bool {
+		return page.Resident
+	})
+}
+
+// TrackedKeys is like [Cache.Keys], but also yields
+// the keys of test pages, along with whether each key is resident.
+// The keys forgotten into the filter of [WithGhosts]
+// cannot be recovered, and are not yielded.
+func (c *Cache[Key, Value]) TrackedKeys() iter.Seq2[Key, bool] {
+	return func(yield func(Key, bool) bool) {
+		for key, page := range c.tracked() {
+			if !yield(key, page.Resident) {
+				return
+			}
+		}
+	}
+}
+
+// tracked returns an iterator over every page
+// within the clock, like [Cache.TrackedKeys].
+func (c *Cache[Key, Value]) tracked() iter.Seq2[Key, *page[Key, Value]] {
+	return func(yield func(Key, *page[Key, Value]) bool) {
+		count := c.clock.Len()
+		if count == 0 {
+			return
+		}
+		modifications := c.modifications
+		for key, page := range c.index.all() {
+			if !yield(key, page) {
+				return
+			}
+			if count--; count == 0 && c.modifications == modifications {
+				return
+			}
+		}
+	}
+}
+
+// Range calls f for each resident entry, in no particular order,
+// until f returns false, like [sync.Map.Range].
+// Entries whose TTL has elapsed are skipped, but not evicted.
+// Ranging does not count as an access.
+// Like [Cache.Keys], the cache may be modified by f.
+func (c *Cache[Key, Value]) Range(f func(Key, Value) bool) {
+	for key, value := range c.EntriesWhere(nil) {
+		if !f(key, value) {
+			return
+		}
+	}
+}
+
+// EntriesWhere returns an iterator over the resident entries
+// for which match returns true, in no particular order.
+// A nil match matches every entry. Like [Cache.Range],
+// entries whose TTL has elapsed are skipped, iterating does
+// not count as an access, and the cache may be modified by yield.
+func (c *Cache[Key, Value]) EntriesWhere(match func(Key, Value) bool) iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		if c.Len() == 0 {
+			return
+		}
+		for key, page := range c.index.all() {
+			if !page.Resident || c.expired(key) {
+				continue
+			}
+			value := c.decoded(page.Value)
+			if (match == nil || match(key, value)) && !yield(key, value) {
+				return
+			}
+		}
+	}
+}
+
+// HotKeys returns an iterator over the (unordered) keys of resident hot pages.
+// Like [Cache.Keys], the cache may be modified during iteration.
+func (c *Cache[Key, Value]) HotKeys() iter.Seq[Key] {
+	hot := func() int { return c.hotCount }
+	return c.keysWhere(hot, func(page *page[Key, Value]) bool {
+		return page.LIR
+	})
+}
+
+// ColdKeys returns an iterator over the (unordered) keys of resident cold pages.
+// Like [Cache.Keys], the cache may be modified during iteration.
+func (c *Cache[Key, Value]) ColdKeys() iter.Seq[Key] {
+	cold := func() int { return c.coldCount }
+	return c.keysWhere(cold, func(page *page[Key, Value]) bool {
+		return !page.LIR && page.Resident
+	})
+}
+
+// SortedKeys returns an iterator over the resident keys
+// of any of the cache types in ascending order,
+// such as for range scans over block numbers.
+// The keys are collected and sorted when called,
+// which takes time proportional to their amount.
+func SortedKeys[Key cmp.Ordered](cache interface{ Keys() iter.Seq[Key] }) iter.Seq[Key] {
+	return slices.Values(slices.Sorted(cache.Keys()))
+}
+
+// keysWhere returns an iterator over the keys of pages which match,
+// stopping after the expected count of matches,
+// unless the cache was modified during iteration.
+func (c *Cache[Key, Value]) keysWhere(expected func() int, match func(*page[Key, Value]) bool) iter.Seq[Key] {
 	return func(yield func(Key) bool) {
-		residents := c.Len()
-		for key, page := range c.index {
-			if page.Resident {
+		count := expected()
+		if count == 0 {
+			return
+		}
+		modifications := c.modifications
+		for key, page := range c.index.all() {
+			if match(page) {
 				if !yield(key) {
 					return
 				}
-				if residents--; residents == 0 {
+				if count--; count == 0 && c.modifications == modifications {
 					return
 				}
 			}
@@ -7,6 +7,9 @@ import (
 	"testing"
 	"unsafe"
 
+	"github.com/djdv/go-clockpro"
+	"github.com/djdv/go-clockpro/sieve"
+	"github.com/djdv/go-clockpro/workload"
 	"github.com/hashicorp/golang-lru/arc/v2"
 )
 
@@ -54,6 +57,16 @@ func cacheConstructors() []cacheConstructor {
 				return newCache[int, int](b, capacity)
 			},
 		},
+		{
+			"ClockProPlus integer index",
+			func(capacity int, b *testing.B) benchCache[int, int] {
+				cache, err := clockpro.New(capacity, clockpro.WithIntegerIndex[int, int]())
+				if err != nil {
+					b.Fatal(err)
+				}
+				return cache
+			},
+		},
 		{
 			"ARC",
 			func(capacity int, b *testing.B) benchCache[int, int] {
@@ -64,6 +77,16 @@ func cacheConstructors() []cacheConstructor {
 				return arcWrapper[int, int]{ARCCache: cache}
 			},
 		},
+		{
+			"SIEVE",
+			func(capacity int, b *testing.B) benchCache[int, int] {
+				cache, err := sieve.New[int, int](capacity)
+				if err != nil {
+					b.Fatal(err)
+				}
+				return cache
+			},
+		},
 	}
 }
 
@@ -76,7 +99,7 @@ func accessPatterns() []accessPattern {
 					universe = 1 << 16 // Key space large enough to force misses.
 					seqLen   = 1 << 15 // Power of two for cheap masking.
 				)
-				return makeSequential(universe, seqLen)
+				return workload.Sequential(universe, nextPow2(seqLen))
 			},
 		},
 		{
@@ -87,7 +110,7 @@ func accessPatterns() []accessPattern {
 					seqLen   = 1 << 16
 					hotRatio = 0.9 // 90% of accesses hit hot set.
 				)
-				return makeLooping(capacity, universe, seqLen, hotRatio)
+				return workload.Looping(rngSeed, max(1, capacity), universe, nextPow2(seqLen), hotRatio)
 			},
 		},
 		{
@@ -99,7 +122,7 @@ func accessPatterns() []accessPattern {
 					skew     = 1.2
 					bias     = 1.0
 				)
-				return makeZipf(universe, seqLen, skew, bias)
+				return workload.Zipf(rngSeed, universe, nextPow2(seqLen), skew, bias)
 			},
 		},
 		{
@@ -107,12 +130,10 @@ func accessPatterns() []accessPattern {
 			func(capacity int) []int {
 				const seqLen = 1 << 16
 				var (
-					rng        = newReproducibleRNG()
 					keyCount   = nextPow2(seqLen)
 					upperBound = capacity * 4 // Universe bigger than capacity.
-					seq        = makeRandomSequence(rng, upperBound, keyCount)
 				)
-				return seq
+				return workload.Uniform(rngSeed, upperBound, keyCount)
 			},
 		},
 	}
@@ -203,44 +224,6 @@ func newBenchCache(
 	}
 }
 
-func makeSequential(universe, seqLen int) []int {
-	seq := make([]int, nextPow2(seqLen))
-	for i := range seq {
-		seq[i] = i % universe
-	}
-	return seq
-}
-
-func makeLooping(capacity, universe, seqLen int, hotRatio float64) []int {
-	var (
-		seq      = make([]int, nextPow2(seqLen))
-		rng      = newReproducibleRNG()
-		hotSize  = max(1, capacity)
-		coldSize = max(1, universe-hotSize)
-	)
-	for i := range seq {
-		if rng.Float64() < hotRatio {
-			seq[i] = rng.Intn(hotSize)
-		} else {
-			seq[i] = hotSize + rng.Intn(coldSize)
-		}
-	}
-	return seq
-}
-
-func makeZipf(universe, seqLen int, skew, bias float64) []int {
-	var (
-		seq  = make([]int, nextPow2(seqLen))
-		rng  = newReproducibleRNG()
-		imax = uint64(max(universe, 2) - 1)
-		zipf = rand.NewZipf(rng, skew, bias, imax)
-	)
-	for i := range seq {
-		seq[i] = int(zipf.Uint64())
-	}
-	return seq
-}
-
 func apiOverhead(b *testing.B) {
 	type (
 		Key   = int
@@ -257,8 +240,7 @@ func apiOverhead(b *testing.B) {
 	)
 	var (
 		cache = newCache[int, int](b, capacity)
-		rng   = newReproducibleRNG()
-		keys  = makeRandomSequence(rng, upperBound, keyCount)
+		keys  = workload.Uniform(rngSeed, upperBound, keyCount)
 	)
 	addIncrementingInts(cache, capacity)
 	b.ReportAllocs()
@@ -271,14 +253,6 @@ func apiOverhead(b *testing.B) {
 	}
 }
 
-func makeRandomSequence(rng *rand.Rand, upperBound, capacity int) []int {
-	keys := make([]int, capacity)
-	for i := range keys {
-		keys[i] = rng.Intn(upperBound)
-	}
-	return keys
-}
-
 func warmUp(c benchCache[int, int], seq []int) {
 	for _, k := range seq {
 		if _, ok := c.Get(k); !ok {
@@ -3,9 +3,3 @@
 package clockpro
 
 const debugging = true
-
-func assert(cond bool, message string) {
-	if !cond {
-		panic(message)
-	}
-}
@@ -3,5 +3,3 @@
 package clockpro
 
 const debugging = false
-
-func assert(bool, string) { /* NOOP */ }
@@ -1,10 +1,14 @@
 package clockpro_test
 
 import (
+	"errors"
 	"fmt"
 	"iter"
+	"maps"
+	"math/rand"
 	"slices"
 	"testing"
+	"time"
 
 	"github.com/djdv/go-clockpro"
 )
@@ -25,6 +29,70 @@ func TestClockProPlus(t *testing.T) {
 	t.Run("eviction order", evictionOrder)
 	t.Run("readmit page", ghostHit)
 	t.Run("only resident keys", keysStopsAfterResidents)
+	t.Run("keys by temperature", keysByTemperature)
+	t.Run("keys during modification", keysModified)
+	t.Run("tracked keys", trackedKeys)
+	t.Run("sorted keys", sortedKeys)
+	t.Run("range", rangeResidents)
+	t.Run("entries where", entriesWhere)
+	t.Run("evicted entry", evictedEntry)
+	t.Run("evicted entries", evictedEntries)
+	t.Run("class counts", classCounts)
+	t.Run("set outcome", setOutcome)
+	t.Run("fetch", fetch)
+	t.Run("get or set", getOrSet)
+	t.Run("set if", setIf)
+	t.Run("compute", compute)
+	t.Run("mutate", mutate)
+	t.Run("remove", remove)
+	t.Run("purge", purge)
+	t.Run("evict n", evictN)
+	t.Run("invalidate", invalidate)
+	t.Run("new epoch", newEpoch)
+}
+
+func classCounts(t *testing.T) {
+	t.Parallel()
+	const capacity = 8
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sharded := newSharded(t, capacity*2, 2)
+	for key := range capacity * 2 {
+		cache.Set(key, key)
+		sharded.Set(key, key)
+		if key%3 == 0 {
+			cache.Get(key / 2)
+		}
+	}
+	want := make(map[clockpro.PageClass]int)
+	for _, info := range cache.Pages() {
+		want[info.Class]++
+	}
+	for _, test := range []struct {
+		class     clockpro.PageClass
+		got, want int
+	}{
+		{clockpro.ClassHot, cache.ResidentHot(), want[clockpro.ClassHot]},
+		{clockpro.ClassCold, cache.ResidentCold(), want[clockpro.ClassCold]},
+		{clockpro.ClassTest, cache.NonResident(), want[clockpro.ClassTest]},
+	} {
+		if test.got != test.want {
+			t.Errorf(
+				"unexpected count of %v pages"+
+					"\n\tgot: %d"+
+					"\n\twant: %d",
+				test.class, test.got, test.want)
+		}
+	}
+	if got := sharded.ResidentHot() + sharded.ResidentCold(); got != sharded.Len() {
+		t.Errorf(
+			"sharded class counts do not sum to the length"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			got, sharded.Len())
+	}
 }
 
 func invalidCapacity(t *testing.T) {
@@ -204,6 +272,642 @@ func keysStopsAfterResidents(t *testing.T) {
 	}
 }
 
+// keysModified inserts and removes keys while iterating,
+// and checks that keys which remained resident were all yielded.
+func keysModified(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 64
+		rounds   = 16
+	)
+	for _, modify := range []struct {
+		name string
+		fn   func(cache testCache[int, int], yielded int)
+	}{
+		{"insert", func(cache testCache[int, int], yielded int) {
+			cache.Set(capacity+yielded, yielded)
+		}},
+		{"delete", func(cache testCache[int, int], yielded int) {
+			cache.(interface{ Delete(int) bool }).Delete(yielded + 1)
+		}},
+	} {
+		for range rounds {
+			cache := newCache[int, int](t, capacity)
+			addIncrementingInts(cache, capacity/4)
+			before := slices.Collect(cache.Keys())
+			var yielded []int
+			for key := range cache.Keys() {
+				yielded = append(yielded, key)
+				modify.fn(cache, key)
+			}
+			for key := range cache.Keys() {
+				if slices.Contains(before, key) && !slices.Contains(yielded, key) {
+					t.Fatalf("%s: key %d was resident throughout iteration but not yielded",
+						modify.name, key)
+				}
+			}
+		}
+	}
+}
+
+func sortedKeys(t *testing.T) {
+	t.Parallel()
+	const capacity = 16
+	var (
+		cache  = newSynced(t, capacity)
+		keys   = rand.New(rand.NewSource(1)).Perm(capacity * 4)
+		sorted []int
+	)
+	for _, key := range keys {
+		cache.Set(key, key)
+	}
+	for key := range clockpro.SortedKeys(cache) {
+		sorted = append(sorted, key)
+	}
+	want := slices.Sorted(cache.Keys())
+	if !slices.Equal(sorted, want) || len(sorted) != cache.Len() {
+		t.Errorf(
+			"unexpected keys"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			sorted, want)
+	}
+}
+
+func trackedKeys(t *testing.T) {
+	t.Parallel()
+	const capacity = 4
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addIncrementingInts(cache, capacity*2)
+	var (
+		got  = make(map[int]bool)
+		want = make(map[int]bool)
+	)
+	for key, resident := range cache.TrackedKeys() {
+		got[key] = resident
+	}
+	for key, info := range cache.Pages() {
+		want[key] = info.Resident
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf(
+			"unexpected tracked keys"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			got, want)
+	}
+	var residents int
+	for _, resident := range got {
+		if resident {
+			residents++
+		}
+	}
+	if residents != cache.Len() || len(got) == residents {
+		t.Errorf("expected %d resident keys and some test keys, got %d of %d",
+			cache.Len(), residents, len(got))
+	}
+}
+
+// rangeResidents checks that Range yields the value
+// of each resident entry, without referencing it.
+func rangeResidents(t *testing.T) {
+	t.Parallel()
+	const capacity = 8
+	var (
+		now        = time.Unix(0, 0)
+		cache, err = clockpro.New(capacity,
+			clockpro.WithTimeSource[int, int](func() time.Time { return now }),
+		)
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key := range capacity * 2 {
+		cache.Set(key, key*10)
+	}
+	cache.SetWithTTL(capacity*2, 0, time.Second)
+	now = now.Add(time.Minute) // Expires the last entry.
+	got := make(map[int]int)
+	cache.Range(func(key, value int) bool {
+		got[key] = value
+		return true
+	})
+	want := make(map[int]int)
+	for key := range cache.Keys() {
+		if key != capacity*2 {
+			want[key] = key * 10
+		}
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf(
+			"unexpected entries"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			got, want)
+	}
+	for key := range got {
+		if info, _ := cache.Inspect(key); info.Referenced {
+			t.Errorf("entry %d was referenced by Range", key)
+		}
+	}
+	var yielded int
+	cache.Range(func(int, int) bool {
+		yielded++
+		return false
+	})
+	if yielded != 1 {
+		t.Errorf(
+			"iteration continued after f returned false"+
+				"\n\tgot: %d entries"+
+				"\n\twant: 1 entry",
+			yielded)
+	}
+}
+
+func entriesWhere(t *testing.T) {
+	t.Parallel()
+	const capacity = 16
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key := range capacity * 2 {
+		cache.Set(key, key%3)
+	}
+	var (
+		got  = maps.Collect(cache.EntriesWhere(func(_, value int) bool { return value == 0 }))
+		want = make(map[int]int)
+	)
+	for key := range cache.Keys() {
+		if key%3 == 0 {
+			want[key] = 0
+		}
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf(
+			"unexpected entries"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			got, want)
+	}
+	// Delete the matching entries while iterating.
+	for key := range cache.EntriesWhere(func(_, value int) bool { return value == 1 }) {
+		cache.Delete(key)
+	}
+	for key, value := range cache.EntriesWhere(nil) {
+		if value == 1 {
+			t.Errorf("entry %d was not deleted", key)
+		}
+	}
+	if err := cache.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+}
+
+func keysByTemperature(t *testing.T) {
+	t.Parallel()
+	const capacity = 2
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addIncrementingInts(cache, capacity)
+	cache.Set(3, 3) // Evicts 2 (cold).
+	cache.Set(2, 2) // Resurrects 2 (hot), demoting 1.
+	for _, test := range []struct {
+		name string
+		keys iter.Seq[int]
+		want []int
+	}{
+		{"hot", cache.HotKeys(), []int{2}},
+		{"cold", cache.ColdKeys(), []int{1}},
+	} {
+		if got := slices.Collect(test.keys); !slices.Equal(got, test.want) {
+			t.Errorf(
+				"unexpected %s keys"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				test.name, got, test.want)
+		}
+	}
+}
+
+// evictedEntries evicts several entries by weight within
+// [clockpro.Cache.SetGetEvicted], and expects the first
+// to be returned, and each to be sent to the channel.
+func evictedEntries(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity  = 8
+		maxWeight = 10
+	)
+	cache, err := clockpro.New(capacity,
+		clockpro.WithEvictionChannel[int, int](capacity, clockpro.DropOldestEviction),
+		clockpro.WithMaxWeight(func(_, value int) int { return value }, maxWeight),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key := range 4 {
+		cache.Set(key, 2)
+	}
+	key, value, evicted := cache.SetGetEvicted(100, 8)
+	if !evicted {
+		t.Fatal("expected an entry to be returned")
+	}
+	var sent []int
+	for len(cache.Evictions()) != 0 {
+		sent = append(sent, (<-cache.Evictions()).Key)
+	}
+	if len(sent) < 2 || sent[0] != key || value != 2 {
+		t.Errorf(
+			"expected the first of several evictions to be returned"+
+				"\n\tgot: %d=%d, evicted: %v",
+			key, value, sent,
+		)
+	}
+}
+
+func evictedEntry(t *testing.T) {
+	t.Parallel()
+	const capacity = 2
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		name       string
+		key, value int
+		evicted    bool
+		wantKey    int
+	}{
+		{"insert", 1, 1, false, 0},
+		{"fill", 2, 2, false, 0},
+		{"update", 1, 10, false, 0},
+		{"evict cold", 3, 3, true, 2},
+		{"resurrect", 2, 20, true, 3},
+	} {
+		key, value, evicted := cache.SetGetEvicted(test.key, test.value)
+		if evicted != test.evicted || key != test.wantKey ||
+			(evicted && value != key) {
+			t.Errorf(
+				"unexpected eviction from %s"+
+					"\n\tgot: %v=%v (%t)"+
+					"\n\twant: %v=%v (%t)",
+				test.name,
+				key, value, evicted,
+				test.wantKey, test.wantKey, test.evicted)
+		}
+	}
+}
+
+func setOutcome(t *testing.T) {
+	t.Parallel()
+	const capacity = 2
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		key  int
+		want clockpro.SetOutcome
+	}{
+		{1, clockpro.SetInserted},
+		{2, clockpro.SetInserted},
+		{3, clockpro.SetInserted}, // Evicts 2 (cold).
+		{2, clockpro.SetResurrected},
+		{2, clockpro.SetUpdated},
+	} {
+		if got := cache.SetReport(test.key, test.key); got != test.want {
+			t.Errorf(
+				"unexpected outcome for key %d"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				test.key, got, test.want)
+		}
+	}
+	checkCount(t, "resurrections", cache.Stats().Resurrections, 1)
+}
+
+func fetch(t *testing.T) {
+	t.Parallel()
+	const capacity = 2
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Fetch(1); !errors.Is(err, clockpro.ErrNotFound) {
+		t.Errorf(
+			"unexpected error for missing key"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			err, clockpro.ErrNotFound)
+	}
+	cache.Set(1, 1)
+	if value, err := cache.Fetch(1); err != nil || value != 1 {
+		t.Errorf(
+			"unexpected result for resident key"+
+				"\n\tgot: %v, %v"+
+				"\n\twant: %v, %v",
+			value, err, 1, nil)
+	}
+}
+
+func getOrSet(t *testing.T) {
+	t.Parallel()
+	const capacity = 2
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		value, want int
+		loaded      bool
+	}{
+		{1, 1, false},
+		{2, 1, true},
+	} {
+		actual, loaded := cache.GetOrSet(1, test.value)
+		if actual != test.want || loaded != test.loaded {
+			t.Errorf(
+				"unexpected result storing %d"+
+					"\n\tgot: %v, %t"+
+					"\n\twant: %v, %t",
+				test.value, actual, loaded, test.want, test.loaded)
+		}
+	}
+}
+
+func setIf(t *testing.T) {
+	t.Parallel()
+	const capacity = 2
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	later := func(value int) func(int, bool) bool {
+		return func(old int, exists bool) bool {
+			return !exists || value > old
+		}
+	}
+	for _, test := range []struct {
+		value, want int
+		stored      bool
+	}{
+		{2, 2, true},
+		{1, 2, false},
+		{3, 3, true},
+	} {
+		stored := cache.SetIf(1, test.value, later(test.value))
+		if stored != test.stored {
+			t.Errorf(
+				"unexpected result storing %d"+
+					"\n\tgot: %t"+
+					"\n\twant: %t",
+				test.value, stored, test.stored)
+		}
+		checkGet(t, cache, 1, test.want, "after SetIf")
+	}
+}
+
+func compute(t *testing.T) {
+	t.Parallel()
+	const capacity = 2
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Increments the value, deleting it once it reaches 3.
+	increment := func(old int, exists bool) (int, bool) {
+		if !exists {
+			return 1, true
+		}
+		return old + 1, old+1 < 3
+	}
+	for _, test := range []struct {
+		want     int
+		resident bool
+	}{
+		{1, true},
+		{2, true},
+		{0, false},
+		{1, true},
+	} {
+		value, resident := cache.Compute(1, increment)
+		if value != test.want || resident != test.resident {
+			t.Errorf(
+				"unexpected result of Compute"+
+					"\n\tgot: %d, %t"+
+					"\n\twant: %d, %t",
+				value, resident, test.want, test.resident)
+		}
+	}
+	checkGet(t, cache, 1, 1, "after Compute")
+}
+
+func mutate(t *testing.T) {
+	t.Parallel()
+	const capacity = 2
+	type large struct {
+		counter int
+		padding [256]byte
+	}
+	cache, err := clockpro.New[int, large](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	increment := func(value *large) { value.counter++ }
+	if cache.Mutate(1, increment) {
+		t.Error("missing key was mutated")
+	}
+	cache.Set(1, large{counter: 1})
+	for range 2 {
+		if !cache.Mutate(1, increment) {
+			t.Fatal("resident key was not mutated")
+		}
+	}
+	if value := mustGet(t, cache, 1); value.counter != 3 {
+		t.Errorf(
+			"unexpected value after Mutate"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			value.counter, 3)
+	}
+}
+
+func remove(t *testing.T) {
+	t.Parallel()
+	const capacity = 2
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addIncrementingInts(cache, capacity)
+	cache.Set(3, 3) // Evicts 2 (cold).
+	for _, test := range []struct {
+		name     string
+		key      int
+		value    int
+		resident bool
+	}{
+		{"resident", 1, 1, true},
+		{"removed", 1, 0, false},
+		{"test page", 2, 0, false},
+		{"untracked", 4, 0, false},
+	} {
+		value, resident := cache.Remove(test.key)
+		if value != test.value || resident != test.resident {
+			t.Errorf(
+				"unexpected %s removal"+
+					"\n\tgot: %v, %t"+
+					"\n\twant: %v, %t",
+				test.name, value, resident, test.value, test.resident)
+		}
+	}
+	keysMatch(t, cache, []int{3}, "remaining keys after removal")
+	if !cache.Delete(3) {
+		t.Error("expected resident key to be deleted")
+	}
+	checkSize(t, cache, 0, "after deleting all keys")
+	// The test page of 2 was removed,
+	// so setting it is a plain insertion.
+	if got := cache.SetReport(2, 2); got != clockpro.SetInserted {
+		t.Errorf(
+			"unexpected outcome after removing test page"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			got, clockpro.SetInserted)
+	}
+	addIncrementingInts(cache, capacity*2)
+	checkSize(t, cache, capacity, "after refilling")
+}
+
+func purge(t *testing.T) {
+	t.Parallel()
+	const capacity = 4
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addIncrementingInts(cache, capacity*2)
+	var (
+		purged []int
+		want   = slices.Collect(cache.Keys())
+	)
+	cache.Purge(func(key, _ int) { purged = append(purged, key) })
+	if !keysEqualUnordered(want, slices.Values(purged)) {
+		t.Errorf(
+			"unexpected purged keys"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			purged, want)
+	}
+	checkSize(t, cache, 0, "after purge")
+	mustMiss(t, cache, want[0], "purged key")
+	// Test pages must be purged too.
+	if got := cache.SetReport(1, 1); got != clockpro.SetInserted {
+		t.Errorf(
+			"unexpected outcome after purge"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			got, clockpro.SetInserted)
+	}
+	cache.Purge(nil)
+	addIncrementingInts(cache, capacity*2)
+	checkSize(t, cache, capacity, "after refilling")
+}
+
+func evictN(t *testing.T) {
+	t.Parallel()
+	const capacity = 8
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addIncrementingInts(cache, capacity)
+	if evicted := cache.EvictN(capacity / 2); evicted != capacity/2 {
+		t.Errorf(
+			"unexpected eviction count"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			evicted, capacity/2)
+	}
+	checkSize(t, cache, capacity/2, "after evicting half")
+	if evicted := cache.EvictN(capacity); evicted != capacity/2 {
+		t.Errorf(
+			"unexpected eviction count when emptying"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			evicted, capacity/2)
+	}
+	checkSize(t, cache, 0, "after evicting all")
+	addIncrementingInts(cache, capacity*2)
+	checkSize(t, cache, capacity, "after refilling")
+}
+
+func invalidate(t *testing.T) {
+	t.Parallel()
+	const capacity = 4
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addIncrementingInts(cache, capacity)
+	hot := slices.Collect(cache.HotKeys())
+	cold := slices.Collect(cache.ColdKeys())
+	for _, key := range []int{hot[0], cold[0]} {
+		if !cache.Invalidate(key) {
+			t.Errorf("expected resident key %d to be invalidated", key)
+		}
+		mustMiss(t, cache, key, "invalidated key")
+		if cache.Invalidate(key) {
+			t.Errorf("expected invalidated key %d to not be resident", key)
+		}
+		if got := cache.SetReport(key, key); got != clockpro.SetResurrected {
+			t.Errorf(
+				"unexpected outcome for invalidated key %d"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				key, got, clockpro.SetResurrected)
+		}
+	}
+	checkSize(t, cache, capacity, "after re-inserting invalidated keys")
+}
+
+func newEpoch(t *testing.T) {
+	t.Parallel()
+	const capacity = 4
+	for _, test := range []struct {
+		name    string
+		epoch   bool
+		evicted bool
+	}{
+		{"referenced", false, false},
+		{"cleared", true, true},
+	} {
+		cache, err := clockpro.New[int, int](capacity)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addIncrementingInts(cache, capacity) // 1-3 hot, 4 cold.
+		mustGet(t, cache, capacity)
+		if test.epoch {
+			cache.NewEpoch()
+		}
+		key, _, _ := cache.SetGetEvicted(capacity+1, capacity+1)
+		if evicted := key == capacity; evicted != test.evicted {
+			t.Errorf(
+				"%s: unexpected eviction of cold page"+
+					"\n\tgot: %t"+
+					"\n\twant: %t",
+				test.name, evicted, test.evicted)
+		}
+	}
+}
+
 func newCache[
 	Key comparable, Value any,
 ](tb testing.TB, capacity int) testCache[Key, Value] {
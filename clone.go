@@ -0,0 +1,108 @@
+package clockpro
+
+import "maps"
+
+// Clone returns an independent copy of the cache,
+// with identical contents and replacement state.
+// If copyValue is not nil, it is called
+// to copy each resident value; otherwise values
+// are assigned to the clone directly.
+// A cache's [Recording], [Journal], and the channel of
+// [WithEvictionChannel] are not shared with its clone.
+func (c *Cache[Key, Value]) Clone(copyValue func(Value) Value) *Cache[Key, Value] {
+	clone := &Cache[Key, Value]{
+		capacity:    c.capacity,
+		coldTarget:  c.coldTarget,
+		hotTarget:   c.hotTarget,
+		coldMinimum: c.coldMinimum,
+		coldMaximum: c.coldMaximum,
+		coldCount:   c.coldCount,
+		hotCount:    c.hotCount,
+		testCount:   c.testCount,
+		demotions:   c.demotions,
+		weight:      c.weight,
+		scanRun:     c.scanRun,
+		meanCost:    c.meanCost,
+		tuned:       c.tuned,
+		paused:      c.paused,
+		operations:  c.operations,
+		stats:       c.stats,
+		settings:    c.settings,
+	}
+	clone.index = newPageIndex(&clone.clock, c.indexHash, c.index.len())
+	clone.adaptationOrigin = c.adaptationOrigin
+	clone.adaptationStart = c.adaptationStart
+	clone.recording = nil
+	clone.residency = nil
+	clone.journal = nil
+	clone.evictions = nil
+	if c.reuse != nil {
+		clone.reuse = c.reuse.clone()
+	}
+	if c.latencies != nil {
+		clone.latencies = c.latencies.clone()
+	}
+	if c.doorkeeper != nil {
+		clone.doorkeeper = c.doorkeeper.clone()
+	}
+	if c.ghosts != nil {
+		clone.ghosts = c.ghosts.clone()
+	}
+	if c.shifts != nil {
+		shifts := *c.shifts
+		clone.shifts = &shifts
+	}
+	c.cloneClock(clone, copyValue)
+	if c.sampled != nil {
+		clone.sampled = c.sampled.clone(&clone.index)
+	}
+	c.cloneExpirations(clone)
+	clone.versions = c.versions.clone()
+	clone.groups = c.groups.clone()
+	clone.tags = maps.Clone(c.tags)
+	clone.restored = c.restored.clone()
+	return clone
+}
+
+func (c *Cache[Key, Value]) cloneClock(clone *Cache[Key, Value], copyValue func(Value) Value) {
+	for original := range c.clock.All() {
+		value := original.Value
+		if copyValue != nil && original.Resident {
+			value = copyValue(value)
+		}
+		page := clone.newPage(original.Metadata, value)
+		if original.Touched() {
+			page.Touch(page.Generation())
+		}
+		clone.clock.PushBack(page)
+		clone.index.put(page)
+		if original == c.hot {
+			clone.hot = page
+		}
+		if original == c.cold {
+			clone.cold = page
+		}
+		if original == c.test {
+			clone.test = page
+		}
+	}
+}
+
+func (c *Cache[Key, Value]) cloneExpirations(clone *Cache[Key, Value]) {
+	clone.expiry.idle = c.expiry.idle
+	if !c.expiry.active() {
+		return
+	}
+	now := c.now().UnixNano()
+	for key, timer := range c.expiry.timers {
+		clone.expiry.arm(key, now, timer.Deadline())
+	}
+	clone.expiry.limits = maps.Clone(c.expiry.limits)
+}
+
+func (rs *reuseSampler[Key]) clone() *reuseSampler[Key] {
+	clone := *rs
+	clone.last = maps.Clone(rs.last)
+	clone.tree = append([]int(nil), rs.tree...)
+	return &clone
+}
@@ -0,0 +1,121 @@
+package clockpro_test
+
+import (
+	"iter"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestClone(t *testing.T) {
+	t.Run("copies values", cloneCopiesValues)
+	t.Run("identical policy", cloneIdenticalPolicy)
+	t.Run("freeze", freeze)
+}
+
+func freeze(t *testing.T) {
+	t.Parallel()
+	const capacity = 4
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addIncrementingInts(cache, capacity)
+	frozen := cache.Freeze()
+	cache.Delete(1)
+	if value, ok := frozen.Get(1); !ok || value != 1 {
+		t.Errorf("expected frozen view to retain key 1, got: %d, %t", value, ok)
+	}
+	if got, want := frozen.Len(), capacity; got != want {
+		t.Errorf(
+			"unexpected frozen length"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			got, want)
+	}
+	for key := range frozen.Keys() {
+		frozen.Peek(key)
+		if info, _ := frozen.Inspect(key); info.Referenced {
+			t.Errorf("reading key %d from frozen view marked it as referenced", key)
+		}
+	}
+	expiring, clock := newExpiringCache(t, capacity)
+	expiring.SetWithTTL(1, 1, time.Second)
+	expiring.Set(2, 2)
+	frozen = expiring.Freeze()
+	clock.advance(2 * time.Second)
+	if got, want := slices.Collect(frozen.Keys()), []int{2}; !slices.Equal(got, want) || frozen.Len() != len(want) {
+		t.Errorf(
+			"expected expired entries to be omitted"+
+				"\n\tgot: %v (length %d)"+
+				"\n\twant: %v",
+			got, frozen.Len(), want)
+	}
+}
+
+func cloneCopiesValues(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 4
+		key      = "key"
+	)
+	cache, err := clockpro.New[string, []int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Set(key, []int{1})
+	clone := cache.Clone(slices.Clone)
+	mustGet(t, clone, key)[0] = 2
+	if got := mustGet(t, cache, key)[0]; got != 1 {
+		t.Errorf("expected original value to be unchanged, got: %d", got)
+	}
+	clone.Set("other", nil)
+	mustMiss(t, cache, "other", "set in clone")
+}
+
+func cloneIdenticalPolicy(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity   = 16
+		universe   = capacity * 4
+		warmup     = 1 << 12
+		operations = capacity
+	)
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := func(cache *clockpro.Cache[int, int], seed int64, operations int) {
+		rng := rand.New(rand.NewSource(seed))
+		for range operations {
+			key := rng.Intn(universe)
+			if _, ok := cache.Get(key); !ok {
+				cache.Set(key, key)
+			}
+		}
+	}
+	run(cache, rngSeed, warmup)
+	clone := cache.Clone(nil)
+	const seed = rngSeed + 1
+	run(cache, seed, operations)
+	run(clone, seed, operations)
+	for _, class := range []struct {
+		name      string
+		want, got iter.Seq[int]
+	}{
+		{"hot", cache.HotKeys(), clone.HotKeys()},
+		{"cold", cache.ColdKeys(), clone.ColdKeys()},
+	} {
+		got, want := slices.Sorted(class.got), slices.Sorted(class.want)
+		if !slices.Equal(got, want) {
+			t.Errorf(
+				"clone's %s keys diverged from original"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				class.name, got, want)
+		}
+	}
+}
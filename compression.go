@@ -0,0 +1,95 @@
+package clockpro
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Compression headers prefix each stored value.
+const (
+	storedRaw byte = iota
+	storedDeflated
+)
+
+var (
+	deflaters = sync.Pool{New: func() any {
+		writer, _ := flate.NewWriter(nil, flate.BestSpeed)
+		return writer
+	}}
+	inflaters = sync.Pool{New: func() any {
+		return flate.NewReader(nil)
+	}}
+)
+
+// WithCompression compresses values of at least threshold
+// bytes with DEFLATE before they are stored, if that makes
+// them smaller, and decompresses them when they are returned.
+// Every stored value is copied, with a one-byte header.
+// Since the size function of [WithSizeAwareEviction]
+// receives stored values, it measures compressed sizes.
+// WithCompression replaces the functions of
+// [WithEncode] and [WithDecode].
+func WithCompression[Key comparable](threshold int) Option[Key, []byte] {
+	return func(set *settings[Key, []byte]) error {
+		if threshold < 0 {
+			return fmt.Errorf(
+				"%w: compression threshold must be >=0 but %d was provided",
+				ErrInvalidOption, threshold,
+			)
+		}
+		set.encode = func(value []byte) []byte {
+			return compress(value, threshold)
+		}
+		set.decode = decompress
+		return nil
+	}
+}
+
+func compress(value []byte, threshold int) []byte {
+	if len(value) >= threshold {
+		var (
+			buffer = bytes.NewBuffer(make([]byte, 1, len(value)/2+1))
+			writer = deflaters.Get().(*flate.Writer)
+		)
+		buffer.Bytes()[0] = storedDeflated
+		writer.Reset(buffer)
+		_, err := writer.Write(value)
+		if err == nil {
+			err = writer.Close()
+		}
+		deflaters.Put(writer)
+		if err != nil {
+			panic(err) // Writes to a buffer do not fail.
+		}
+		if buffer.Len() < len(value)+1 {
+			return buffer.Bytes()
+		}
+	}
+	stored := make([]byte, len(value)+1)
+	stored[0] = storedRaw
+	copy(stored[1:], value)
+	return stored
+}
+
+func decompress(stored []byte) []byte {
+	if len(stored) == 0 {
+		return nil // Zero value of a nonresident page.
+	}
+	header, value := stored[0], stored[1:]
+	if header == storedRaw {
+		return append([]byte(nil), value...)
+	}
+	reader := inflaters.Get().(io.ReadCloser)
+	defer inflaters.Put(reader)
+	if err := reader.(flate.Resetter).Reset(bytes.NewReader(value), nil); err != nil {
+		panic(err)
+	}
+	decompressed, err := io.ReadAll(reader)
+	if err != nil {
+		panic(fmt.Errorf("stored value is corrupt: %w", err))
+	}
+	return decompressed
+}
@@ -0,0 +1,113 @@
+package clockpro_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestCompression(t *testing.T) {
+	t.Run("invalid", compressionInvalid)
+	t.Run("round trip", compressionRoundTrip)
+	t.Run("size", compressionSize)
+}
+
+const compressionThreshold = 64
+
+func compressibleValue(key int) []byte {
+	return bytes.Repeat(fmt.Appendf(nil, `{"key":%d,"valid":true},`, key), 32)
+}
+
+func compressionInvalid(t *testing.T) {
+	t.Parallel()
+	_, err := clockpro.New(2, clockpro.WithCompression[int](-1))
+	if !errors.Is(err, clockpro.ErrInvalidOption) {
+		t.Errorf(
+			"expected error to match"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			err, clockpro.ErrInvalidOption)
+	}
+}
+
+func compressionRoundTrip(t *testing.T) {
+	t.Parallel()
+	const capacity = 4
+	cache, err := clockpro.New(capacity,
+		clockpro.WithCompression[int](compressionThreshold),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := [][]byte{
+		nil,
+		[]byte("small"),
+		compressibleValue(2),
+		bytes.Repeat([]byte{0xff}, compressionThreshold),
+	}
+	for key, value := range values {
+		cache.Set(key, value)
+	}
+	for key, want := range values {
+		got, ok := cache.Get(key)
+		if !ok || !bytes.Equal(got, want) {
+			t.Errorf(
+				"unexpected value for key %d"+
+					"\n\tgot: %q, %t"+
+					"\n\twant: %q, true",
+				key, got, ok, want,
+			)
+		}
+	}
+	// Returned values must not alias the stored values.
+	got, _ := cache.Get(1)
+	got[0] = 'S'
+	if got, _ := cache.Get(1); !bytes.Equal(got, values[1]) {
+		t.Errorf(
+			"stored value was modified"+
+				"\n\tgot: %q"+
+				"\n\twant: %q",
+			got, values[1],
+		)
+	}
+}
+
+func compressionSize(t *testing.T) {
+	t.Parallel()
+	var (
+		sizes []int
+		value = compressibleValue(1)
+	)
+	cache, err := clockpro.New(4,
+		clockpro.WithCompression[int](compressionThreshold),
+		clockpro.WithHooks(clockpro.Hooks[int, []byte]{
+			OnInsert: func(_ int, stored []byte) {
+				sizes = append(sizes, len(stored))
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Set(1, value)
+	cache.Set(2, value[:compressionThreshold-1])
+	if want := len(value) / 3; sizes[0] > want {
+		t.Errorf(
+			"compressible value was not compressed"+
+				"\n\tgot: %d bytes"+
+				"\n\twant: <=%d bytes",
+			sizes[0], want,
+		)
+	}
+	if want := compressionThreshold; sizes[1] != want {
+		t.Errorf(
+			"value below the threshold was not stored raw"+
+				"\n\tgot: %d bytes"+
+				"\n\twant: %d bytes",
+			sizes[1], want,
+		)
+	}
+}
@@ -0,0 +1,33 @@
+package clockpro
+
+// costSmoothing is the weight of the newest cost
+// in the moving average of costs.
+const costSmoothing = 1.0 / 16
+
+// SetWithCost is like [Cache.Set], but also
+// provides the cost of the miss which produced value;
+// e.g. the latency of fetching it.
+// If key is resurrected from a test page,
+// the cold target is adjusted in proportion to
+// cost relative to the average of provided costs,
+// so that misses on expensive keys are weighted
+// more heavily than misses on cheap keys.
+// Costs must be positive, otherwise they are ignored.
+func (c *Cache[Key, Value]) SetWithCost(key Key, value Value, cost float64) {
+	if !(cost > 0) {
+		cost = 0
+	} else if c.meanCost == 0 {
+		c.meanCost = cost
+	} else {
+		c.meanCost += (cost - c.meanCost) * costSmoothing
+	}
+	c.set(key, value, cost)
+}
+
+// costWeight returns the adaptation weight of a miss with cost.
+func (c *Cache[_, _]) costWeight(cost float64) float64 {
+	if cost == 0 {
+		return 1
+	}
+	return cost / c.meanCost
+}
@@ -0,0 +1,62 @@
+package clockpro_test
+
+import (
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestSetWithCost(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity  = 16
+		cheap     = 1
+		expensive = 10
+	)
+	var (
+		cheapTarget     = resurrectWithCost(t, capacity, cheap)
+		expensiveTarget = resurrectWithCost(t, capacity, expensive)
+	)
+	if expensiveTarget <= cheapTarget {
+		t.Errorf(
+			"expected expensive misses to increase the cold target more"+
+				"\n\tgot: %d"+
+				"\n\twant: >%d",
+			expensiveTarget, cheapTarget)
+	}
+}
+
+// resurrectWithCost fills a cache with pages of cost 1,
+// resurrects an evicted page with cost, and
+// returns the cold target after resurrection.
+func resurrectWithCost(tb testing.TB, capacity int, cost float64) int {
+	tb.Helper()
+	var (
+		coldTarget  int
+		resurrected bool
+		hooks       = clockpro.Hooks[int, int]{
+			OnGhostHit: func(int, int) { resurrected = true },
+		}
+		cache, err = clockpro.New(capacity,
+			clockpro.WithHooks(hooks),
+			clockpro.WithAdaptationRecorder[int, int](func(sample clockpro.AdaptationSample) {
+				coldTarget = sample.ColdTarget
+			}),
+		)
+	)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	for key := 1; key <= capacity; key++ {
+		cache.SetWithCost(key, key, 1)
+	}
+	evicted, _, ok := cache.SetGetEvicted(capacity+1, capacity+1)
+	if !ok {
+		tb.Fatal("expected a page to be evicted")
+	}
+	cache.SetWithCost(evicted, evicted, cost)
+	if !resurrected {
+		tb.Fatal("expected evicted page to be resurrected")
+	}
+	return coldTarget
+}
@@ -0,0 +1,121 @@
+// Package debughttp serves the state of a cache over HTTP,
+// in the manner of [net/http/pprof], so that its health
+// may be inspected without changes to the program.
+// For example:
+//
+//	http.Handle("/debug/clockpro", debughttp.Handler(cache))
+package debughttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"iter"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/djdv/go-clockpro"
+)
+
+type (
+	// Cache is the state served by [Handler], which
+	// each of the concurrent cache types implement.
+	// If the cache implements [fmt.Stringer], such as
+	// [clockpro.Synced] and [clockpro.Sharded], its
+	// targets and hand positions are served too.
+	Cache[Key comparable] interface {
+		Stats() clockpro.Stats
+		Len() int
+		Keys() iter.Seq[Key]
+	}
+	// report is the document served by [Handler].
+	report struct {
+		Stats    clockpro.Stats `json:"stats"`
+		HitRatio float64        `json:"hitRatio"`
+		Len      int            `json:"len"`
+		Clock    []string       `json:"clock,omitempty"`
+		Keys     []string       `json:"keys,omitempty"`
+	}
+)
+
+// MaxKeys is the most keys that [Handler] lists.
+const MaxKeys = 1000
+
+var page = template.Must(template.New("clockpro").Parse(`<!DOCTYPE html>
+<html>
+<head><title>clockpro</title></head>
+<body>
+<h1>clockpro</h1>
+<p>{{.Len}} resident entries; hit ratio {{printf "%.4f" .HitRatio}}
+(recently {{printf "%.4f" .Stats.RecentHitRatio}})</p>
+{{with .Clock}}<h2>Clock</h2>
+<pre>{{range .}}{{.}}
+{{end}}</pre>{{end}}
+<h2>Stats</h2>
+<table>
+<tr><td>Since</td><td>{{.Stats.Since}}</td></tr>
+<tr><td>Elapsed</td><td>{{.Stats.Elapsed}}</td></tr>
+<tr><td>Hits</td><td>{{.Stats.Hits}}</td></tr>
+<tr><td>Misses</td><td>{{.Stats.Misses}}</td></tr>
+<tr><td>Evictions</td><td>{{.Stats.Evictions}}</td></tr>
+<tr><td>Expirations</td><td>{{.Stats.Expirations}}</td></tr>
+<tr><td>Rejections</td><td>{{.Stats.Rejections}}</td></tr>
+<tr><td>Resurrections</td><td>{{.Stats.Resurrections}}</td></tr>
+<tr><td>Promotions</td><td>{{.Stats.Promotions}}</td></tr>
+<tr><td>Demotions</td><td>{{.Stats.Demotions}}</td></tr>
+<tr><td>TestRemovals</td><td>{{.Stats.TestRemovals}}</td></tr>
+</table>
+{{with .Keys}}<h2>Keys</h2>
+<ul>{{range .}}<li>{{.}}</li>{{end}}</ul>{{end}}
+<p><a href="?format=json">JSON</a></p>
+</body>
+</html>
+`))
+
+// Handler returns a handler which serves the stats of cache,
+// as HTML, or as JSON if the "format" query parameter is "json".
+// If the "keys" parameter is provided, up to that many
+// (and at most [MaxKeys]) resident keys are also listed,
+// in no particular order.
+func Handler[Key comparable](cache Cache[Key]) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		var limit int
+		if keys := query.Get("keys"); keys != "" {
+			var err error
+			if limit, err = strconv.Atoi(keys); err != nil || limit < 0 {
+				http.Error(w, fmt.Sprintf("invalid key count %q", keys), http.StatusBadRequest)
+				return
+			}
+			limit = min(limit, MaxKeys)
+		}
+		stats := cache.Stats()
+		document := report{
+			Stats:    stats,
+			HitRatio: stats.HitRatio(),
+			Len:      cache.Len(),
+		}
+		if stringer, ok := cache.(fmt.Stringer); ok {
+			document.Clock = strings.Split(stringer.String(), "\n")
+		}
+		if limit != 0 {
+			for key := range cache.Keys() {
+				document.Keys = append(document.Keys, fmt.Sprint(key))
+				if len(document.Keys) == limit {
+					break
+				}
+			}
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		if query.Get("format") == "json" {
+			w.Header().Set("Content-Type", "application/json")
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "\t")
+			encoder.Encode(document)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		page.Execute(w, document)
+	})
+}
@@ -0,0 +1,101 @@
+package debughttp_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+	"github.com/djdv/go-clockpro/debughttp"
+)
+
+const capacity = 8
+
+func TestHandler(t *testing.T) {
+	t.Run("json", handlerJSON)
+	t.Run("html", handlerHTML)
+	t.Run("invalid keys", handlerInvalidKeys)
+}
+
+func newHandler(t *testing.T) http.Handler {
+	t.Helper()
+	cache, err := clockpro.NewSynced[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key := range capacity * 2 {
+		cache.Set(key, key)
+		cache.Get(key)
+	}
+	return debughttp.Handler(cache)
+}
+
+func serve(handler http.Handler, target string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+	return recorder
+}
+
+func handlerJSON(t *testing.T) {
+	t.Parallel()
+	const keys = 3
+	response := serve(newHandler(t), "/debug/clockpro?format=json&keys=3")
+	if response.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d", response.Code)
+	}
+	var document struct {
+		Stats clockpro.Stats
+		Len   int
+		Clock []string
+		Keys  []string
+	}
+	if err := json.NewDecoder(response.Body).Decode(&document); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := document.Stats.Hits, uint64(capacity*2); got != want {
+		t.Errorf(
+			"unexpected hits"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			got, want)
+	}
+	if document.Len != capacity || len(document.Keys) != keys {
+		t.Errorf(
+			"unexpected entries"+
+				"\n\tgot: %d resident, %d keys listed"+
+				"\n\twant: %d resident, %d keys listed",
+			document.Len, len(document.Keys), capacity, keys)
+	}
+	if len(document.Clock) != 1 || !strings.Contains(document.Clock[0], "hands") {
+		t.Errorf("expected a summary of the clock but got: %q", document.Clock)
+	}
+}
+
+func handlerHTML(t *testing.T) {
+	t.Parallel()
+	response := serve(newHandler(t), "/debug/clockpro")
+	if got := response.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/html") {
+		t.Errorf("unexpected content type: %s", got)
+	}
+	body := response.Body.String()
+	for _, want := range []string{"Evictions", "capacity 8", "<table>"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("page does not contain %q", want)
+		}
+	}
+}
+
+func handlerInvalidKeys(t *testing.T) {
+	t.Parallel()
+	for _, keys := range []string{"-1", "many"} {
+		if response := serve(newHandler(t), "/?keys="+keys); response.Code != http.StatusBadRequest {
+			t.Errorf(
+				"unexpected status for keys=%s"+
+					"\n\tgot: %d"+
+					"\n\twant: %d",
+				keys, response.Code, http.StatusBadRequest)
+		}
+	}
+}
@@ -85,6 +85,8 @@
 //
 //   - coldTarget ∈ [1, capacit/2], hotTarget = capacity - coldTarget.
 //
+//     The bounds may be configured with [WithColdTargetBounds].
+//
 //     This is some point within the range that the hot and cold page sizes can be adapted to.
 //
 //   - Metadata is bounded to: hotCount + coldCount + testCount ≤ 2 * size.
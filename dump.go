@@ -0,0 +1,90 @@
+package clockpro
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Dump writes a deterministic textual description
+// of the clock to w: the capacity, targets, and counts,
+// followed by one line for each page, from the least
+// to the most recently inserted, with its class,
+// flags, key, and the hands that point to it.
+// Flags are R (referenced), D (demoted), and S (stacked),
+// with "-" in place of each that is unset.
+// Dumping does not count as an access.
+func (c *Cache[Key, Value]) Dump(w io.Writer) error {
+	var buffer bytes.Buffer
+	c.summarize(&buffer, "\n")
+	buffer.WriteByte('\n')
+	for entry := range c.clock.All() {
+		fmt.Fprintf(&buffer, "%-4s %s %v", classOf(entry), flagsOf(entry), entry.Name)
+		for _, hand := range c.hands() {
+			if hand.page == entry {
+				buffer.WriteString(" <" + hand.name)
+			}
+		}
+		buffer.WriteByte('\n')
+	}
+	_, err := buffer.WriteTo(w)
+	return err
+}
+
+// String summarizes the clock on a single line:
+// the capacity, targets, counts, and the key
+// that each hand points to, or "-" if none.
+// See [Cache.Dump] for the state of each page.
+func (c *Cache[Key, Value]) String() string {
+	var builder strings.Builder
+	c.summarize(&builder, "; ")
+	builder.WriteString("; hands")
+	for i, hand := range c.hands() {
+		if i != 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteString(" " + hand.name + " ")
+		if hand.page == nil {
+			builder.WriteByte('-')
+		} else {
+			fmt.Fprint(&builder, hand.page.Name)
+		}
+	}
+	return builder.String()
+}
+
+func (c *Cache[_, _]) summarize(w io.Writer, separator string) {
+	fmt.Fprintf(w,
+		"capacity %d; targets %d hot, %d cold (%d-%d)%s"+
+			"pages %d hot, %d cold, %d test; %d demoted",
+		c.capacity, c.hotTarget, c.coldTarget, c.coldMinimum, c.coldMaximum,
+		separator,
+		c.hotCount, c.coldCount, c.testCount, c.demotions,
+	)
+}
+
+type hand[Key comparable, Value any] struct {
+	name string
+	page *page[Key, Value]
+}
+
+func (c *Cache[Key, Value]) hands() []hand[Key, Value] {
+	return []hand[Key, Value]{
+		{"hot", c.hot}, {"cold", c.cold}, {"test", c.test}, {"lru", c.clock.Back()},
+	}
+}
+
+func flagsOf[Key comparable, Value any](page *page[Key, Value]) string {
+	flags := []byte("---")
+	if page.Referenced || page.Touched() {
+		flags[0] = 'R'
+	}
+	if page.Demoted {
+		flags[1] = 'D'
+	}
+	if page.Stacked {
+		flags[2] = 'S'
+	}
+	return string(flags)
+}
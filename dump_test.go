@@ -0,0 +1,144 @@
+package clockpro_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// TestDump compares the state of the clock after canonical
+// sequences of operations against golden files.
+// Run with -update to accept changes to hand behavior.
+func TestDump(t *testing.T) {
+	const capacity = 4
+	for _, test := range []struct {
+		name     string
+		sequence func(*clockpro.Cache[int, int])
+	}{
+		{"empty", func(*clockpro.Cache[int, int]) {}},
+		{"fill", func(cache *clockpro.Cache[int, int]) {
+			fill(cache, 0, capacity)
+		}},
+		{"evict", func(cache *clockpro.Cache[int, int]) {
+			fill(cache, 0, capacity*2)
+		}},
+		{"referenced", func(cache *clockpro.Cache[int, int]) {
+			fill(cache, 0, capacity)
+			cache.Get(capacity - 1)
+			fill(cache, capacity, capacity+2)
+		}},
+		{"resurrect", func(cache *clockpro.Cache[int, int]) {
+			fill(cache, 0, capacity+2)
+			cache.Set(capacity-1, capacity-1)
+		}},
+		{"rereference", func(cache *clockpro.Cache[int, int]) {
+			fill(cache, 0, capacity+2)
+			for key := range capacity - 1 {
+				cache.Get(key)
+			}
+		}},
+		{"invalidate", func(cache *clockpro.Cache[int, int]) {
+			fill(cache, 0, capacity+1)
+			cache.Invalidate(capacity)
+			cache.Delete(1)
+		}},
+		{"epoch", func(cache *clockpro.Cache[int, int]) {
+			fill(cache, 0, capacity+2)
+			cache.Get(0)
+			cache.NewEpoch()
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			cache, err := clockpro.New[int, int](capacity)
+			if err != nil {
+				t.Fatal(err)
+			}
+			test.sequence(cache)
+			if err := cache.CheckInvariants(); err != nil {
+				t.Fatal(err)
+			}
+			var got bytes.Buffer
+			if err := cache.Dump(&got); err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, test.name, got.Bytes())
+		})
+	}
+}
+
+func fill(cache *clockpro.Cache[int, int], start, end int) {
+	for key := start; key < end; key++ {
+		cache.Set(key, key)
+	}
+}
+
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", "dump", name+".golden")
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf(
+			"state differs from %s"+
+				"\n\tgot:\n%s"+
+				"\n\twant:\n%s",
+			path, got, want,
+		)
+	}
+}
+
+func TestString(t *testing.T) {
+	t.Parallel()
+	const capacity = 4
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		name, want string
+		sequence   func()
+	}{
+		{
+			"empty",
+			"capacity 4; targets 3 hot, 1 cold (1-2); " +
+				"pages 0 hot, 0 cold, 0 test; 0 demoted; " +
+				"hands hot -, cold -, test -, lru -",
+			func() {},
+		},
+		{
+			"evict",
+			"capacity 4; targets 3 hot, 1 cold (1-2); " +
+				"pages 3 hot, 1 cold, 2 test; 0 demoted; " +
+				"hands hot 0, cold 5, test 3, lru 5",
+			func() { fill(cache, 0, capacity+2) },
+		},
+	} {
+		test.sequence()
+		if got := cache.String(); got != test.want {
+			t.Errorf(
+				"%s: unexpected summary"+
+					"\n\tgot: %s"+
+					"\n\twant: %s",
+				test.name, got, test.want,
+			)
+		}
+	}
+}
@@ -4,8 +4,32 @@ import "fmt"
 
 type constError string
 
-// ErrInvalidCapacity may be returned from [New].
-const ErrInvalidCapacity = constError("invalid capacity")
+const (
+	// ErrInvalidCapacity may be returned from [New].
+	ErrInvalidCapacity = constError("invalid capacity")
+	// ErrInvalidSize may be returned from constructors
+	// of auxiliary types, such as [NewAdaptationHistory].
+	ErrInvalidSize = constError("invalid size")
+	// ErrInvalidOption may be returned from [New]
+	// if an [Option] was provided an invalid value.
+	ErrInvalidOption = constError("invalid option")
+	// ErrInvalidTarget may be returned from [Cache.SetColdTarget].
+	ErrInvalidTarget = constError("invalid target")
+	// ErrReplayMismatch may be returned from [Replay].
+	ErrReplayMismatch = constError("replay mismatch")
+	// ErrNotFound is returned from [Cache.Fetch]
+	// if the key is not resident, and from [Batcher.Load]
+	// and [Cache.LoadManyPartial] if the key was not fetched.
+	ErrNotFound = constError("not found")
+	// ErrInvariant may be returned from [Cache.CheckInvariants].
+	ErrInvariant = constError("invariant violated")
+	// ErrFetchFailed wraps errors returned by the fetch
+	// functions of methods such as [Cache.Load].
+	ErrFetchFailed = constError("fetch failed")
+	// ErrClosed may be returned from methods of an [Actor]
+	// which could not be performed because it was closed.
+	ErrClosed = constError("closed")
+)
 
 func (errStr constError) Error() string { return string(errStr) }
 
@@ -14,3 +38,13 @@ func minCapacityError(capacity int) error {
 		"%w: must be >=%d but %d was requested",
 		ErrInvalidCapacity, MinimumCapacity, capacity)
 }
+
+func fetchError(err error) error {
+	return fmt.Errorf("%w: %w", ErrFetchFailed, err)
+}
+
+func minHistoryError(size int) error {
+	return fmt.Errorf(
+		"%w: must be >=1 but %d was requested",
+		ErrInvalidSize, size)
+}
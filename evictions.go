@@ -0,0 +1,89 @@
+package clockpro
+
+import "fmt"
+
+type (
+	// Eviction is a value which was evicted from a [Cache],
+	// with the state of its entry beforehand.
+	// See [WithEvictionChannel].
+	Eviction[Key comparable, Value any] struct {
+		Key   Key
+		Value Value
+		Info  EntryInfo
+	}
+	// EvictionOverflow decides how [Eviction]s are sent
+	// to a channel which is full. See [WithEvictionChannel].
+	EvictionOverflow                           uint8
+	evictionChannel[Key comparable, Value any] struct {
+		channel  chan Eviction[Key, Value]
+		overflow EvictionOverflow
+	}
+)
+
+const (
+	// DropOldestEviction discards the oldest eviction
+	// within the channel, to make room for the newest.
+	DropOldestEviction EvictionOverflow = iota
+	// BlockOnEviction waits for the channel to be received from.
+	// The method which evicted the value does not return until
+	// then, and concurrent caches remain locked meanwhile,
+	// so the receiver must not use the cache.
+	BlockOnEviction
+)
+
+// WithEvictionChannel sends each value which is evicted to
+// make room for others to the channel returned by [Cache.Evictions],
+// which buffers up to size evictions, such that they may be
+// processed by another goroutine, like a write-behind queue.
+// Values which are removed otherwise, such as by [Cache.Delete],
+// or by expiring, are not sent.
+// Once the channel is full, evictions are sent as decided by overflow.
+func WithEvictionChannel[Key comparable, Value any](size int, overflow EvictionOverflow) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if size < 1 {
+			return fmt.Errorf(
+				"%w: eviction channel size must be >=1 but %d was provided",
+				ErrInvalidOption, size,
+			)
+		}
+		if overflow > BlockOnEviction {
+			return fmt.Errorf(
+				"%w: unknown eviction overflow %d",
+				ErrInvalidOption, overflow,
+			)
+		}
+		set.evictions = &evictionChannel[Key, Value]{
+			channel:  make(chan Eviction[Key, Value], size),
+			overflow: overflow,
+		}
+		return nil
+	}
+}
+
+// Evictions returns the channel given evictions by
+// [WithEvictionChannel], or nil if the cache was not
+// constructed with it. The channel is never closed.
+func (c *Cache[Key, Value]) Evictions() <-chan Eviction[Key, Value] {
+	if c.evictions == nil {
+		return nil
+	}
+	return c.evictions.channel
+}
+
+func (ec *evictionChannel[Key, Value]) send(eviction Eviction[Key, Value]) {
+	if ec.overflow == BlockOnEviction {
+		ec.channel <- eviction
+		return
+	}
+	for {
+		select {
+		case ec.channel <- eviction:
+			return
+		default:
+		}
+		select { // Drop the oldest, unless it was received meanwhile.
+		case <-ec.channel:
+		default:
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package clockpro_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestEvictionChannel(t *testing.T) {
+	t.Run("invalid", evictionChannelInvalid)
+	t.Run("drop oldest", evictionChannelDropOldest)
+	t.Run("block", evictionChannelBlock)
+}
+
+func evictionChannelInvalid(t *testing.T) {
+	t.Parallel()
+	for _, option := range []clockpro.Option[int, int]{
+		clockpro.WithEvictionChannel[int, int](0, clockpro.DropOldestEviction),
+		clockpro.WithEvictionChannel[int, int](1, clockpro.BlockOnEviction+1),
+	} {
+		if _, err := clockpro.New(2, option); !errors.Is(err, clockpro.ErrInvalidOption) {
+			t.Errorf(
+				"expected error to match"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				err, clockpro.ErrInvalidOption,
+			)
+		}
+	}
+}
+
+func receiveEvictions(evictions <-chan clockpro.Eviction[int, int]) (keys []int) {
+	for {
+		select {
+		case eviction := <-evictions:
+			if eviction.Key != eviction.Value || eviction.Info.Hot {
+				return nil
+			}
+			keys = append(keys, eviction.Key)
+		default:
+			return keys
+		}
+	}
+}
+
+func evictionChannelDropOldest(t *testing.T) {
+	t.Parallel()
+	const capacity = 2
+	cache, err := clockpro.New(capacity,
+		clockpro.WithEvictionChannel[int, int](2, clockpro.DropOldestEviction),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addIncrementingInts(cache, capacity+3) // Evicts 2, 3, and 4.
+	cache.Delete(1)                        // Not an eviction.
+	got := receiveEvictions(cache.Evictions())
+	if want := []int{3, 4}; !slices.Equal(got, want) {
+		t.Errorf(
+			"unexpected evictions"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			got, want,
+		)
+	}
+}
+
+func evictionChannelBlock(t *testing.T) {
+	t.Parallel()
+	const capacity = 2
+	cache, err := clockpro.NewSynced(capacity,
+		clockpro.WithEvictionChannel[int, int](1, clockpro.BlockOnEviction),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const evictions = 8
+	done := make(chan []int)
+	go func() {
+		var keys []int
+		for eviction := range cache.Evictions() {
+			if keys = append(keys, eviction.Key); len(keys) == evictions {
+				break
+			}
+		}
+		done <- keys
+	}()
+	for key := range capacity + evictions {
+		cache.Set(key, key)
+	}
+	if got := <-done; len(got) != evictions {
+		t.Errorf(
+			"unexpected evictions"+
+				"\n\tgot: %v"+
+				"\n\twant: %d keys",
+			got, evictions,
+		)
+	}
+}
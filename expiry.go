@@ -0,0 +1,257 @@
+package clockpro
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/djdv/go-clockpro/internal/wheel"
+)
+
+type expirations[Key comparable] struct {
+	wheel  *wheel.Wheel[Key]
+	timers map[Key]*wheel.Timer[Key]
+	// limits holds the deadlines scheduled for entries
+	// which also expire once idle, since their timers
+	// are scheduled for the earlier of the two.
+	limits map[Key]int64
+	idle   int64 // See [WithMaxIdle].
+}
+
+// expirationResolution is the granularity
+// of the expiration wheel's ticks.
+const expirationResolution = int64(time.Millisecond)
+
+// WithTimeSource sets the function used by the cache
+// to retrieve the current time. By default, [time.Now] is used.
+func WithTimeSource[Key comparable, Value any](now func() time.Time) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		set.timeSource = now
+		return nil
+	}
+}
+
+// WithMaxIdle schedules every resident entry to expire
+// once idle has elapsed since it was last set or hit,
+// regardless of capacity pressure, or by its TTL if that is earlier.
+// Hits of [Synced] and [Sharded] caches acquire the lock, so that
+// the deadline may be extended. Hits served by [Striped] and [Actor]
+// without the lock do not extend the deadline.
+func WithMaxIdle[Key comparable, Value any](idle time.Duration) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if idle <= 0 {
+			return fmt.Errorf(
+				"%w: max idle duration must be >0 but %v was provided",
+				ErrInvalidOption, idle,
+			)
+		}
+		set.maxIdle = idle
+		return nil
+	}
+}
+
+// SetWithTTL inserts or updates key with value,
+// marks it as referenced, and schedules it to be
+// evicted once ttl has elapsed. Like [Cache.Invalidate],
+// the metadata of an expired entry is retained as a test page,
+// so that a key which is set again promptly is resurrected.
+// A non-positive ttl means the entry does not expire.
+func (c *Cache[Key, Value]) SetWithTTL(key Key, value Value, ttl time.Duration) {
+	c.Set(key, value)
+	if ttl <= 0 {
+		return
+	}
+	if page, ok := c.index.get(key); !ok || !page.Resident {
+		return // Admission was denied.
+	}
+	now := c.now()
+	c.expiry.schedule(key, now.UnixNano(), now.Add(ttl).UnixNano())
+	c.stored(key)
+}
+
+// ExtendTTL schedules the resident entry of key to expire
+// once ttl has elapsed from now, without replacing its value,
+// such as to slide the deadline of a session on activity.
+// A non-positive ttl means the entry does not expire.
+// ExtendTTL does not count as an access, and reports
+// whether key was resident and unexpired.
+func (c *Cache[Key, Value]) ExtendTTL(key Key, ttl time.Duration) bool {
+	if ttl <= 0 {
+		return c.SetExpiry(key, time.Time{})
+	}
+	return c.SetExpiry(key, c.now().Add(ttl))
+}
+
+// SetExpiry is like [Cache.ExtendTTL], but schedules
+// the entry to expire at deadline. The zero time
+// means the entry does not expire.
+func (c *Cache[Key, Value]) SetExpiry(key Key, deadline time.Time) bool {
+	page, ok := c.index.get(key)
+	if !ok || !page.Resident {
+		return false
+	}
+	if c.expired(key) {
+		c.expirePage(page)
+		return false
+	}
+	if deadline.IsZero() {
+		c.expiry.cancel(key)
+	} else {
+		c.expiry.schedule(key, c.now().UnixNano(), deadline.UnixNano())
+	}
+	c.stored(key)
+	return true
+}
+
+// GetWithExpiry is like [Cache.Get], but also returns
+// the time at which the entry expires, or the zero time
+// if it does not expire.
+func (c *Cache[Key, Value]) GetWithExpiry(key Key) (Value, time.Time, bool) {
+	value, ok := c.Get(key)
+	if !ok {
+		return value, time.Time{}, false
+	}
+	deadline, _ := c.expiry.deadline(key)
+	return value, deadlineTime(deadline), true
+}
+
+// Expire evicts all entries whose TTL has elapsed,
+// and returns how many were evicted.
+// Expired entries are otherwise evicted lazily,
+// when accessed, or when the cache is modified.
+func (c *Cache[Key, _]) Expire() int {
+	if !c.expiry.active() {
+		return 0
+	}
+	var (
+		removed int
+		now     = c.now().UnixNano()
+	)
+	c.expiry.wheel.Advance(now, func(timer *wheel.Timer[Key]) {
+		if page, ok := c.index.get(timer.Value); ok {
+			c.expirePage(page)
+			removed++
+		}
+	})
+	return removed
+}
+
+// Janitor evicts the expired entries of a cache from
+// a background goroutine, so that their values are
+// released even while the cache is not otherwise used.
+// Without a janitor, the expiration wheel is only
+// advanced when entries are accessed, or when the
+// cache is modified.
+type Janitor struct {
+	stop    func()
+	expired atomic.Uint64
+}
+
+// NewJanitor calls cache.Expire every interval,
+// from a new goroutine, until [Janitor.Stop] is called.
+// The cache must be safe for concurrent use,
+// such as [Synced] or [Sharded].
+func NewJanitor(cache interface{ Expire() int }, interval time.Duration) *Janitor {
+	janitor := new(Janitor)
+	janitor.stop = every(interval, func() {
+		janitor.expired.Add(uint64(cache.Expire()))
+	})
+	return janitor
+}
+
+// Expired returns how many entries the janitor has evicted.
+func (j *Janitor) Expired() uint64 { return j.expired.Load() }
+
+// Stop stops the janitor's goroutine.
+// Stop may be called more than once.
+func (j *Janitor) Stop() { j.stop() }
+
+// expirePage evicts the page of an expired entry,
+// as if it was invalidated.
+func (c *Cache[Key, Value]) expirePage(page *page[Key, Value]) {
+	c.stats.total.expirations++
+	c.recordOperation(OperationInvalidate, page.Name)
+	c.invalidate(page)
+}
+
+// deadlineTime converts a deadline in Unix nanoseconds
+// to a time, where 0 is the zero time.
+func deadlineTime(deadline int64) time.Time {
+	if deadline == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, deadline)
+}
+
+func (c *Cache[_, _]) now() time.Time {
+	if now := c.timeSource; now != nil {
+		return now()
+	}
+	return time.Now()
+}
+
+// expired reports whether key has a deadline
+// which has passed.
+func (c *Cache[Key, _]) expired(key Key) bool {
+	if !c.expiry.active() {
+		return false
+	}
+	deadline, ok := c.expiry.deadline(key)
+	return ok && deadline <= c.now().UnixNano()
+}
+
+func (ex *expirations[_]) active() bool {
+	return len(ex.timers) != 0
+}
+
+func (ex *expirations[Key]) schedule(key Key, now, deadline int64) {
+	if ex.idle != 0 {
+		if ex.limits == nil {
+			ex.limits = make(map[Key]int64)
+		}
+		ex.limits[key] = deadline
+		deadline = min(deadline, now+ex.idle)
+	}
+	ex.arm(key, now, deadline)
+}
+
+// extend reschedules the timer of key for when it
+// becomes idle, unless its scheduled deadline is earlier.
+func (ex *expirations[Key]) extend(key Key, now int64) {
+	deadline := now + ex.idle
+	if limit, ok := ex.limits[key]; ok {
+		deadline = min(deadline, limit)
+	}
+	ex.arm(key, now, deadline)
+}
+
+func (ex *expirations[Key]) arm(key Key, now, deadline int64) {
+	if ex.timers == nil {
+		ex.timers = make(map[Key]*wheel.Timer[Key])
+		ex.wheel = wheel.New[Key](now, expirationResolution)
+	}
+	timer, ok := ex.timers[key]
+	if !ok {
+		timer = &wheel.Timer[Key]{Value: key}
+		ex.timers[key] = timer
+	}
+	ex.wheel.Schedule(timer, deadline)
+}
+
+func (ex *expirations[Key]) deadline(key Key) (int64, bool) {
+	if timer, ok := ex.timers[key]; ok {
+		return timer.Deadline(), true
+	}
+	return 0, false
+}
+
+func (ex *expirations[Key]) cancel(key Key) {
+	if !ex.active() {
+		return
+	}
+	if timer, ok := ex.timers[key]; ok {
+		ex.wheel.Cancel(timer)
+		delete(ex.timers, key)
+	}
+	delete(ex.limits, key)
+}
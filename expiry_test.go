@@ -0,0 +1,369 @@
+package clockpro_test
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/djdv/go-clockpro"
+)
+
+type fakeClock struct{ now time.Time }
+
+func (fc *fakeClock) Now() time.Time { return fc.now }
+
+func (fc *fakeClock) advance(d time.Duration) { fc.now = fc.now.Add(d) }
+
+func TestExpiration(t *testing.T) {
+	t.Run("lazy", expireLazy)
+	t.Run("expire", expireAll)
+	t.Run("set clears ttl", setClearsTTL)
+	t.Run("random", expireRandom)
+	t.Run("test page", expireToTest)
+	t.Run("get with expiry", getWithExpiry)
+	t.Run("extend", expireExtend)
+	t.Run("max idle", expireIdle)
+	t.Run("janitor", expireJanitor)
+}
+
+func newExpiringCache(tb testing.TB, capacity int) (*clockpro.Cache[int, int], *fakeClock) {
+	tb.Helper()
+	var (
+		clock      = &fakeClock{now: time.Unix(0, 0)}
+		timeSource = clockpro.WithTimeSource[int, int](clock.Now)
+	)
+	cache, err := clockpro.New(capacity, timeSource)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return cache, clock
+}
+
+func expireLazy(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 4
+		key      = 1
+		ttl      = time.Second
+	)
+	cache, clock := newExpiringCache(t, capacity)
+	cache.SetWithTTL(key, key, ttl)
+	clock.advance(ttl - 1)
+	mustGet(t, cache, key)
+	clock.advance(1)
+	mustMiss(t, cache, key, "expiration")
+	checkSize(t, cache, 0, "after expiration")
+}
+
+func expireAll(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 8
+		ttl      = time.Second
+	)
+	cache, clock := newExpiringCache(t, capacity)
+	for key := range capacity {
+		if key%2 == 0 {
+			cache.SetWithTTL(key, key, ttl)
+		} else {
+			cache.Set(key, key)
+		}
+	}
+	if removed := cache.Expire(); removed != 0 {
+		t.Fatalf("expected nothing to expire yet, but %d entries were removed", removed)
+	}
+	clock.advance(ttl)
+	const want = capacity / 2
+	if got := cache.Expire(); got != want {
+		t.Fatalf(
+			"unexpected amount of expired entries"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			got, want)
+	}
+	checkSize(t, cache, capacity-want, "after expiration")
+	checkKeyLength(t, cache, capacity-want, "after expiration")
+}
+
+// expireJanitor expects a janitor to evict
+// expired entries while the cache is idle,
+// and to count each of them.
+func expireJanitor(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 64
+		shards   = 4
+		ttl      = time.Millisecond
+	)
+	sharded := newSharded(t, capacity, shards)
+	for key := range capacity {
+		sharded.SetWithTTL(key, key, ttl)
+	}
+	var (
+		resident = uint64(sharded.Len())
+		janitor  = clockpro.NewJanitor(sharded, ttl)
+	)
+	defer janitor.Stop()
+	for deadline := time.Now().Add(time.Minute); janitor.Expired() != resident; {
+		if time.Now().After(deadline) {
+			t.Fatalf(
+				"unexpected amount of expired entries"+
+					"\n\tgot: %d"+
+					"\n\twant: %d",
+				janitor.Expired(), resident)
+		}
+		time.Sleep(ttl)
+	}
+	janitor.Stop()
+	janitor.Stop()
+	if got := sharded.Len(); got != 0 {
+		t.Errorf("%d expired entries were not evicted", got)
+	}
+}
+
+func expireToTest(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 4
+		ttl      = time.Second
+	)
+	cache, clock := newExpiringCache(t, capacity)
+	for key := range capacity {
+		cache.SetWithTTL(key, key, ttl)
+	}
+	clock.advance(ttl)
+	mustMiss(t, cache, 0, "expiration")
+	cache.Expire()
+	for key := range capacity {
+		if info, ok := cache.Inspect(key); !ok || info.Resident {
+			t.Errorf("expired key %d is not a test page", key)
+		}
+	}
+	if outcome := cache.SetReport(1, 1); outcome != clockpro.SetResurrected {
+		t.Errorf(
+			"expired key was not resurrected"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			outcome, clockpro.SetResurrected)
+	}
+	cache.SetWithTTL(1, 1, ttl)
+	clock.advance(ttl)
+	if cache.Delete(1) {
+		t.Error("expired value was reported as resident")
+	}
+	if _, ok := cache.Inspect(1); ok {
+		t.Error("deleted key retained a test page")
+	}
+	if err := cache.CheckInvariants(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func getWithExpiry(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 4
+		ttl      = time.Second
+	)
+	type expiringCache interface {
+		SetWithTTL(int, int, time.Duration)
+		GetWithExpiry(int) (int, time.Time, bool)
+	}
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	synced, err := clockpro.NewSynced(capacity,
+		clockpro.WithTimeSource[int, int](clock.Now),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache, err := clockpro.New(capacity,
+		clockpro.WithTimeSource[int, int](clock.Now),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cache := range []expiringCache{cache, synced} {
+		cache.SetWithTTL(1, 1, ttl)
+		cache.SetWithTTL(2, 2, 0)
+		for _, test := range []struct {
+			key      int
+			deadline time.Time
+			ok       bool
+		}{
+			{1, clock.now.Add(ttl), true},
+			{2, time.Time{}, true},
+			{3, time.Time{}, false},
+		} {
+			value, deadline, ok := cache.GetWithExpiry(test.key)
+			if ok != test.ok || !deadline.Equal(test.deadline) ||
+				(ok && value != test.key) {
+				t.Errorf(
+					"%T: unexpected entry for key %d"+
+						"\n\tgot: %d, %v, %t"+
+						"\n\twant: %d, %v, %t",
+					cache, test.key,
+					value, deadline, ok,
+					test.key, test.deadline, test.ok)
+			}
+		}
+	}
+}
+
+func expireExtend(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 4
+		ttl      = time.Second
+	)
+	type extendingCache interface {
+		testCache[int, int]
+		SetWithTTL(int, int, time.Duration)
+		ExtendTTL(int, time.Duration) bool
+		SetExpiry(int, time.Time) bool
+	}
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	synced, err := clockpro.NewSynced(capacity,
+		clockpro.WithTimeSource[int, int](clock.Now),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache, err := clockpro.New(capacity,
+		clockpro.WithTimeSource[int, int](clock.Now),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cache := range []extendingCache{cache, synced} {
+		cache.SetWithTTL(1, 1, ttl)
+		cache.SetWithTTL(2, 2, ttl)
+		clock.advance(ttl - 1)
+		if !cache.ExtendTTL(1, ttl) {
+			t.Errorf("%T: resident key was not extended", cache)
+		}
+		if !cache.SetExpiry(2, time.Time{}) {
+			t.Errorf("%T: resident key's expiry was not cleared", cache)
+		}
+		if cache.ExtendTTL(3, ttl) {
+			t.Errorf("%T: missing key was extended", cache)
+		}
+		clock.advance(ttl - 1)
+		mustGet(t, cache, 1)
+		mustGet(t, cache, 2)
+		clock.advance(1)
+		mustMiss(t, cache, 1, "extended expiration")
+		if !cache.SetExpiry(2, clock.now) {
+			t.Errorf("%T: resident key's expiry was not set", cache)
+		}
+		mustMiss(t, cache, 2, "expiration set to now")
+		if cache.ExtendTTL(2, ttl) {
+			t.Errorf("%T: expired key was extended", cache)
+		}
+	}
+}
+
+func expireIdle(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 4
+		idle     = time.Second
+	)
+	type idleCache interface {
+		testCache[int, int]
+		SetWithTTL(int, int, time.Duration)
+	}
+	if _, err := clockpro.New(capacity,
+		clockpro.WithMaxIdle[int, int](0),
+	); !errors.Is(err, clockpro.ErrInvalidOption) {
+		t.Errorf(
+			"expected error to match"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			err, clockpro.ErrInvalidOption)
+	}
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	options := []clockpro.Option[int, int]{
+		clockpro.WithTimeSource[int, int](clock.Now),
+		clockpro.WithMaxIdle[int, int](idle),
+	}
+	synced, err := clockpro.NewSynced(capacity, options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache, err := clockpro.New(capacity, options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cache := range []idleCache{cache, synced} {
+		cache.Set(1, 1)
+		cache.Set(2, 2)
+		cache.SetWithTTL(3, 3, idle/2)
+		for range 4 { // Hits extend the deadline of 1.
+			clock.advance(idle / 2)
+			mustGet(t, cache, 1)
+		}
+		mustMiss(t, cache, 2, "idle expiration")
+		mustMiss(t, cache, 3, "expiration before idle")
+		clock.advance(idle)
+		mustMiss(t, cache, 1, "idle expiration")
+		checkSize(t, cache, 0, "after idle expiration")
+	}
+	if err := cache.CheckInvariants(); err != nil {
+		t.Fatal(err)
+	}
+	if err := synced.CheckInvariants(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func setClearsTTL(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 4
+		key      = 1
+		ttl      = time.Second
+	)
+	cache, clock := newExpiringCache(t, capacity)
+	cache.SetWithTTL(key, key, ttl)
+	cache.Set(key, key)
+	clock.advance(ttl)
+	mustGet(t, cache, key)
+}
+
+func expireRandom(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity   = 16
+		universe   = capacity * 4
+		operations = 1 << 14
+		maxTTL     = int64(time.Second)
+	)
+	var (
+		cache, clock = newExpiringCache(t, capacity)
+		rng          = rand.New(rand.NewSource(rngSeed))
+	)
+	for range operations {
+		key := rng.Intn(universe)
+		switch rng.Intn(4) {
+		case 0:
+			cache.Get(key)
+		case 1:
+			cache.Set(key, key)
+		case 2:
+			cache.SetWithTTL(key, key, time.Duration(rng.Int63n(maxTTL)))
+		case 3:
+			clock.advance(time.Duration(rng.Int63n(maxTTL / 8)))
+			if rng.Intn(2) == 0 {
+				cache.Expire()
+			}
+		}
+		if got := cache.Len(); got > capacity {
+			t.Fatalf("cache exceeded capacity: %d > %d", got, capacity)
+		}
+		checkKeyLength(t, cache, cache.Len(), "after random operation")
+		if err := cache.CheckInvariants(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package clockpro
+
+import (
+	"iter"
+)
+
+// EntryInfo describes the replacement state of a cache entry.
+type EntryInfo struct {
+	// Hot is true if the entry is a hot (LIR) page.
+	Hot bool
+	// Resident is true if the entry holds a value,
+	// rather than being a test page.
+	Resident bool
+	// Referenced is true if the entry was
+	// accessed since a hand last passed it.
+	Referenced bool
+	// Demoted is true if the entry was demoted
+	// from hot to cold and has not been referenced since.
+	Demoted bool
+	// Stacked is true if the entry is within the recency stack.
+	Stacked bool
+	// Hits counts the lookups which hit the entry
+	// since it became resident, saturating at its maximum.
+	// Only counted if the cache was constructed
+	// with [WithHitCounts].
+	Hits uint16
+	// Tag is the tag provided by [Cache.SetWithTag], if any.
+	Tag any
+	// Age counts the operations on the cache since
+	// the entry was inserted or last referenced.
+	// Only counted if the cache was constructed
+	// with [WithEvictionAges].
+	Age uint64
+	// Size is the size of a resident entry,
+	// as measured by the function given to
+	// [WithSizeAwareEviction], if any.
+	Size int
+}
+
+// Inspect returns the state of the entry of key,
+// including the test pages of evicted keys,
+// and reports whether key is tracked.
+// Inspecting does not count as an access.
+func (c *Cache[Key, Value]) Inspect(key Key) (EntryInfo, bool) {
+	page, ok := c.index.get(key)
+	if !ok {
+		return EntryInfo{}, false
+	}
+	return c.infoOf(page), true
+}
+
+// KeysInfo returns an iterator over the (unordered) keys
+// of every page, including the test pages of evicted keys,
+// along with the state of each, as reported by [Cache.Inspect].
+// Like [Cache.Keys], the cache may be modified during iteration.
+func (c *Cache[Key, Value]) KeysInfo() iter.Seq2[Key, EntryInfo] {
+	return func(yield func(Key, EntryInfo) bool) {
+		for key, page := range c.tracked() {
+			if !yield(key, c.infoOf(page)) {
+				return
+			}
+		}
+	}
+}
+
+// Export calls yield for each resident entry,
+// hot entries before cold, most recently used first,
+// until yield returns false.
+// Exporting does not count as an access.
+// If yield modifies the cache, Export stops after it returns,
+// since the order of the remaining entries is undefined.
+func (c *Cache[Key, Value]) Export(yield func(Key, Value, EntryInfo) bool) {
+	for page := range c.residents() {
+		if !yield(page.Name, c.decoded(page.Value), c.infoOf(page)) {
+			return
+		}
+	}
+}
+
+// ExportWarmList returns the keys of up to n resident entries,
+// in the order of [Cache.Export], so that the most valuable keys
+// come first. The list may be persisted in place of the values,
+// and passed to [Cache.Prime] of the next cache, such as when a
+// process restarts. Listing does not count as an access.
+func (c *Cache[Key, Value]) ExportWarmList(n int) []Key {
+	if n <= 0 {
+		return nil
+	}
+	keys := make([]Key, 0, min(n, c.Len()))
+	for page := range c.residents() {
+		if c.expired(page.Name) {
+			continue
+		}
+		if keys = append(keys, page.Name); len(keys) == n {
+			break
+		}
+	}
+	return keys
+}
+
+// residents returns an iterator over the resident pages,
+// hot pages before cold, most recently used first.
+// Iteration stops if the cache is modified by yield.
+func (c *Cache[Key, Value]) residents() iter.Seq[*page[Key, Value]] {
+	return func(yield func(*page[Key, Value]) bool) {
+		modifications := c.modifications
+		for _, hot := range []bool{true, false} {
+			for page := range c.clock.Backward() {
+				if page.Resident && page.LIR == hot &&
+					(!yield(page) || c.modifications != modifications) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (c *Cache[Key, Value]) infoOf(page *page[Key, Value]) EntryInfo {
+	info := EntryInfo{
+		Hot:        page.LIR,
+		Resident:   page.Resident,
+		Referenced: page.Referenced || page.Touched(),
+		Demoted:    page.Demoted,
+		Stacked:    page.Stacked,
+		Hits:       page.Hits,
+		Tag:        c.tags[page.Name],
+	}
+	if c.trackAges {
+		info.Age = c.operations - page.Accessed
+	}
+	if c.victims != nil && page.Resident {
+		info.Size = c.victims.size(page.Name, page.Value)
+	}
+	return info
+}
@@ -0,0 +1,225 @@
+package clockpro_test
+
+import (
+	"maps"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestExport(t *testing.T) {
+	t.Run("order", exportOrder)
+	t.Run("warm list", exportWarmList)
+	t.Run("stop", exportStop)
+	t.Run("hits", exportHits)
+	t.Run("modified", exportModified)
+	t.Run("inspect", exportInspect)
+	t.Run("keys info", exportKeysInfo)
+}
+
+func exportOrder(t *testing.T) {
+	t.Parallel()
+	const capacity = 2
+	cache := newMergeCache(t, capacity)
+	addIncrementingInts(cache, capacity)
+	cache.Set(3, 3) // Evicts 2 (cold).
+	cache.Set(2, 2) // Resurrects 2 (hot), evicting 3 and demoting 1.
+	var (
+		keys []int
+		hot  []bool
+	)
+	cache.Export(func(key, value int, info clockpro.EntryInfo) bool {
+		if key != value {
+			t.Errorf("value mismatch for key %d"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+				key, value, key,
+			)
+		}
+		keys = append(keys, key)
+		hot = append(hot, info.Hot)
+		return true
+	})
+	var (
+		wantKeys = []int{2, 1}
+		wantHot  = []bool{true, false}
+	)
+	if !slices.Equal(keys, wantKeys) || !slices.Equal(hot, wantHot) {
+		t.Errorf("exported entries do not match"+
+			"\n\tgot: %v %v"+
+			"\n\twant: %v %v",
+			keys, hot, wantKeys, wantHot,
+		)
+	}
+}
+
+func exportWarmList(t *testing.T) {
+	t.Parallel()
+	const capacity = 2
+	cache := newMergeCache(t, capacity)
+	addIncrementingInts(cache, capacity)
+	cache.Set(3, 3) // Evicts 2 (cold).
+	cache.Set(2, 2) // Resurrects 2 (hot), evicting 3 and demoting 1.
+	for _, test := range []struct {
+		n    int
+		want []int
+	}{
+		{0, nil},
+		{1, []int{2}},
+		{capacity + 1, []int{2, 1}},
+	} {
+		if got := cache.ExportWarmList(test.n); !slices.Equal(got, test.want) {
+			t.Errorf("unexpected warm list of %d keys"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+				test.n, got, test.want,
+			)
+		}
+	}
+	primed, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	identity := func(key int) (int, error) { return key, nil }
+	if got := primed.Prime(cache.ExportWarmList(capacity), identity); got != capacity {
+		t.Errorf("expected %d keys to be primed, got: %d", capacity, got)
+	}
+}
+
+func exportStop(t *testing.T) {
+	t.Parallel()
+	const capacity = 8
+	cache := newMergeCache(t, capacity)
+	addIncrementingInts(cache, capacity)
+	var calls int
+	cache.Export(func(int, int, clockpro.EntryInfo) bool {
+		calls++
+		return false
+	})
+	if calls != 1 {
+		t.Errorf("yield was called after returning false"+
+			"\n\tgot: %d calls"+
+			"\n\twant: 1 call",
+			calls,
+		)
+	}
+}
+
+func exportHits(t *testing.T) {
+	t.Parallel()
+	const capacity = 4
+	cache, err := clockpro.New(capacity,
+		clockpro.WithHitCounts[int, int](),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addIncrementingInts(cache, capacity)
+	want := map[int]uint16{1: 0, 2: 1, 3: 3, 4: math.MaxUint16}
+	for key, hits := range want {
+		for range hits {
+			cache.Get(key)
+		}
+	}
+	cache.Get(4) // Saturated.
+	cache.Export(func(key, _ int, info clockpro.EntryInfo) bool {
+		if info.Hits != want[key] {
+			t.Errorf("unexpected hits for key %d"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+				key, info.Hits, want[key],
+			)
+		}
+		return true
+	})
+}
+
+func exportModified(t *testing.T) {
+	t.Parallel()
+	const capacity = 8
+	cache := newMergeCache(t, capacity)
+	addIncrementingInts(cache, capacity)
+	var calls int
+	cache.Export(func(key, _ int, _ clockpro.EntryInfo) bool {
+		calls++
+		cache.Delete(key)
+		return true
+	})
+	if calls != 1 {
+		t.Errorf("yield was called after modifying the cache"+
+			"\n\tgot: %d calls"+
+			"\n\twant: 1 call",
+			calls,
+		)
+	}
+	if err := cache.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+}
+
+func exportInspect(t *testing.T) {
+	t.Parallel()
+	const capacity = 2
+	cache, err := clockpro.New(capacity,
+		clockpro.WithEvictionAges[int, int](),
+		clockpro.WithSizeAwareEviction(func(_, value int) int { return value * 10 }, 1),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addIncrementingInts(cache, capacity)
+	cache.Set(3, 3) // Evicts 2 (cold).
+	cache.Get(1)
+	for _, test := range []struct {
+		key     int
+		want    clockpro.EntryInfo
+		tracked bool
+	}{
+		{1, clockpro.EntryInfo{Hot: true, Resident: true, Referenced: true, Stacked: true, Size: 10}, true},
+		{2, clockpro.EntryInfo{Stacked: true, Age: 2}, true},
+		{3, clockpro.EntryInfo{Resident: true, Stacked: true, Age: 1, Size: 30}, true},
+		{4, clockpro.EntryInfo{}, false},
+	} {
+		if got, tracked := cache.Inspect(test.key); got != test.want || tracked != test.tracked {
+			t.Errorf(
+				"unexpected state of key %d"+
+					"\n\tgot: %+v, %t"+
+					"\n\twant: %+v, %t",
+				test.key, got, tracked, test.want, test.tracked,
+			)
+		}
+	}
+}
+
+func exportKeysInfo(t *testing.T) {
+	t.Parallel()
+	const capacity = 4
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addIncrementingInts(cache, capacity*2)
+	cache.Get(capacity * 2)
+	var keys int
+	for key, got := range cache.KeysInfo() {
+		keys++
+		if want, _ := cache.Inspect(key); got != want {
+			t.Errorf(
+				"unexpected state of key %d"+
+					"\n\tgot: %+v"+
+					"\n\twant: %+v",
+				key, got, want,
+			)
+		}
+	}
+	if want := len(maps.Collect(cache.Pages())); keys != want {
+		t.Errorf(
+			"unexpected key count"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			keys, want,
+		)
+	}
+}
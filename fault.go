@@ -0,0 +1,89 @@
+package clockpro
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// FaultPoint identifies a point within the operations
+// of a cache at which faults may be injected.
+// See [WithFaultInjection].
+type FaultPoint uint8
+
+const (
+	// FaultEvict precedes the eviction of a resident page,
+	// with its key.
+	FaultEvict FaultPoint = iota + 1
+	// FaultSweep precedes each sweep of the hot and cold hands,
+	// with the key of the page at the hand.
+	FaultSweep
+	// FaultFetch precedes each call of the fetch function
+	// of Load (and its variants), including retries,
+	// with the key being fetched.
+	FaultFetch
+)
+
+// WithFaultInjection calls inject at each [FaultPoint],
+// such that tests may delay operations, by blocking within
+// inject, to provoke adverse interleavings of concurrent callers,
+// or fail them. An error returned at [FaultFetch] fails the fetch
+// as if it was returned by the fetch function, which is not called.
+// At other points, where the cache cannot fail, the cache
+// panics with the error, as if it had a defect.
+// inject is called synchronously, while concurrent caches
+// are locked, except for [FaultFetch], and must not
+// call back into the cache.
+func WithFaultInjection[Key comparable, Value any](inject func(FaultPoint, Key) error) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if inject == nil {
+			return fmt.Errorf(
+				"%w: fault injection function must not be nil",
+				ErrInvalidOption,
+			)
+		}
+		set.faults = inject
+		return nil
+	}
+}
+
+// fault calls the function given to [WithFaultInjection],
+// if any, and panics with the error it returns.
+func (set *settings[Key, _]) fault(point FaultPoint, key Key) {
+	if inject := set.faults; inject != nil {
+		if err := inject(point, key); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// faultyFetch returns fetch, preceded by
+// the function given to [WithFaultInjection], if any.
+func (set *settings[Key, Value]) faultyFetch(key Key, fetch fetcher[Value]) fetcher[Value] {
+	inject := set.faults
+	if inject == nil {
+		return fetch
+	}
+	return fetcher[Value]{
+		withContext: func(ctx context.Context) (Value, error) {
+			if err := inject(FaultFetch, key); err != nil {
+				var zero Value
+				return zero, err
+			}
+			return fetch.call(ctx)
+		},
+	}
+}
+
+func (point FaultPoint) String() string {
+	switch point {
+	case FaultEvict:
+		return "evict"
+	case FaultSweep:
+		return "sweep"
+	case FaultFetch:
+		return "fetch"
+	default:
+		return "FaultPoint(" + strconv.Itoa(int(point)) + ")"
+	}
+}
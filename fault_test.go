@@ -0,0 +1,130 @@
+package clockpro_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestFaultInjection(t *testing.T) {
+	t.Run("points", faultPoints)
+	t.Run("panics", faultPanics)
+	t.Run("delays", faultDelays)
+}
+
+func faultPoints(t *testing.T) {
+	t.Parallel()
+	const capacity = 4
+	var (
+		injected = make(map[clockpro.FaultPoint]int)
+		fetchErr = errors.New("injected")
+		inject   = func(point clockpro.FaultPoint, key int) error {
+			injected[point]++
+			if point == clockpro.FaultFetch && key < 0 {
+				return fetchErr
+			}
+			return nil
+		}
+		cache, err = clockpro.New(capacity,
+			clockpro.WithFaultInjection[int, int](inject),
+		)
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addIncrementingInts(cache, capacity*2)
+	var fetched bool
+	_, err = cache.Load(-1, func() (int, error) {
+		fetched = true
+		return 0, nil
+	})
+	if !errors.Is(err, fetchErr) || !errors.Is(err, clockpro.ErrFetchFailed) || fetched {
+		t.Errorf("expected injected fetch error without fetching, got: %v (fetched: %t)", err, fetched)
+	}
+	for _, point := range []clockpro.FaultPoint{
+		clockpro.FaultEvict,
+		clockpro.FaultSweep,
+		clockpro.FaultFetch,
+	} {
+		if injected[point] == 0 {
+			t.Errorf("expected faults to be injected at point %s", point)
+		}
+	}
+}
+
+func faultPanics(t *testing.T) {
+	t.Parallel()
+	var (
+		defect = errors.New("injected")
+		inject = func(point clockpro.FaultPoint, _ int) error {
+			if point == clockpro.FaultEvict {
+				return defect
+			}
+			return nil
+		}
+		cache, err = clockpro.NewSynced(2,
+			clockpro.WithFaultInjection[int, int](inject),
+		)
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+	func() {
+		defer func() {
+			if recovered := recover(); recovered != defect {
+				t.Errorf(
+					"unexpected panic"+
+						"\n\tgot: %v"+
+						"\n\twant: %v",
+					recovered, defect,
+				)
+			}
+		}()
+		cache.Set(3, 3)
+	}()
+	cache.Get(1) // The lock must have been released.
+}
+
+func faultDelays(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 16
+		workers  = 4
+		keys     = capacity * 4
+	)
+	var (
+		inject = func(clockpro.FaultPoint, int) error {
+			time.Sleep(time.Microsecond)
+			return nil
+		}
+		cache, err = clockpro.NewSynced(capacity,
+			clockpro.WithFaultInjection[int, int](inject),
+		)
+		wg sync.WaitGroup
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for worker := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range keys * 4 {
+				key := (i*7 + worker) % keys
+				if _, err := cache.Load(key, func() (int, error) { return key, nil }); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if err := cache.CheckInvariants(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,55 @@
+package clockpro
+
+import (
+	"fmt"
+	"io"
+)
+
+// WithFinalizer calls finalize exactly once with each value
+// that the cache discards: values which are evicted, invalidated,
+// expired, replaced, deleted, or purged (after they are passed
+// to the function given to [Cache.Purge]).
+// Values which are returned to the caller, such as by
+// [Cache.Remove] and [Cache.SetGetEvicted], are not finalized.
+// Like hooks, finalize receives stored values; see [WithEncode].
+// finalize is called synchronously unless [WithAsyncRelease] is used,
+// in which case it must not call back into the cache.
+func WithFinalizer[Key comparable, Value any](finalize func(Key, Value)) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if finalize == nil {
+			return fmt.Errorf(
+				"%w: finalizer must not be nil",
+				ErrInvalidOption,
+			)
+		}
+		set.finalizer = finalize
+		return nil
+	}
+}
+
+// WithClose is like [WithFinalizer], closing the values
+// which implement [io.Closer]. If handle is not nil,
+// it is called with the errors returned by Close.
+func WithClose[Key comparable, Value any](handle func(Key, error)) Option[Key, Value] {
+	return WithFinalizer(func(key Key, value Value) {
+		closer, ok := any(value).(io.Closer)
+		if !ok {
+			return
+		}
+		if err := closer.Close(); err != nil && handle != nil {
+			handle(key, err)
+		}
+	})
+}
+
+// WithRecycler is like [WithFinalizer], for returning
+// discarded values, such as buffers, to a pool.
+// Since a value may be recycled as soon as it is discarded,
+// values returned by lookups must not be used after
+// their entry may have been modified.
+func WithRecycler[Key comparable, Value any](recycle func(Value)) Option[Key, Value] {
+	if recycle == nil {
+		return WithFinalizer[Key, Value](nil)
+	}
+	return WithFinalizer(func(_ Key, value Value) { recycle(value) })
+}
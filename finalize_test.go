@@ -0,0 +1,240 @@
+package clockpro_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+type closeCounter struct {
+	closes int
+	weight int
+	err    error
+}
+
+func (cc *closeCounter) Close() error {
+	cc.closes++
+	return cc.err
+}
+
+func TestFinalizer(t *testing.T) {
+	t.Run("invalid", finalizerInvalid)
+	t.Run("close", finalizerClose)
+	t.Run("weight", finalizerWeight)
+	t.Run("async", finalizerAsync)
+	t.Run("recycler", finalizerRecycler)
+}
+
+// finalizerWeight evicts several values by weight
+// within [clockpro.Cache.SetGetEvicted], and checks that
+// each is closed exactly once, unless it was returned.
+func finalizerWeight(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity  = 8
+		maxWeight = 10
+	)
+	cache, err := clockpro.New(capacity,
+		clockpro.WithClose[int, *closeCounter](nil),
+		clockpro.WithMaxWeight(func(_ int, value *closeCounter) int {
+			return value.weight
+		}, maxWeight),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := make(map[int]*closeCounter)
+	for key := range 4 {
+		values[key] = &closeCounter{weight: 2}
+		cache.Set(key, values[key])
+	}
+	values[100] = &closeCounter{weight: 8}
+	evictedKey, evicted, ok := cache.SetGetEvicted(100, values[100])
+	if !ok || evicted != values[evictedKey] {
+		t.Fatalf("expected an evicted value to be returned, got: %d %v %t",
+			evictedKey, evicted, ok)
+	}
+	resident := make(map[int]bool)
+	for key := range cache.Keys() {
+		resident[key] = true
+	}
+	if len(resident) == len(values)-1 {
+		t.Fatal("expected several values to be evicted by weight")
+	}
+	for key, value := range values {
+		want := 1
+		if resident[key] || key == evictedKey {
+			want = 0
+		}
+		if value.closes != want {
+			t.Errorf(
+				"unexpected closes of key %d"+
+					"\n\tgot: %d"+
+					"\n\twant: %d",
+				key, value.closes, want,
+			)
+		}
+	}
+}
+
+func finalizerInvalid(t *testing.T) {
+	t.Parallel()
+	for _, option := range []clockpro.Option[int, int]{
+		clockpro.WithFinalizer[int, int](nil),
+		clockpro.WithRecycler[int, int](nil),
+	} {
+		if _, err := clockpro.New(2, option); !errors.Is(err, clockpro.ErrInvalidOption) {
+			t.Errorf(
+				"expected error to match"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				err, clockpro.ErrInvalidOption)
+		}
+	}
+}
+
+// finalizerClose discards values in every way,
+// and checks that each is closed exactly once,
+// unless it was returned.
+func finalizerClose(t *testing.T) {
+	t.Parallel()
+	var (
+		errClose   = errors.New("close failed")
+		failed     []int
+		values     []*closeCounter
+		cache, err = clockpro.New(2,
+			clockpro.WithClose[int, *closeCounter](func(key int, err error) {
+				if errors.Is(err, errClose) {
+					failed = append(failed, key)
+				}
+			}),
+		)
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value := func() *closeCounter {
+		value := new(closeCounter)
+		values = append(values, value)
+		return value
+	}
+	cache.Set(1, value())
+	cache.Set(2, value())
+	cache.Set(1, value()) // Replaces 1.
+	cache.Set(3, value()) // Evicts 2.
+	cache.Invalidate(3)   // Evicts 3.
+	cache.Set(4, value()) // Inserted as cold.
+	cache.Delete(4)       // Deletes 4.
+	returned, _ := cache.Remove(1)
+	cache.Set(5, value())
+	cache.Set(6, value())
+	_, evicted, ok := cache.SetGetEvicted(7, value())
+	failing := value()
+	failing.err = errClose
+	cache.Set(8, failing)
+	cache.Purge(func(_ int, value *closeCounter) {
+		if value.closes != 0 {
+			t.Error("purged value was closed before it was passed to Purge")
+		}
+	})
+	for i, value := range values {
+		want := 1
+		if value == returned || (ok && value == evicted) {
+			want = 0
+		}
+		if value.closes != want {
+			t.Errorf(
+				"unexpected closes of value %d"+
+					"\n\tgot: %d"+
+					"\n\twant: %d",
+				i, value.closes, want,
+			)
+		}
+	}
+	if len(failed) != 1 || failed[0] != 8 {
+		t.Errorf(
+			"unexpected close errors"+
+				"\n\tgot: %v"+
+				"\n\twant: [8]",
+			failed,
+		)
+	}
+}
+
+func finalizerAsync(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 4
+		keys     = capacity * 4
+	)
+	var (
+		finalized  = make(chan int, keys)
+		cache, err = clockpro.New(capacity,
+			clockpro.WithFinalizer(func(key, _ int) { finalized <- key }),
+			clockpro.WithAsyncRelease[int, int](2, 1),
+		)
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addIncrementingInts(cache, keys)
+	cache.Flush()
+	if got, want := len(finalized), keys-cache.Len(); got != want {
+		t.Errorf(
+			"unexpected finalizations"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			got, want,
+		)
+	}
+}
+
+// finalizerRecycler reuses buffers from a free list,
+// and checks that buffers are only reused once discarded.
+func finalizerRecycler(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 4
+		keys     = capacity * 4
+	)
+	var (
+		free       [][]byte
+		allocated  int
+		cache, err = clockpro.New(capacity,
+			clockpro.WithRecycler[int](func(buffer []byte) {
+				free = append(free, buffer[:0])
+			}),
+		)
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buffer := func() []byte {
+		if last := len(free) - 1; last >= 0 {
+			buffer := free[last]
+			free = free[:last]
+			return buffer
+		}
+		allocated++
+		return make([]byte, 0, 8)
+	}
+	for round := range 2 {
+		for key := range keys {
+			cache.Set(key, append(buffer(), byte(key), byte(round)))
+		}
+	}
+	for key := range cache.Keys() {
+		if value, _ := cache.Get(key); value[0] != byte(key) || value[1] != 1 {
+			t.Errorf("buffer of key %d was reused while resident: %v", key, value)
+		}
+	}
+	if want := capacity + 1; allocated != want {
+		t.Errorf(
+			"unexpected allocations"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			allocated, want,
+		)
+	}
+}
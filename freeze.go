@@ -0,0 +1,90 @@
+package clockpro
+
+import "iter"
+
+// ReadOnlyCache is a view of a frozen [Cache],
+// which may be read but not modified.
+// Reading does not count as an access,
+// so the replacement state of the entries is
+// not perturbed by its readers. Entries whose TTL
+// has elapsed are omitted, but not evicted.
+// ReadOnlyCache is safe for concurrent use.
+type ReadOnlyCache[Key comparable, Value any] struct {
+	cache *Cache[Key, Value]
+}
+
+// Freeze returns a read-only view of a copy of the cache,
+// such as to share a prepared cache with readers which
+// must not be able to modify it. The cache remains
+// usable, and its changes are not reflected by the view.
+// Values are shared with the view, like [Cache.Clone]
+// with a nil copyValue.
+func (c *Cache[Key, Value]) Freeze() *ReadOnlyCache[Key, Value] {
+	return &ReadOnlyCache[Key, Value]{cache: c.Clone(nil)}
+}
+
+// Get returns the value of key if it is resident,
+// without marking it as referenced.
+func (ro *ReadOnlyCache[Key, Value]) Get(key Key) (Value, bool) {
+	return ro.cache.peek(key)
+}
+
+// Peek is an alias of [ReadOnlyCache.Get],
+// since neither counts as an access.
+func (ro *ReadOnlyCache[Key, Value]) Peek(key Key) (Value, bool) {
+	return ro.cache.peek(key)
+}
+
+// Contains reports whether key is resident.
+func (ro *ReadOnlyCache[Key, _]) Contains(key Key) bool {
+	_, ok := ro.cache.peek(key)
+	return ok
+}
+
+// Inspect is like [Cache.Inspect].
+func (ro *ReadOnlyCache[Key, _]) Inspect(key Key) (EntryInfo, bool) {
+	return ro.cache.Inspect(key)
+}
+
+// Len returns the number of resident entries
+// whose TTL has not elapsed.
+func (ro *ReadOnlyCache[_, _]) Len() int {
+	if !ro.cache.expiry.active() {
+		return ro.cache.Len()
+	}
+	var length int
+	for range ro.Keys() {
+		length++
+	}
+	return length
+}
+
+// Capacity returns the capacity of the frozen cache.
+func (ro *ReadOnlyCache[_, _]) Capacity() int { return ro.cache.capacity }
+
+// Keys is like [Cache.Keys], but omits
+// the keys of entries whose TTL has elapsed.
+func (ro *ReadOnlyCache[Key, _]) Keys() iter.Seq[Key] {
+	return func(yield func(Key) bool) {
+		for key := range ro.cache.Keys() {
+			if !ro.cache.expired(key) && !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the resident entries,
+// in no particular order.
+func (ro *ReadOnlyCache[Key, Value]) All() iter.Seq2[Key, Value] {
+	return ro.cache.EntriesWhere(nil)
+}
+
+// Range is like [Cache.Range].
+func (ro *ReadOnlyCache[Key, Value]) Range(f func(Key, Value) bool) {
+	ro.cache.Range(f)
+}
+
+// Stats returns the statistics of the cache
+// at the time that it was frozen.
+func (ro *ReadOnlyCache[_, _]) Stats() Stats { return ro.cache.Stats() }
@@ -0,0 +1,65 @@
+package clockpro_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/djdv/go-clockpro"
+)
+
+// BenchmarkGC measures the time taken by a full garbage
+// collection while a large cache is populated, relative
+// to the "empty" baseline, so that changes to the layout
+// of pages and indexes can be compared with benchstat.
+func BenchmarkGC(b *testing.B) {
+	const capacity = 1 << 20
+	b.Run("empty", func(b *testing.B) {
+		benchmarkGC(b, func() any { return nil })
+	})
+	for _, constructor := range []struct {
+		name    string
+		options []clockpro.Option[int, int]
+	}{
+		{"ClockProPlus", nil},
+		{"ClockProPlus integer index", []clockpro.Option[int, int]{
+			clockpro.WithIntegerIndex[int, int](),
+		}},
+	} {
+		b.Run(constructor.name, func(b *testing.B) {
+			benchmarkGC(b, func() any {
+				cache, err := clockpro.New(capacity, constructor.options...)
+				if err != nil {
+					b.Fatal(err)
+				}
+				// Twice the capacity, so that test pages are retained too.
+				for key := range capacity * 2 {
+					cache.Set(key, key)
+				}
+				return cache
+			})
+		})
+	}
+}
+
+// benchmarkGC forces a collection for each iteration,
+// while the value returned by populate is reachable,
+// and reports the pauses of the collector alongside.
+func benchmarkGC(b *testing.B, populate func() any) {
+	live := populate()
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	b.ResetTimer()
+	for b.Loop() {
+		runtime.GC()
+	}
+	b.StopTimer()
+	runtime.ReadMemStats(&after)
+	runtime.KeepAlive(live)
+	if collections := after.NumGC - before.NumGC; collections != 0 {
+		pauses := time.Duration(after.PauseTotalNs - before.PauseTotalNs)
+		b.ReportMetric(float64(pauses.Nanoseconds())/float64(collections), "pause-ns/GC")
+	}
+	b.ReportMetric(float64(after.HeapAlloc)/(1<<20), "heap-MiB")
+}
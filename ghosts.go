@@ -0,0 +1,99 @@
+package clockpro
+
+import "hash/maphash"
+
+// ghostSketch approximates the set of test pages
+// with bloom filters, rather than retaining their metadata.
+// Ghosts are added to the current generation, which
+// replaces the previous generation once it is full;
+// so the most recent [length, 2*length) ghosts are retained.
+type ghostSketch[Key comparable] struct {
+	current, previous bloom[Key]
+	added, length     int
+	retired           int // Ghosts in the previous generation.
+}
+
+// WithGhostSketch tracks test pages in a compact sketch
+// instead of retaining their metadata in the clock.
+// This reduces the memory used by nonresident pages,
+// at the cost of approximating their test period by
+// the amount of evictions since, rather than by stack position,
+// and the occasional false positive.
+// Caches which use a sketch cannot be checked by [Replay].
+func WithGhostSketch[Key comparable, Value any]() Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		set.ghostSketch = true
+		return nil
+	}
+}
+
+func newGhostSketch[Key comparable](capacity int) *ghostSketch[Key] {
+	var (
+		seed   = maphash.MakeSeed()
+		length = max(capacity/2, 1)
+	)
+	return &ghostSketch[Key]{
+		current:  newBloom[Key](seed, length),
+		previous: newBloom[Key](seed, length),
+		length:   length,
+	}
+}
+
+func (gs *ghostSketch[Key]) add(key Key) {
+	if gs.added == gs.length {
+		gs.current, gs.previous = gs.previous, gs.current
+		gs.current.reset()
+		gs.retired = gs.added
+		gs.added = 0
+	}
+	gs.current.insert(key)
+	gs.added++
+}
+
+func (gs *ghostSketch[Key]) contains(key Key) bool {
+	return gs.current.contains(key) || gs.previous.contains(key)
+}
+
+// len returns the amount of ghosts retained.
+func (gs *ghostSketch[_]) len() int { return gs.added + gs.retired }
+
+func (gs *ghostSketch[_]) reset() {
+	gs.current.reset()
+	gs.previous.reset()
+	gs.added, gs.retired = 0, 0
+}
+
+func (gs *ghostSketch[Key]) clone() *ghostSketch[Key] {
+	clone := *gs
+	clone.current = gs.current.clone()
+	clone.previous = gs.previous.clone()
+	return &clone
+}
+
+// resurrectGhost inserts a page for a key found in the ghost sketch,
+// promoting it to hot as if it were a test page.
+func (c *Cache[Key, Value]) resurrectGhost(key Key, value Value, weight float64) (result setResult[Key, Value]) {
+	c.increaseColdTarget(weight)
+	c.observeShift(true)
+	if c.atCapacity() {
+		result = c.evictCold()
+	}
+	page := c.newPage(metadata[Key]{
+		Name:     key,
+		Resident: true,
+		Stacked:  true,
+	}, value)
+	c.touch(page)
+	c.addToClock(page)
+	c.coldCount++
+	if c.cold == nil {
+		c.cold = page
+	}
+	c.recordDecision(DecisionResurrect, key)
+	c.stats.total.resurrections++
+	c.hooks.ghostHit(key, value)
+	c.becameResident(page)
+	c.promoteCold(page)
+	c.sweepCold()
+	return result
+}
@@ -0,0 +1,79 @@
+package clockpro_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestGhostSketch(t *testing.T) {
+	t.Run("resurrect", ghostSketchResurrect)
+	t.Run("forget", ghostSketchForget)
+}
+
+func ghostSketchResurrect(t *testing.T) {
+	t.Parallel()
+	const capacity = 2
+	cache := newGhostSketchCache(t, capacity)
+	addIncrementingInts(cache, capacity)
+	cache.Set(3, 3) // Evicts 2 (cold).
+	if got := cache.SetReport(2, 2); got != clockpro.SetResurrected {
+		t.Errorf(
+			"unexpected outcome for evicted key"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			got, clockpro.SetResurrected)
+	}
+	if got := slices.Collect(cache.HotKeys()); !slices.Contains(got, 2) {
+		t.Errorf("expected resurrected key to be hot, hot keys: %v", got)
+	}
+}
+
+func ghostSketchForget(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 4
+		trials   = 8
+		// Sketches may report false positives;
+		// tolerate one across all trials.
+		tolerance = 1
+	)
+	var remembered int
+	for range trials {
+		cache := newGhostSketchCache(t, capacity)
+		addIncrementingInts(cache, capacity)
+		var (
+			evicted int
+			next    = capacity + 1
+		)
+		for ; evicted == 0; next++ {
+			evicted, _, _ = cache.SetGetEvicted(next, next)
+		}
+		// Ghosts are retained for at most twice
+		// half of the capacity in evictions.
+		for range capacity + 1 {
+			cache.Set(next, next)
+			next++
+		}
+		if cache.SetReport(evicted, evicted) != clockpro.SetInserted {
+			remembered++
+		}
+	}
+	if remembered > tolerance {
+		t.Errorf(
+			"unexpected amount of forgotten keys resurrected"+
+				"\n\tgot: %d"+
+				"\n\twant: <=%d",
+			remembered, tolerance)
+	}
+}
+
+func newGhostSketchCache(tb testing.TB, capacity int) *clockpro.Cache[int, int] {
+	tb.Helper()
+	cache, err := clockpro.New(capacity, clockpro.WithGhostSketch[int, int]())
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return cache
+}
@@ -0,0 +1,107 @@
+package clockpro
+
+import (
+	"maps"
+	"slices"
+)
+
+// entryGroups indexes the resident entries by the tags
+// given to [Cache.SetTagged], and their tags by key.
+// Each tag groups the entries which carry it.
+type entryGroups[Key comparable] struct {
+	members map[string]map[Key]struct{}
+	groups  map[Key][]string
+}
+
+// SetTagged is like [Cache.Set], but also associates the
+// entry with each of tags, until it is no longer resident or
+// is tagged again, such that the entries carrying a tag may
+// be invalidated together by [Cache.InvalidateTag].
+// [Cache.Set] retains the tags of a resident entry.
+//
+// These tags only group entries for invalidation, and are
+// distinct from the single opaque tag of [Cache.SetWithTag],
+// which is reported by [EntryInfo.Tag]; an entry may carry both,
+// and neither replaces the other.
+func (c *Cache[Key, Value]) SetTagged(key Key, value Value, tags ...string) {
+	c.set(key, value, 0)
+	if page, ok := c.index.get(key); ok && page.Resident {
+		c.groups.join(key, tags)
+	}
+}
+
+// InvalidateTag invalidates each resident entry
+// carrying tag, like [Cache.Invalidate],
+// and returns the amount that were invalidated.
+// Tags given to [Cache.SetWithTag] are not matched.
+func (c *Cache[Key, Value]) InvalidateTag(tag string) int {
+	var (
+		members     = c.groups.members[tag]
+		keys        = make([]Key, 0, len(members))
+		invalidated int
+	)
+	for key := range members {
+		keys = append(keys, key)
+	}
+	for _, key := range keys {
+		if c.Invalidate(key) {
+			invalidated++
+		}
+	}
+	return invalidated
+}
+
+// join replaces the groups of key.
+func (eg *entryGroups[Key]) join(key Key, groups []string) {
+	eg.drop(key)
+	if len(groups) == 0 {
+		return
+	}
+	if eg.members == nil {
+		eg.members = make(map[string]map[Key]struct{})
+		eg.groups = make(map[Key][]string)
+	}
+	groups = slices.Clone(groups)
+	for _, group := range groups {
+		members, ok := eg.members[group]
+		if !ok {
+			members = make(map[Key]struct{})
+			eg.members[group] = members
+		}
+		members[key] = struct{}{}
+	}
+	eg.groups[key] = groups
+}
+
+func (eg *entryGroups[Key]) drop(key Key) {
+	groups, ok := eg.groups[key]
+	if !ok {
+		return
+	}
+	for _, group := range groups {
+		members := eg.members[group]
+		if delete(members, key); len(members) == 0 {
+			delete(eg.members, group)
+		}
+	}
+	delete(eg.groups, key)
+}
+
+func (eg *entryGroups[Key]) reset() {
+	clear(eg.members)
+	clear(eg.groups)
+}
+
+func (eg *entryGroups[Key]) clone() entryGroups[Key] {
+	if eg.members == nil {
+		return entryGroups[Key]{}
+	}
+	members := make(map[string]map[Key]struct{}, len(eg.members))
+	for group, keys := range eg.members {
+		members[group] = maps.Clone(keys)
+	}
+	return entryGroups[Key]{
+		members: members,
+		groups:  maps.Clone(eg.groups),
+	}
+}
@@ -0,0 +1,84 @@
+package clockpro_test
+
+import (
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestTagged(t *testing.T) {
+	t.Run("invalidate", taggedInvalidate)
+	t.Run("opaque tag", taggedOpaqueTag)
+}
+
+func taggedInvalidate(t *testing.T) {
+	t.Parallel()
+	const capacity = 8
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.SetTagged(1, 1, "a")
+	cache.SetTagged(2, 2, "a", "b")
+	cache.SetTagged(3, 3, "b")
+	cache.SetTagged(4, 4, "c")
+	cache.Set(4, 40) // Retains "c".
+	cache.SetTagged(5, 5, "d")
+	cache.SetTagged(5, 5, "e") // Replaces "d".
+	for _, test := range []struct {
+		tag  string
+		want int
+	}{
+		{"a", 2},
+		{"a", 0},
+		{"b", 1}, // 2 was already invalidated.
+		{"c", 1},
+		{"d", 0},
+		{"e", 1},
+	} {
+		if got := cache.InvalidateTag(test.tag); got != test.want {
+			t.Errorf(
+				"unexpected amount of entries invalidated by tag %q"+
+					"\n\tgot: %d"+
+					"\n\twant: %d",
+				test.tag, got, test.want,
+			)
+		}
+	}
+	if got := cache.Len(); got != 0 {
+		t.Errorf("expected all entries to be invalidated, %d remain", got)
+	}
+}
+
+// taggedOpaqueTag expects the tags of SetTagged
+// and the tag of SetWithTag to be independent.
+func taggedOpaqueTag(t *testing.T) {
+	t.Parallel()
+	cache, err := clockpro.New[int, int](8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.SetWithTag(1, 1, "owner")
+	cache.SetTagged(1, 1, "document")
+	if got := cache.InvalidateTag("owner"); got != 0 {
+		t.Errorf("opaque tag was invalidated: %d", got)
+	}
+	for key, info := range cache.Pages() {
+		if key == 1 && info.Tag != "owner" {
+			t.Errorf(
+				"opaque tag was replaced"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				info.Tag, "owner",
+			)
+		}
+	}
+	if got := cache.InvalidateTag("document"); got != 1 {
+		t.Errorf(
+			"unexpected amount of entries invalidated"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			got, 1,
+		)
+	}
+}
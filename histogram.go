@@ -0,0 +1,90 @@
+package clockpro
+
+import "math/bits"
+
+// Histogram counts values in buckets of exponentially increasing width.
+// Bucket 0 counts zeros, and bucket i counts values within [2^(i-1), 2^i).
+type Histogram struct {
+	Buckets [65]uint64
+}
+
+// Count returns the total number of values in the histogram.
+func (hg *Histogram) Count() uint64 {
+	var count uint64
+	for _, bucket := range hg.Buckets {
+		count += bucket
+	}
+	return count
+}
+
+// Quantile returns an upper bound for the q-quantile
+// of the values in the histogram, where q is within [0,1].
+func (hg *Histogram) Quantile(q float64) uint64 {
+	count := hg.Count()
+	if count == 0 {
+		return 0
+	}
+	var (
+		rank       = uint64(q * float64(count))
+		cumulative uint64
+	)
+	for i, bucket := range hg.Buckets {
+		cumulative += bucket
+		if cumulative > rank || cumulative == count {
+			return bucketLimit(i)
+		}
+	}
+	return bucketLimit(len(hg.Buckets) - 1)
+}
+
+func (hg *Histogram) add(value uint64) {
+	hg.Buckets[bits.Len64(value)]++
+}
+
+func (hg *Histogram) sub(base *Histogram) Histogram {
+	var delta Histogram
+	for i, bucket := range hg.Buckets {
+		delta.Buckets[i] = bucket - base.Buckets[i]
+	}
+	return delta
+}
+
+func (hg *Histogram) merge(other *Histogram) {
+	for i, bucket := range other.Buckets {
+		hg.Buckets[i] += bucket
+	}
+}
+
+// below estimates the fraction of values which are less than value,
+// assuming that the values of each bucket are evenly distributed.
+func (hg *Histogram) below(value uint64) float64 {
+	count := hg.Count()
+	if count == 0 {
+		return 0
+	}
+	var below float64
+	for i, bucket := range hg.Buckets {
+		var low, high uint64 = 0, bucketLimit(i) + 1
+		if i != 0 {
+			low = 1 << (i - 1)
+		}
+		if value >= high {
+			below += float64(bucket)
+			continue
+		}
+		if value > low {
+			below += float64(bucket) * float64(value-low) / float64(high-low)
+		}
+		break
+	}
+	return below / float64(count)
+}
+
+// bucketLimit returns the largest value
+// counted by the bucket at index.
+func bucketLimit(index int) uint64 {
+	if index == 0 {
+		return 0
+	}
+	return 1<<index - 1
+}
@@ -0,0 +1,63 @@
+package clockpro_test
+
+import (
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestHistogram(t *testing.T) {
+	t.Run("quantile", histogramQuantile)
+	t.Run("eviction ages", evictionAges)
+}
+
+func histogramQuantile(t *testing.T) {
+	t.Parallel()
+	var histogram clockpro.Histogram
+	histogram.Buckets[0] = 50 // Zeros.
+	histogram.Buckets[4] = 49 // [8,15].
+	histogram.Buckets[8] = 1  // [128,255].
+	for _, test := range []struct {
+		quantile float64
+		want     uint64
+	}{
+		{0, 0},
+		{0.5, 15},
+		{0.99, 255},
+		{1, 255},
+	} {
+		if got := histogram.Quantile(test.quantile); got != test.want {
+			t.Errorf(
+				"unexpected bound for quantile %f"+
+					"\n\tgot: %d"+
+					"\n\twant: %d",
+				test.quantile, got, test.want)
+		}
+	}
+}
+
+func evictionAges(t *testing.T) {
+	t.Parallel()
+	const capacity = 2
+	cache, err := clockpro.New(capacity,
+		clockpro.WithEvictionAges[int, int](),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addIncrementingInts(cache, capacity) // Operations 1 and 2.
+	mustGet(t, cache, 1)                 // Operation 3.
+	cache.Set(3, 3)                      // Operation 4; evicts 2.
+	var (
+		ages = cache.Stats().EvictionAges
+		want = clockpro.Histogram{}
+	)
+	want.Buckets[2] = 1 // 4 - 2.
+	if ages != want {
+		t.Errorf(
+			"unexpected eviction ages"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			ages.Buckets, want.Buckets)
+	}
+}
@@ -0,0 +1,113 @@
+package clockpro_test
+
+import (
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+	"github.com/djdv/go-clockpro/sieve"
+	"github.com/djdv/go-clockpro/workload"
+)
+
+// hitRateTolerance is the absolute difference from
+// a baseline hit ratio which is accepted.
+const hitRateTolerance = 0.01
+
+// TestHitRate guards against changes which degrade
+// (or unexpectedly improve) the hit ratio of the policy
+// for fixed workloads. Baselines were recorded from
+// the current implementation; if a change is intended to
+// alter them, they should be updated along with it.
+// The hit ratios of [sieve.Cache] are recorded alongside,
+// for comparison with those of CLOCK-Pro+.
+func TestHitRate(t *testing.T) {
+	const (
+		capacity = 512
+		length   = 1 << 16
+	)
+	for _, test := range []struct {
+		name                    string
+		keys                    []int
+		baseline, sieveBaseline float64
+	}{
+		{
+			"zipf",
+			workload.Zipf(rngSeed, capacity*32, length, 1.2, 1),
+			0.8307, 0.8307,
+		},
+		{
+			"loop",
+			workload.Looping(rngSeed, capacity, capacity*16, length, 0.9),
+			0.8739, 0.8838,
+		},
+		{
+			"loop with scans",
+			withScans(
+				workload.Looping(rngSeed, capacity/2, capacity*16, length, 0.9),
+				capacity*16, 4,
+			),
+			0.6728, 0.6726,
+		},
+		{
+			"uniform",
+			workload.Uniform(rngSeed, capacity*4, length),
+			0.2505, 0.2488,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			cache, err := clockpro.New[int, int](capacity)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkHitRate(t, "CLOCK-Pro+", cache, test.keys, test.baseline)
+			sieveCache, err := sieve.New[int, int](capacity)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkHitRate(t, "SIEVE", sieveCache, test.keys, test.sieveBaseline)
+		})
+	}
+}
+
+// hitRateCache is implemented by
+// [clockpro.Cache] and [sieve.Cache].
+type hitRateCache interface {
+	benchCache[int, int]
+	Stats() clockpro.Stats
+}
+
+// checkHitRate looks up each key in turn, setting it
+// if it misses, and compares the resulting hit ratio
+// of the cache with its baseline.
+func checkHitRate(t *testing.T, policy string, cache hitRateCache, keys []int, baseline float64) {
+	t.Helper()
+	for _, key := range keys {
+		if _, ok := cache.Get(key); !ok {
+			cache.Set(key, key)
+		}
+	}
+	got := cache.Stats().HitRatio()
+	if got < baseline-hitRateTolerance ||
+		got > baseline+hitRateTolerance {
+		t.Errorf(
+			"%s: hit ratio differs from baseline"+
+				"\n\tgot: %.4f"+
+				"\n\twant: %.4f±%.2f",
+			policy, got, baseline, hitRateTolerance,
+		)
+	}
+}
+
+// withScans replaces every nth key with the next key
+// of a scan over keys which are otherwise never accessed,
+// starting at offset.
+func withScans(keys []int, offset, n int) []int {
+	scan := offset
+	for i := range keys {
+		if i%n == 0 {
+			keys[i] = scan
+			scan++
+		}
+	}
+	return keys
+}
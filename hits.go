@@ -0,0 +1,22 @@
+package clockpro
+
+import "math"
+
+// WithHitCounts counts the lookups which hit each
+// resident page, in [EntryInfo.Hits] of [Cache.Export].
+// Counts start when a page becomes resident,
+// and saturate at their maximum.
+// Hits served without locking the cache,
+// by [Synced] and [Actor], are not counted.
+func WithHitCounts[Key comparable, Value any]() Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		set.countHits = true
+		return nil
+	}
+}
+
+func (c *Cache[Key, Value]) countHit(page *page[Key, Value]) {
+	if c.countHits && page.Hits != math.MaxUint16 {
+		page.Hits++
+	}
+}
@@ -0,0 +1,52 @@
+package clockpro
+
+// Hooks are optional callbacks invoked by the [Cache]
+// when a page undergoes a policy transition.
+// Hooks are called synchronously, while the cache is
+// being modified; they must not call back into the cache.
+type Hooks[Key comparable, Value any] struct {
+	// OnInsert is called when a new page becomes resident.
+	OnInsert func(Key, Value)
+	// OnPromote is called when a cold page becomes hot.
+	OnPromote func(Key)
+	// OnDemote is called when a hot page becomes cold.
+	OnDemote func(Key)
+	// OnGhostHit is called when a nonresident test page
+	// is accessed, and is resurrected with the value.
+	OnGhostHit func(Key, Value)
+	// OnRelease is called when a resident value
+	// is no longer held by the cache, because it was
+	// evicted, invalidated, expired, replaced, removed,
+	// or purged, with the state of its entry beforehand.
+	OnRelease func(Key, Value, EntryInfo)
+}
+
+func (hk *Hooks[Key, Value]) inserted(key Key, value Value) {
+	if hook := hk.OnInsert; hook != nil {
+		hook(key, value)
+	}
+}
+
+func (hk *Hooks[Key, _]) promoted(key Key) {
+	if hook := hk.OnPromote; hook != nil {
+		hook(key)
+	}
+}
+
+func (hk *Hooks[Key, _]) demoted(key Key) {
+	if hook := hk.OnDemote; hook != nil {
+		hook(key)
+	}
+}
+
+func (hk *Hooks[Key, Value]) ghostHit(key Key, value Value) {
+	if hook := hk.OnGhostHit; hook != nil {
+		hook(key, value)
+	}
+}
+
+func (hk *Hooks[Key, Value]) released(key Key, value Value, info EntryInfo) {
+	if hook := hk.OnRelease; hook != nil {
+		hook(key, value, info)
+	}
+}
@@ -0,0 +1,98 @@
+package clockpro_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestHooks(t *testing.T) {
+	t.Parallel()
+	const capacity = 2
+	var (
+		inserted, promoted,
+		demoted, ghostHits,
+		released []int
+		hooks = clockpro.Hooks[int, int]{
+			OnInsert:   func(key, _ int) { inserted = append(inserted, key) },
+			OnPromote:  func(key int) { promoted = append(promoted, key) },
+			OnDemote:   func(key int) { demoted = append(demoted, key) },
+			OnGhostHit: func(key, _ int) { ghostHits = append(ghostHits, key) },
+			OnRelease:  func(_, value int, _ clockpro.EntryInfo) { released = append(released, value) },
+		}
+		cache, err = clockpro.New(capacity, clockpro.WithHooks(hooks))
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addIncrementingInts(cache, capacity)
+	cache.Set(3, 3) // Evicts 2 (cold).
+	cache.Set(2, 2) // Resurrects 2 (hot).
+	cache.Set(2, 4) // Replaces 2.
+	cache.Purge(nil)
+	for _, test := range []struct {
+		name      string
+		got, want []int
+	}{
+		{"insert", inserted, []int{1, 2, 3}},
+		{"promote", promoted, []int{2}},
+		{"demote", demoted, []int{1}},
+		{"ghost hit", ghostHits, []int{2}},
+		{"release", released, []int{2, 3, 2, 4, 1}},
+	} {
+		if !slices.Equal(test.got, test.want) {
+			t.Errorf(
+				"unexpected %s hook calls"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				test.name, test.got, test.want)
+		}
+	}
+}
+
+func TestValueEquality(t *testing.T) {
+	t.Parallel()
+	var (
+		released []int
+		hooks    = clockpro.Hooks[int, int]{
+			OnRelease: func(_, value int, _ clockpro.EntryInfo) { released = append(released, value) },
+		}
+		cache, err = clockpro.New(2,
+			clockpro.WithHooks(hooks),
+			clockpro.WithComparableValues[int, int](),
+		)
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Set(1, 1)
+	version := cache.SetVersioned(2, 2)
+	cache.Set(1, 1) // Refreshed.
+	cache.Set(2, 2) // Refreshed.
+	cache.Set(1, 3) // Replaces 1.
+	if want := []int{1}; !slices.Equal(released, want) {
+		t.Errorf(
+			"unexpected release hook calls"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			released, want)
+	}
+	if _, got, _ := cache.GetVersioned(2); got != version {
+		t.Errorf(
+			"refresh changed version"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			got, version)
+	}
+	if _, err := clockpro.New(2,
+		clockpro.WithValueEquality[int, int](nil),
+	); !errors.Is(err, clockpro.ErrInvalidOption) {
+		t.Errorf(
+			"nil equality function was accepted"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			err, clockpro.ErrInvalidOption)
+	}
+}
@@ -0,0 +1,194 @@
+package clockpro
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// hotKeys replicates the entries of keys which receive
+	// a large share of a [Sharded] cache's hits into a replica
+	// per shard, so that readers of the same key are spread
+	// across replicas rather than sharing the owner's entry.
+	hotKeys[Key comparable, Value any] struct {
+		replicas []hotReplica[Key, Value]
+		// counts holds the sampled hits of each key
+		// during the current window.
+		counts    map[Key]int
+		sampled   int
+		threshold int // Sampled hits within a window.
+		mu        sync.Mutex
+	}
+	hotReplica[Key comparable, Value any] struct {
+		entries atomic.Pointer[map[Key]*syncedEntry[Key, Value]]
+		// lookups are those of the shard with the same index,
+		// so that its hits include those of the replica.
+		lookups *unlockedLookups
+	}
+)
+
+const (
+	// hotSampleRate is the inverse of the fraction
+	// of hits which are sampled to detect hot keys.
+	hotSampleRate = 16
+	// hotWindow is the amount of sampled hits after which
+	// keys which were not hot during the window are no
+	// longer replicated, and the counts are reset.
+	hotWindow = 1024
+)
+
+// WithHotKeyReplication replicates the entries of keys which
+// account for at least fraction of the hits of a [Sharded]
+// cache into a replica per shard. Hits of replicated keys are
+// served by a random replica, so that readers of a single hot
+// key do not all contend for its shard. Hits are sampled, so
+// detection is approximate, and keys which are no longer hot
+// stop being replicated after a window of sampled hits.
+// Modifications of a replicated key update every replica
+// before they return. Keys are not replicated by caches
+// constructed with [WithMaxIdle], since their hits must
+// extend the deadline of the entry while holding the lock.
+// Also used by [NewSharded]; ignored by other constructors.
+func WithHotKeyReplication[Key comparable, Value any](fraction float64) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if !(fraction > 0 && fraction <= 1) {
+			return fmt.Errorf(
+				"%w: hot key fraction must be within (0,1] but %v was provided",
+				ErrInvalidOption, fraction,
+			)
+		}
+		set.hotFraction = fraction
+		return nil
+	}
+}
+
+func newHotKeys[Key comparable, Value any](shards []*Synced[Key, Value], fraction float64) *hotKeys[Key, Value] {
+	hot := &hotKeys[Key, Value]{
+		replicas:  make([]hotReplica[Key, Value], len(shards)),
+		counts:    make(map[Key]int),
+		threshold: max(int(math.Ceil(fraction*hotWindow)), 1),
+	}
+	for i := range hot.replicas {
+		hot.replicas[i].entries.Store(new(map[Key]*syncedEntry[Key, Value]))
+		hot.replicas[i].lookups = &shards[i].lookups
+	}
+	return hot
+}
+
+// get returns the entry of key from a random replica,
+// if key is replicated and its entry has not expired.
+func (hk *hotKeys[Key, Value]) get(key Key, now func() time.Time) (*syncedEntry[Key, Value], bool) {
+	replica := &hk.replicas[rand.N(len(hk.replicas))]
+	entry, ok := (*replica.entries.Load())[key]
+	if !ok {
+		return nil, false
+	}
+	if entry.deadline != 0 && entry.deadline <= now().UnixNano() {
+		return nil, false // Expiration requires the owner's lock.
+	}
+	entry.touch()
+	replica.lookups.hits.Add(1)
+	return entry, true
+}
+
+// sample counts a hit of key served by owner,
+// replicating key if it became hot.
+func (hk *hotKeys[Key, Value]) sample(owner *Synced[Key, Value], key Key) {
+	if rand.N(hotSampleRate) != 0 {
+		return
+	}
+	hk.mu.Lock()
+	hk.counts[key]++
+	promote := hk.counts[key] == hk.threshold && !hk.replicated(key)
+	if hk.sampled++; hk.sampled == hotWindow {
+		hk.endWindow()
+	}
+	hk.mu.Unlock()
+	if !promote {
+		return
+	}
+	// The owner's lock is held while publishing,
+	// so that modifications of key are not missed.
+	owner.lock()
+	defer owner.mu.Unlock()
+	hk.mu.Lock()
+	defer hk.mu.Unlock()
+	if loaded, ok := owner.entries.Load(key); ok && !hk.replicated(key) {
+		hk.publish(key, loaded.(*syncedEntry[Key, Value]))
+	}
+}
+
+// endWindow stops replicating keys which were not
+// hot during the window, and resets the counts.
+func (hk *hotKeys[Key, Value]) endWindow() {
+	entries := *hk.replicas[0].entries.Load()
+	for key := range entries {
+		if hk.counts[key] < hk.threshold {
+			hk.publish(key, nil)
+		}
+	}
+	clear(hk.counts)
+	hk.sampled = 0
+}
+
+// stored updates the replicas of key, if it is replicated.
+// Called while holding the owner's lock.
+func (hk *hotKeys[Key, Value]) stored(key Key, entry *syncedEntry[Key, Value]) {
+	if !hk.replicated(key) {
+		return
+	}
+	hk.mu.Lock()
+	defer hk.mu.Unlock()
+	if hk.replicated(key) { // Unless the window ended.
+		hk.publish(key, entry)
+	}
+}
+
+// dropped stops replicating key.
+// Called while holding the owner's lock.
+func (hk *hotKeys[Key, Value]) dropped(key Key) { hk.stored(key, nil) }
+
+// publish replaces the entry of key in every replica,
+// or removes it if entry is nil.
+// Called while holding the mutex.
+func (hk *hotKeys[Key, Value]) publish(key Key, entry *syncedEntry[Key, Value]) {
+	for i := range hk.replicas {
+		replica := &hk.replicas[i]
+		entries := make(map[Key]*syncedEntry[Key, Value], len(*replica.entries.Load())+1)
+		for replicated, other := range *replica.entries.Load() {
+			entries[replicated] = other
+		}
+		if entry != nil {
+			entries[key] = entry
+		} else {
+			delete(entries, key)
+		}
+		replica.entries.Store(&entries)
+	}
+}
+
+func (hk *hotKeys[Key, _]) replicated(key Key) bool {
+	_, ok := (*hk.replicas[0].entries.Load())[key]
+	return ok
+}
+
+// len returns the amount of replicated keys.
+func (hk *hotKeys[_, _]) len() int {
+	return len(*hk.replicas[0].entries.Load())
+}
+
+// keys returns the replicated keys.
+func (hk *hotKeys[Key, Value]) keys() []Key {
+	var (
+		entries = *hk.replicas[0].entries.Load()
+		keys    = make([]Key, 0, len(entries))
+	)
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,298 @@
+package clockpro
+
+import (
+	"hash/maphash"
+	"iter"
+	"math/bits"
+	"slices"
+
+	"github.com/djdv/go-clockpro/internal/list"
+)
+
+type (
+	// pageIndex maps keys to the references of their pages
+	// within the clock, held by either a map or a [hashTable],
+	// so that neither holds pointers for the garbage collector
+	// to trace, besides those of the keys.
+	// See [WithIntegerIndex] and [WithStringIndex].
+	pageIndex[Key comparable, Value any] struct {
+		clock *list.List[Key, Value]
+		pages map[Key]list.Ref
+		table *hashTable[Key, Value]
+	}
+	// hashTable is an open-addressing hash table
+	// of pages with linear probing.
+	hashTable[Key comparable, Value any] struct {
+		clock *list.List[Key, Value]
+		hash  func(Key) uint64
+		slots []tableSlot
+		count int
+		shift uint8
+	}
+	// tableSlot holds the reference to a page,
+	// and the high bits of the mixed hash of its key,
+	// which select its home slot. Probes compare tags
+	// before keys, and the table is grown and shifted
+	// without hashing keys again.
+	tableSlot struct {
+		ref list.Ref
+		tag uint32
+	}
+	// Integer is the set of key types
+	// supported by [WithIntegerIndex].
+	Integer interface {
+		~int | ~int8 | ~int16 | ~int32 | ~int64 |
+			~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+	}
+)
+
+// fibonacci is 2^64 divided by the golden ratio.
+// Multiplying keys by it spreads keys which share low bits,
+// such as aligned offsets, across the table.
+const fibonacci = 0x9E3779B97F4A7C15
+
+// minimumTableSize is the smallest amount of slots in a [hashTable].
+const minimumTableSize = 8
+
+// WithIntegerIndex indexes pages within an open-addressing
+// hash table, rather than a map. Keys are hashed by a
+// single multiplication, which is faster than the hash
+// of a map, but distributes keys worse if they differ
+// only in their highest bits.
+func WithIntegerIndex[Key Integer, Value any]() Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		set.indexHash = func(key Key) uint64 { return uint64(key) }
+		return nil
+	}
+}
+
+// WithStringIndex indexes pages within an open-addressing
+// hash table, rather than a map, which retains the high bits
+// of the hash of each key alongside its page, so that the
+// contents of strings are only compared when those bits are
+// equal, and the table grows without hashing keys again.
+// See the strcache package.
+func WithStringIndex[Key ~string, Value any]() Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		seed := maphash.MakeSeed()
+		set.indexHash = func(key Key) uint64 {
+			return maphash.String(seed, string(key))
+		}
+		return nil
+	}
+}
+
+func newPageIndex[Key comparable, Value any](clock *list.List[Key, Value], hash func(Key) uint64, size int) pageIndex[Key, Value] {
+	if hash != nil {
+		return pageIndex[Key, Value]{
+			clock: clock,
+			table: newHashTable(clock, hash, size),
+		}
+	}
+	return pageIndex[Key, Value]{
+		clock: clock,
+		pages: make(map[Key]list.Ref, size),
+	}
+}
+
+func (ix *pageIndex[Key, Value]) get(key Key) (*page[Key, Value], bool) {
+	if ix.table != nil {
+		return ix.table.get(key)
+	}
+	ref, ok := ix.pages[key]
+	if !ok {
+		return nil, false
+	}
+	return ix.clock.At(ref), true
+}
+
+func (ix *pageIndex[Key, Value]) put(page *page[Key, Value]) {
+	if ix.table != nil {
+		ix.table.put(page)
+		return
+	}
+	ix.pages[page.Name] = page.Ref()
+}
+
+func (ix *pageIndex[Key, Value]) delete(key Key) {
+	if ix.table != nil {
+		ix.table.delete(key)
+		return
+	}
+	delete(ix.pages, key)
+}
+
+func (ix *pageIndex[Key, Value]) len() int {
+	if ix.table != nil {
+		return ix.table.count
+	}
+	return len(ix.pages)
+}
+
+func (ix *pageIndex[Key, Value]) clear() {
+	if ix.table != nil {
+		ix.table.clear()
+		return
+	}
+	clear(ix.pages)
+}
+
+// all returns an iterator over the indexed pages.
+// Like ranging over a map, the index may be modified
+// during iteration; pages which are removed before
+// they are reached are not yielded, and pages which
+// are inserted may or may not be.
+func (ix *pageIndex[Key, Value]) all() iter.Seq2[Key, *page[Key, Value]] {
+	if ix.table != nil {
+		return ix.table.all()
+	}
+	return func(yield func(Key, *page[Key, Value]) bool) {
+		for key, ref := range ix.pages {
+			if !yield(key, ix.clock.At(ref)) {
+				return
+			}
+		}
+	}
+}
+
+func newHashTable[Key comparable, Value any](clock *list.List[Key, Value], hash func(Key) uint64, size int) *hashTable[Key, Value] {
+	table := &hashTable[Key, Value]{clock: clock, hash: hash}
+	table.allocate(max(minimumTableSize, size*2))
+	return table
+}
+
+// allocate replaces the slots with at least size empty slots.
+// Tables hold at most 2^32 slots, so that the tag
+// of a key holds the bits which select its home.
+func (ht *hashTable[Key, Value]) allocate(size int) {
+	shift := bits.LeadingZeros64(uint64(size - 1))
+	ht.slots = make([]tableSlot, 1<<(64-shift))
+	ht.shift = uint8(shift)
+	ht.count = 0
+}
+
+// tag returns the high bits of the mixed hash of key.
+func (ht *hashTable[Key, _]) tag(key Key) uint32 {
+	return uint32((ht.hash(key) * fibonacci) >> 32)
+}
+
+// home returns the slot that a key with tag is placed in,
+// unless it is occupied by another key.
+func (ht *hashTable[_, _]) home(tag uint32) int {
+	return int(tag >> (ht.shift - 32))
+}
+
+// find returns the slot of key, which has tag,
+// or the empty slot where it belongs.
+func (ht *hashTable[Key, Value]) find(key Key, tag uint32) int {
+	mask := len(ht.slots) - 1
+	for slot := ht.home(tag); ; slot = (slot + 1) & mask {
+		switch entry := ht.slots[slot]; {
+		case entry.ref == 0:
+			return slot
+		case entry.tag == tag && ht.clock.At(entry.ref).Name == key:
+			return slot
+		}
+	}
+}
+
+func (ht *hashTable[Key, Value]) get(key Key) (*page[Key, Value], bool) {
+	ref := ht.slots[ht.find(key, ht.tag(key))].ref
+	if ref == 0 {
+		return nil, false
+	}
+	return ht.clock.At(ref), true
+}
+
+func (ht *hashTable[Key, Value]) put(page *page[Key, Value]) {
+	// Grown before it is half full, so that probes remain short.
+	if (ht.count+1)*2 > len(ht.slots) {
+		ht.grow()
+	}
+	var (
+		tag  = ht.tag(page.Name)
+		slot = ht.find(page.Name, tag)
+	)
+	if ht.slots[slot].ref == 0 {
+		ht.count++
+	}
+	ht.slots[slot] = tableSlot{ref: page.Ref(), tag: tag}
+}
+
+func (ht *hashTable[Key, Value]) grow() {
+	slots := ht.slots
+	ht.allocate(len(slots) * 2)
+	mask := len(ht.slots) - 1
+	for _, entry := range slots {
+		if entry.ref == 0 {
+			continue
+		}
+		// Keys are distinct, so each is placed
+		// in the first empty slot of its probe.
+		slot := ht.home(entry.tag)
+		for ht.slots[slot].ref != 0 {
+			slot = (slot + 1) & mask
+		}
+		ht.slots[slot] = entry
+		ht.count++
+	}
+}
+
+// delete removes key, and shifts the pages which follow it
+// back into the emptied slot if they were displaced past it,
+// so that no probe sequence contains an empty slot.
+func (ht *hashTable[Key, Value]) delete(key Key) {
+	var (
+		mask  = len(ht.slots) - 1
+		empty = ht.find(key, ht.tag(key))
+	)
+	if ht.slots[empty].ref == 0 {
+		return
+	}
+	ht.slots[empty] = tableSlot{}
+	ht.count--
+	for slot := (empty + 1) & mask; ht.slots[slot].ref != 0; slot = (slot + 1) & mask {
+		var (
+			displacement = (slot - ht.home(ht.slots[slot].tag)) & mask
+			distance     = (slot - empty) & mask
+		)
+		if displacement >= distance {
+			ht.slots[empty], ht.slots[slot] = ht.slots[slot], tableSlot{}
+			empty = slot
+		}
+	}
+}
+
+func (ht *hashTable[_, _]) clear() {
+	clear(ht.slots)
+	ht.count = 0
+}
+
+// all is like [pageIndex.all]. Since deletions may move pages
+// into slots which were already visited, the slots are
+// copied before iteration if there are pages to yield.
+// The pages referenced by the copy may be released,
+// or reused for other keys, during iteration.
+func (ht *hashTable[Key, Value]) all() iter.Seq2[Key, *page[Key, Value]] {
+	return func(yield func(Key, *page[Key, Value]) bool) {
+		if ht.count == 0 {
+			return
+		}
+		for _, entry := range slices.Clone(ht.slots) {
+			ref := entry.ref
+			if ref == 0 {
+				continue
+			}
+			if !ht.clock.InRange(ref) {
+				continue // Released by [Cache.Purge].
+			}
+			page := ht.clock.At(ref)
+			if indexed, _ := ht.get(page.Name); indexed != page {
+				continue // Removed during iteration.
+			}
+			if !yield(page.Name, page) {
+				return
+			}
+		}
+	}
+}
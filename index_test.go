@@ -0,0 +1,125 @@
+package clockpro_test
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+// TestIntegerIndex stores keys which share their low bits,
+// like aligned offsets, and checks that they remain indexed
+// as pages are inserted, evicted, and removed.
+func TestIntegerIndex(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity  = 64
+		blockSize = 4096
+	)
+	cache, err := clockpro.New(capacity,
+		clockpro.WithIntegerIndex[uint64, uint64](),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range uint64(capacity * 8) {
+		offset := i * blockSize
+		cache.Set(offset, i)
+		if i%3 == 0 {
+			cache.Delete(offset - blockSize)
+		}
+		if err := cache.CheckInvariants(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, offset := range slices.Collect(cache.Keys()) {
+		if value, ok := cache.Get(offset); !ok || value != offset/blockSize {
+			t.Errorf(
+				"unexpected value for offset %d"+
+					"\n\tgot: %d, %t"+
+					"\n\twant: %d, true",
+				offset, value, ok, offset/blockSize,
+			)
+		}
+	}
+	clone := cache.Clone(nil)
+	if err := clone.CheckInvariants(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := slices.Sorted(clone.Keys()), slices.Sorted(cache.Keys()); !slices.Equal(got, want) {
+		t.Errorf(
+			"clone holds different keys"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			got, want,
+		)
+	}
+	// Clearing the cache while it is ranged over releases
+	// the pages of the slots which remain to be visited.
+	cache.Range(func(uint64, uint64) bool {
+		cache.Purge(nil)
+		for i := range uint64(4) {
+			cache.Set(i, i)
+		}
+		return true
+	})
+	if err := cache.CheckInvariants(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestStringIndex stores keys which share a long prefix,
+// so that their contents are compared only when the bits
+// of their hashes retained by the index are equal,
+// and checks that they remain indexed as the index grows,
+// and as pages are inserted, evicted, and removed.
+func TestStringIndex(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 64
+		prefix   = "/a/long/shared/prefix/"
+	)
+	cache, err := clockpro.New(capacity,
+		clockpro.WithStringIndex[string, int](),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range capacity * 8 {
+		cache.Set(prefix+strconv.Itoa(i), i)
+		if i%3 == 0 {
+			cache.Delete(prefix + strconv.Itoa(i-1))
+		}
+		if err := cache.CheckInvariants(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, key := range slices.Collect(cache.Keys()) {
+		want, err := strconv.Atoi(strings.TrimPrefix(key, prefix))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value, ok := cache.Get(key); !ok || value != want {
+			t.Errorf(
+				"unexpected value for key %s"+
+					"\n\tgot: %d, %t"+
+					"\n\twant: %d, true",
+				key, value, ok, want,
+			)
+		}
+	}
+	clone := cache.Clone(nil)
+	if err := clone.CheckInvariants(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := slices.Sorted(clone.Keys()), slices.Sorted(cache.Keys()); !slices.Equal(got, want) {
+		t.Errorf(
+			"clone holds different keys"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			got, want,
+		)
+	}
+}
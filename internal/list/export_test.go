@@ -0,0 +1,5 @@
+package list
+
+// SetNext links e to next without linking next back to e,
+// such that tests may corrupt a list.
+func (e *Element[Key, Value]) SetNext(next *Element[Key, Value]) { e.next = next.ref }
@@ -0,0 +1,476 @@
+// Package list is a circular, intrusive, doubly linked list
+// of cache pages, which form the clock of CLOCK-Pro.
+// Elements are allocated by the list, within chunks which
+// are linked by index rather than by pointer, so that the
+// garbage collector need not trace the links of each page.
+// Each element records the list which allocated it,
+// so that operations with an element of another list
+// panic, rather than silently corrupting both lists.
+package list
+
+import (
+	"fmt"
+	"iter"
+	"sync/atomic"
+)
+
+type (
+	// List is a circular list of elements, ordered from
+	// the front (least recently inserted) to the back
+	// (most recently inserted), such that the element
+	// following the back is the front.
+	// The zero value is an empty list.
+	List[Key comparable, Value any] struct {
+		// chunks hold the elements allocated by the list,
+		// and double in length up to [lastChunk], so that
+		// elements are never moved once they are allocated.
+		// The first chunk is empty, so that no Ref is zero.
+		chunks    [][]Element[Key, Value]
+		back      Ref
+		free      Ref // Released elements, linked by next.
+		filled    int // Elements allocated from the last chunk.
+		allocated int
+		len       int
+		id        uint32
+	}
+	// Ref identifies an element of the list which allocated it,
+	// by the index of its chunk, shifted left by [chunkBits],
+	// and its offset within that chunk.
+	// The zero Ref identifies no element.
+	Ref uint32
+	// Element is a page of the clock, and may be on one list at a time.
+	Element[Key comparable, Value any] struct {
+		Value Value
+		Metadata[Key]
+		ref, next, prev Ref
+		list            uint32 // The id of the allocating list.
+		linked          bool
+		// touched holds the generation of the element,
+		// shifted left by one, and is set in its low bit
+		// by readers which reference the page concurrently
+		// with the cache's owner. See [Element.Touch].
+		touched uint32
+	}
+	// Metadata stores LIRS (Low Inter‑Reference Recency Set) state of a cache page.
+	// It is used by CLOCK‑Pro and related eviction algorithms.
+	Metadata[Key comparable] struct {
+		// Name is the identifier of the data this metadata is bound to.
+		Name Key
+		// LIR (Low Inter-Reference Recency) is true
+		// if the page is frequently accessed relative to other pages,
+		// to be spared from eviction because of its short "reuse distance".
+		// See LIRS algorithm for more detail.
+		LIR bool
+		// Resident is true if the data this metadata
+		// is associated with, is to be considered valid.
+		// I.e. true if the data is still stored in memory
+		// and has not been nullified via eviction.
+		Resident bool
+		// Demoted is true if the page has been moved
+		// to the test/ghost list (HIR non-resident).
+		Demoted bool
+		// Referenced is true if the page was
+		// accessed since the last sweep.
+		Referenced bool
+		// Stacked is true if the page is currently in the LRU/LIRS stack.
+		Stacked bool
+		// Chances counts the times a cold page was restacked
+		// since it was inserted or promoted.
+		// Only maintained when the cache limits restacks.
+		Chances uint8
+		// Hits counts the lookups which hit the page
+		// since it became resident, saturating at its maximum.
+		// Only maintained when the cache counts hits.
+		Hits uint16
+		// Slot is the position of a resident page
+		// within the cache's sampled pages, plus 1.
+		// Only maintained when the cache samples pages.
+		Slot uint32
+		// Accessed is the operation count of the cache
+		// when the page was inserted or last referenced.
+		// Only maintained when the cache tracks page ages.
+		Accessed uint64
+	}
+)
+
+const (
+	// firstChunk is the length of a list's first chunk.
+	// Each following chunk is twice the length of the last,
+	// until they reach the length of lastChunk, so that
+	// small lists are small, and large lists are not
+	// left with a mostly unused chunk.
+	firstChunk = 16
+	chunkBits  = 16
+	lastChunk  = 1 << chunkBits
+	doublings  = 12 // Chunks shorter than lastChunk.
+)
+
+// lists counts the lists which have allocated elements,
+// so that each is identified distinctly.
+var lists atomic.Uint32
+
+// Ref returns the reference to e
+// within the list which allocated it.
+func (e *Element[Key, Value]) Ref() Ref { return e.ref }
+
+// Generation returns the amount of times that e was
+// released by [List.Free], modulo 2^31.
+func (e *Element[Key, Value]) Generation() uint32 {
+	return atomic.LoadUint32(&e.touched) >> 1
+}
+
+// Touch marks e as referenced, unless it was released
+// since generation was returned by [Element.Generation].
+// Touch may be called concurrently with the owner
+// of the list, such as by readers of a cache.
+func (e *Element[Key, Value]) Touch(generation uint32) {
+	untouched := generation << 1
+	if atomic.LoadUint32(&e.touched) == untouched {
+		atomic.CompareAndSwapUint32(&e.touched, untouched, untouched|1)
+	}
+}
+
+// Touched reports whether e was touched since it was
+// allocated, or since [Element.Untouch] was last called.
+func (e *Element[Key, Value]) Touched() bool {
+	return atomic.LoadUint32(&e.touched)&1 != 0
+}
+
+// Untouch clears the mark of [Element.Touch],
+// and reports whether it was set.
+func (e *Element[Key, Value]) Untouch() bool {
+	touched := atomic.LoadUint32(&e.touched)
+	if touched&1 == 0 {
+		return false
+	}
+	atomic.StoreUint32(&e.touched, touched&^1)
+	return true
+}
+
+// At returns the element referenced by ref,
+// which must have been allocated by the list.
+func (l *List[Key, Value]) At(ref Ref) *Element[Key, Value] {
+	return &l.chunks[ref>>chunkBits][ref&(lastChunk-1)]
+}
+
+// InRange reports whether ref is within the chunks of the list,
+// such that [List.At] may be called with it, even if it
+// referenced an element which was discarded by [List.Init].
+func (l *List[Key, Value]) InRange(ref Ref) bool {
+	chunk := int(ref >> chunkBits)
+	return chunk < len(l.chunks) &&
+		int(ref&(lastChunk-1)) < len(l.chunks[chunk])
+}
+
+// New returns an element which is not on the list, to be
+// pushed to it. Elements released by [List.Free] are reused.
+func (l *List[Key, Value]) New() *Element[Key, Value] {
+	if l.id == 0 {
+		l.id = lists.Add(1)
+	}
+	if l.free != 0 {
+		e := l.At(l.free)
+		l.free, e.next = e.next, 0
+		return e
+	}
+	if len(l.chunks) == 0 {
+		l.chunks = append(l.chunks, nil)
+	}
+	if chunk := l.chunks[len(l.chunks)-1]; l.filled == len(chunk) {
+		length := firstChunk << min(len(l.chunks)-1, doublings)
+		l.chunks = append(l.chunks, make([]Element[Key, Value], length))
+		l.filled = 0
+	}
+	var (
+		ref = Ref(len(l.chunks)-1)<<chunkBits | Ref(l.filled)
+		e   = l.At(ref)
+	)
+	e.ref, e.list = ref, l.id
+	l.filled++
+	l.allocated++
+	return e
+}
+
+// Free releases e, which must have been allocated
+// by the list and must not be on it, to be returned
+// by a later call to [List.New]. Its fields are
+// cleared and its generation is incremented,
+// so that touches of its previous use are ignored.
+func (l *List[Key, Value]) Free(e *Element[Key, Value]) {
+	if e.list != l.id || e.linked {
+		panic("list: element is not free to be released")
+	}
+	var zero Value
+	e.Value, e.Metadata = zero, Metadata[Key]{}
+	// Clearing the touch and incrementing the generation.
+	touched := atomic.LoadUint32(&e.touched)
+	atomic.StoreUint32(&e.touched, (touched|1)+1)
+	e.next, l.free = l.free, e.ref
+}
+
+// Cap returns the number of elements allocated by the list,
+// including those which were released.
+func (l *List[Key, Value]) Cap() int { return l.allocated }
+
+// Next returns the element following e, which is
+// the front of the list if e is its back.
+// e must be on the list, which is not checked,
+// since the clock's hands call Next as they sweep.
+func (l *List[Key, Value]) Next(e *Element[Key, Value]) *Element[Key, Value] {
+	return l.At(e.next)
+}
+
+// Prev returns the element preceding e, which is
+// the back of the list if e is its front.
+// e must be on the list, which is not checked.
+func (l *List[Key, Value]) Prev(e *Element[Key, Value]) *Element[Key, Value] {
+	return l.At(e.prev)
+}
+
+// Contains reports whether e is on the list.
+func (l *List[Key, Value]) Contains(e *Element[Key, Value]) bool {
+	return e.linked && e.list == l.id
+}
+
+// Len returns the number of elements in the list.
+func (l *List[Key, Value]) Len() int { return l.len }
+
+// Front returns the least recently inserted element,
+// or nil if the list is empty.
+func (l *List[Key, Value]) Front() *Element[Key, Value] {
+	if l.back == 0 {
+		return nil
+	}
+	return l.At(l.At(l.back).next)
+}
+
+// Back returns the most recently inserted element,
+// or nil if the list is empty.
+func (l *List[Key, Value]) Back() *Element[Key, Value] {
+	if l.back == 0 {
+		return nil
+	}
+	return l.At(l.back)
+}
+
+// Init empties the list, and discards the elements
+// which it allocated, which must no longer be used.
+func (l *List[Key, Value]) Init() {
+	l.chunks, l.filled, l.allocated, l.free = nil, 0, 0, 0
+	l.back, l.len = 0, 0
+}
+
+// PushBack inserts e at the back of the list.
+// e must have been returned by [List.New],
+// and must not be on the list.
+func (l *List[Key, Value]) PushBack(e *Element[Key, Value]) {
+	switch {
+	case e.linked:
+		panic("list: element is already on a list")
+	case l.id == 0 || e.list != l.id:
+		panic("list: element was not allocated by the list")
+	}
+	e.linked = true
+	if l.back == 0 {
+		e.next, e.prev = e.ref, e.ref
+	} else {
+		l.insertAfter(e, l.At(l.back))
+	}
+	l.back = e.ref
+	l.len++
+}
+
+// Remove removes e from the list.
+// e must be on the list. It remains allocated,
+// so it may be pushed again, or released by [List.Free].
+func (l *List[Key, Value]) Remove(e *Element[Key, Value]) {
+	l.owns(e)
+	if l.len == 1 {
+		l.back = 0
+	} else {
+		if e.ref == l.back {
+			l.back = e.prev
+		}
+		l.unlink(e)
+	}
+	e.next, e.prev, e.linked = 0, 0, false
+	l.len--
+}
+
+// MoveToBack moves e to the back of the list.
+// e must be on the list.
+func (l *List[Key, Value]) MoveToBack(e *Element[Key, Value]) {
+	l.MoveAfter(e, l.Back())
+}
+
+// MoveAfter moves e to follow mark.
+// If mark is the back of the list, e becomes the back.
+// e and mark must be on the list.
+func (l *List[Key, Value]) MoveAfter(e, mark *Element[Key, Value]) {
+	l.owns(e)
+	l.owns(mark)
+	if e == mark {
+		return
+	}
+	if e.ref == l.back {
+		l.back = e.prev
+	}
+	l.unlink(e)
+	l.insertAfter(e, mark)
+	if mark.ref == l.back {
+		l.back = e.ref
+	}
+}
+
+// MoveBefore moves e to precede mark.
+// If mark is the front of the list, e becomes the front.
+// e and mark must be on the list.
+func (l *List[Key, Value]) MoveBefore(e, mark *Element[Key, Value]) {
+	l.owns(e)
+	l.owns(mark)
+	if e == mark {
+		return
+	}
+	if e.ref == l.back {
+		l.back = e.prev
+	}
+	if e.next != mark.ref {
+		l.unlink(e)
+		l.insertAfter(e, l.At(mark.prev))
+	}
+}
+
+// Swap exchanges the positions of a and b.
+// a and b must be on the list.
+func (l *List[Key, Value]) Swap(a, b *Element[Key, Value]) {
+	l.owns(a)
+	l.owns(b)
+	switch {
+	case a == b:
+		return
+	case a.next == b.ref:
+		l.unlink(a)
+		l.insertAfter(a, b)
+	case b.next == a.ref:
+		l.unlink(b)
+		l.insertAfter(b, a)
+	default:
+		beforeA, beforeB := l.At(a.prev), l.At(b.prev)
+		l.unlink(a)
+		l.insertAfter(a, beforeB)
+		l.unlink(b)
+		l.insertAfter(b, beforeA)
+	}
+	switch l.back {
+	case a.ref:
+		l.back = b.ref
+	case b.ref:
+		l.back = a.ref
+	}
+}
+
+// Rotate makes e the back of the list, and the element
+// following it the front, without relinking any element.
+// e must be on the list.
+func (l *List[Key, Value]) Rotate(e *Element[Key, Value]) {
+	l.owns(e)
+	l.back = e.ref
+}
+
+// All returns an iterator over the elements of the list,
+// from the front to the back. Elements following the
+// yielded element must not be removed during iteration.
+func (l *List[Key, Value]) All() iter.Seq[*Element[Key, Value]] {
+	return func(yield func(*Element[Key, Value]) bool) {
+		if l.back == 0 {
+			return
+		}
+		for e, back := l.Front(), l.back; ; e = l.At(e.next) {
+			if !yield(e) || e.ref == back {
+				return
+			}
+		}
+	}
+}
+
+// Backward is like All, but iterates
+// from the back of the list to the front.
+func (l *List[Key, Value]) Backward() iter.Seq[*Element[Key, Value]] {
+	return func(yield func(*Element[Key, Value]) bool) {
+		if l.back == 0 {
+			return
+		}
+		for e, front := l.Back(), l.Back().next; ; e = l.At(e.prev) {
+			if !yield(e) || e.ref == front {
+				return
+			}
+		}
+	}
+}
+
+// Validate checks that each element of the list is on it,
+// and links back to its neighbours, so that traversing
+// the list in either direction visits the same elements.
+// It returns an error describing the first element which
+// does not, such as one left behind by an incomplete splice.
+func (l *List[Key, Value]) Validate() error {
+	if l.back == 0 {
+		if l.len != 0 {
+			return fmt.Errorf("list of %d elements has no back", l.len)
+		}
+		return nil
+	}
+	// If each element is the predecessor of its successor,
+	// no element can be reached from two others, so the
+	// traversal must return to the back rather than
+	// enter a cycle which excludes it.
+	e := l.Back()
+	for position := 0; ; position++ {
+		switch {
+		case !l.Contains(e):
+			return fmt.Errorf(
+				"list element %d (%v) is not on the list",
+				position, e.Name,
+			)
+		case e.next == 0 || e.prev == 0:
+			return fmt.Errorf(
+				"list element %d (%v) is not linked",
+				position, e.Name,
+			)
+		}
+		next := l.At(e.next)
+		if next.prev != e.ref {
+			return fmt.Errorf(
+				"list element %d (%v) is not the predecessor of its successor (%v)",
+				position, e.Name, next.Name,
+			)
+		}
+		if e = next; e.ref == l.back {
+			if length := position + 1; length != l.len {
+				return fmt.Errorf(
+					"list holds %d elements but expected %d",
+					length, l.len,
+				)
+			}
+			return nil
+		}
+	}
+}
+
+func (l *List[Key, Value]) owns(e *Element[Key, Value]) {
+	if !l.Contains(e) {
+		panic("list: element is not on the list")
+	}
+}
+
+func (l *List[Key, Value]) insertAfter(e, mark *Element[Key, Value]) {
+	e.prev = mark.ref
+	e.next = mark.next
+	l.At(mark.next).prev = e.ref
+	mark.next = e.ref
+}
+
+func (l *List[Key, Value]) unlink(e *Element[Key, Value]) {
+	l.At(e.prev).next = e.next
+	l.At(e.next).prev = e.prev
+}
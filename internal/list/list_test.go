@@ -0,0 +1,254 @@
+package list_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/djdv/go-clockpro/internal/list"
+)
+
+type (
+	clock   = list.List[int, int]
+	element = list.Element[int, int]
+)
+
+func TestList(t *testing.T) {
+	t.Run("owner", listOwner)
+	t.Run("validate", listValidate)
+	t.Run("free", listFree)
+}
+
+func listOwner(t *testing.T) {
+	t.Parallel()
+	var (
+		a, b clock
+		e    = a.New()
+	)
+	a.PushBack(e)
+	if !a.Contains(e) || b.Contains(e) {
+		t.Errorf("element is not on the list it was pushed to")
+	}
+	for name, misuse := range map[string]func(){
+		"push twice":           func() { a.PushBack(e) },
+		"push to another list": func() { b.PushBack(e) },
+		"remove from another":  func() { b.Remove(e) },
+		"move within another":  func() { b.MoveToBack(e) },
+		"free while linked":    func() { a.Free(e) },
+		"push unallocated":     func() { a.PushBack(new(element)) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected panic", name)
+				}
+			}()
+			misuse()
+		}()
+	}
+	a.Remove(e)
+	if a.Contains(e) {
+		t.Error("removed element is still linked")
+	}
+	a.PushBack(e)
+	if err := a.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+// listFree expects released elements to be reused
+// with their fields cleared, and to ignore the
+// touches of readers from their previous use.
+func listFree(t *testing.T) {
+	t.Parallel()
+	var (
+		l          clock
+		e          = l.New()
+		generation = e.Generation()
+	)
+	e.Value = 1
+	l.PushBack(e)
+	l.Remove(e)
+	l.Free(e)
+	reused := l.New()
+	if reused != e || reused.Value != 0 {
+		t.Fatalf("released element was not reused and cleared")
+	}
+	e.Touch(generation)
+	if reused.Touched() {
+		t.Error("touch of a released element was applied to its reuse")
+	}
+	reused.Touch(reused.Generation())
+	if !reused.Untouch() || reused.Touched() {
+		t.Error("touch was not applied and cleared")
+	}
+	// Allocate across chunks of each length.
+	const count = 1 << 18
+	refs := make(map[list.Ref]struct{}, count)
+	for i := range count {
+		e := l.New()
+		e.Value = i
+		l.PushBack(e)
+		refs[e.Ref()] = struct{}{}
+		if got := l.At(e.Ref()); got != e {
+			t.Fatalf("element %d is not referenced by its ref", i)
+		}
+	}
+	if len(refs) != count || l.Cap() != count+1 {
+		t.Errorf(
+			"unexpected allocations"+
+				"\n\tgot: %d, %d"+
+				"\n\twant: %d, %d",
+			len(refs), l.Cap(), count, count+1)
+	}
+}
+
+func listValidate(t *testing.T) {
+	t.Parallel()
+	var l clock
+	if err := l.Validate(); err != nil {
+		t.Errorf("empty list: %v", err)
+	}
+	elements := make([]*element, 8)
+	for i := range elements {
+		elements[i] = l.New()
+		elements[i].Value = i
+		l.PushBack(elements[i])
+	}
+	if err := l.Validate(); err != nil {
+		t.Errorf("list of %d elements: %v", l.Len(), err)
+	}
+	// Link an element to an earlier one, as if the
+	// elements between them were partly unlinked,
+	// leaving a cycle which does not include the back.
+	elements[3].SetNext(elements[1])
+	if err := l.Validate(); err == nil {
+		t.Error("expected corrupted list to be invalid")
+	}
+}
+
+// FuzzList applies sequences of operations to a list,
+// and to a model of it as a slice, then checks that the
+// list's links agree with the model, and that each
+// element is on the list only while it is modeled.
+func FuzzList(f *testing.F) {
+	f.Add(uint8(8), []byte{0, 1, 2, 3, 4, 5, 6, 7})
+	f.Add(uint8(1), []byte{1, 0, 0, 0, 0, 0, 2, 0, 0, 5, 0, 0})
+	f.Add(uint8(4), []byte{3, 0, 1, 4, 0, 1, 5, 0, 1, 5, 0, 3, 3, 1, 3, 4, 2, 2, 6, 1, 0})
+	f.Add(uint8(32), []byte{
+		1, 3, 9, 1, 1, 12, 0, 4, 2, 2, 7, 250,
+		0, 5, 6, 1, 0, 3, 6, 9, 2, 1, 30, 3,
+	})
+	f.Fuzz(func(t *testing.T, count uint8, operations []byte) {
+		var (
+			l        clock
+			elements = make([]*element, count)
+			model    = make([]int, count)
+		)
+		for i := range elements {
+			elements[i] = l.New()
+			elements[i].Value = i
+			model[i] = i
+			l.PushBack(elements[i])
+		}
+		for len(operations) >= 3 {
+			op, a, b := operations[0], int(operations[1]), int(operations[2])
+			operations = operations[3:]
+			if op%7 == 0 { // Push an element which is not on the list.
+				var absent []*element
+				for _, e := range elements {
+					if !l.Contains(e) {
+						absent = append(absent, e)
+					}
+				}
+				if len(absent) != 0 {
+					e := absent[a%len(absent)]
+					l.PushBack(e)
+					model = append(model, e.Value)
+					checkList(t, &l, elements, model)
+				}
+				continue
+			}
+			if len(model) == 0 {
+				continue
+			}
+			var (
+				i = a % len(model)
+				j = b % len(model)
+				e = elements[model[i]]
+			)
+			switch op % 7 {
+			case 1:
+				l.Remove(e)
+				model = slices.Delete(model, i, i+1)
+			case 2:
+				l.MoveToBack(e)
+				model = append(slices.Delete(model, i, i+1), e.Value)
+			case 3:
+				l.MoveAfter(e, elements[model[j]])
+				if i != j {
+					model = slices.Delete(model, i, i+1)
+					if j > i {
+						j--
+					}
+					model = slices.Insert(model, j+1, e.Value)
+				}
+			case 4:
+				l.MoveBefore(e, elements[model[j]])
+				if i != j {
+					model = slices.Delete(model, i, i+1)
+					if j > i {
+						j--
+					}
+					model = slices.Insert(model, j, e.Value)
+				}
+			case 5:
+				l.Swap(e, elements[model[j]])
+				model[i], model[j] = model[j], model[i]
+			case 6:
+				l.Rotate(e)
+				model = append(model[i+1:], model[:i+1]...)
+			}
+			checkList(t, &l, elements, model)
+		}
+	})
+}
+
+// checkList verifies that the list is linked in the
+// order of the model, in both directions, and that
+// each element is on the list only if it is modeled.
+func checkList(t *testing.T, l *clock, elements []*element, model []int) {
+	t.Helper()
+	if err := l.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	var forward, backward []int
+	for e := range l.All() {
+		forward = append(forward, e.Value)
+	}
+	for e := range l.Backward() {
+		backward = append(backward, e.Value)
+	}
+	slices.Reverse(backward)
+	if !slices.Equal(forward, model) || !slices.Equal(backward, model) ||
+		l.Len() != len(model) {
+		t.Fatalf("list is not linked as modeled"+
+			"\n\tgot: %v (forward), %v (backward), %d (length)"+
+			"\n\twant: %v",
+			forward, backward, l.Len(), model)
+	}
+	if len(model) != 0 {
+		if front, back := l.Front().Value, l.Back().Value; front != model[0] ||
+			back != model[len(model)-1] || l.Next(l.Back()) != l.Front() {
+			t.Fatalf("unexpected ends of list"+
+				"\n\tgot: %d, %d"+
+				"\n\twant: %d, %d",
+				front, back, model[0], model[len(model)-1])
+		}
+	}
+	for _, e := range elements {
+		if modeled := slices.Contains(model, e.Value); modeled != l.Contains(e) {
+			t.Fatalf("element %d is on the list: %t, want %t",
+				e.Value, l.Contains(e), modeled)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+// Package wheel implements a hierarchical timing wheel,
+// which schedules, cancels, and expires timers in constant time.
+package wheel
+
+const (
+	slotBits   = 6
+	slotCount  = 1 << slotBits
+	slotMask   = slotCount - 1
+	levelCount = 6
+	// Timers beyond the range of the wheel's levels
+	// are kept in a single overflow slot, and are
+	// redistributed each time the highest level wraps.
+	overflow = levelCount
+)
+
+type (
+	// Timer is an element of a [Wheel].
+	// The zero value is an unscheduled timer.
+	Timer[T any] struct {
+		next, prev  *Timer[T]
+		Value       T
+		deadline    int64
+		level, slot int
+		scheduled   bool
+	}
+	// Wheel holds timers in slots of increasing granularity.
+	// Times are arbitrary integers (e.g. Unix nanoseconds),
+	// which the wheel divides into ticks of its resolution.
+	// Constructed by [New].
+	Wheel[T any] struct {
+		slots      [levelCount + 1][slotCount]*Timer[T]
+		counts     [levelCount + 1]int
+		now        int64 // In ticks.
+		resolution int64
+	}
+)
+
+// New creates a [Wheel] starting at now,
+// which advances in steps of resolution.
+// Resolution must be positive.
+func New[T any](now, resolution int64) *Wheel[T] {
+	return &Wheel[T]{
+		now:        now / resolution,
+		resolution: resolution,
+	}
+}
+
+// Deadline returns the time the timer was scheduled for.
+func (t *Timer[T]) Deadline() int64 { return t.deadline }
+
+// Scheduled reports whether the timer is pending in a wheel.
+func (t *Timer[T]) Scheduled() bool { return t.scheduled }
+
+// Len returns the number of scheduled timers.
+func (w *Wheel[T]) Len() int {
+	var total int
+	for _, count := range w.counts {
+		total += count
+	}
+	return total
+}
+
+// Schedule (re)schedules the timer to expire at deadline.
+// Deadlines which have already passed expire
+// during the next tick.
+func (w *Wheel[T]) Schedule(timer *Timer[T], deadline int64) {
+	if timer.scheduled {
+		w.remove(timer)
+	}
+	timer.deadline = deadline
+	w.place(timer, w.now+1)
+}
+
+// Cancel removes the timer from the wheel, if it is scheduled.
+func (w *Wheel[T]) Cancel(timer *Timer[T]) {
+	if timer.scheduled {
+		w.remove(timer)
+	}
+}
+
+// Advance moves the wheel forward to now,
+// calling expire with each timer whose deadline was reached.
+// Timers are removed from the wheel before expire is called,
+// and expire may schedule or cancel other timers.
+func (w *Wheel[T]) Advance(now int64, expire func(*Timer[T])) {
+	target := now / w.resolution
+	for w.now < target {
+		if w.Len() == 0 {
+			w.now = target
+			return
+		}
+		w.now = w.nextTick(target)
+		w.cascade()
+		w.expireSlot(expire)
+	}
+}
+
+// nextTick returns the next tick (up to target)
+// at which a slot may need to be processed.
+// If the lower levels are empty, ticks are skipped
+// until the next boundary of the lowest occupied level.
+func (w *Wheel[T]) nextTick(target int64) int64 {
+	level := 0
+	for level < overflow && w.counts[level] == 0 {
+		level++
+	}
+	if level == 0 {
+		return w.now + 1
+	}
+	var (
+		span     = int64(1) << (slotBits * level)
+		boundary = (w.now | (span - 1)) + 1
+	)
+	return min(boundary, target)
+}
+
+// cascade redistributes timers from the higher levels
+// whose slots are reached at the current tick.
+func (w *Wheel[T]) cascade() {
+	top := 0
+	for level := 1; level <= overflow; level++ {
+		span := int64(1) << (slotBits * level)
+		if w.now&(span-1) != 0 {
+			break
+		}
+		top = level
+	}
+	for level := top; level > 0; level-- {
+		slot := w.slotFor(w.now, level)
+		head := w.slots[level][slot]
+		w.slots[level][slot] = nil
+		for timer := head; timer != nil; {
+			next := timer.next
+			w.counts[level]--
+			timer.next, timer.prev = nil, nil
+			w.place(timer, w.now)
+			timer = next
+		}
+	}
+}
+
+func (w *Wheel[T]) expireSlot(expire func(*Timer[T])) {
+	slot := w.slotFor(w.now, 0)
+	for timer := w.slots[0][slot]; timer != nil; timer = w.slots[0][slot] {
+		w.remove(timer)
+		expire(timer)
+	}
+}
+
+// place inserts the timer into the lowest level
+// that can represent its distance from now.
+// Deadlines before the earliest tick are clamped to it.
+func (w *Wheel[T]) place(timer *Timer[T], earliest int64) {
+	var (
+		resolution = w.resolution
+		tick       = max( // Rounded up, to never expire early.
+			(timer.deadline+resolution-1)/resolution,
+			earliest,
+		)
+		level = 0
+	)
+	for ; level < overflow; level++ {
+		shift := slotBits * (level + 1)
+		if tick>>shift == w.now>>shift {
+			break
+		}
+	}
+	slot := w.slotFor(tick, level)
+	if head := w.slots[level][slot]; head != nil {
+		head.prev = timer
+		timer.next = head
+	}
+	w.slots[level][slot] = timer
+	w.counts[level]++
+	timer.level, timer.slot = level, slot
+	timer.scheduled = true
+}
+
+func (w *Wheel[T]) slotFor(tick int64, level int) int {
+	if level == overflow {
+		return 0
+	}
+	return int(tick>>(slotBits*level)) & slotMask
+}
+
+func (w *Wheel[T]) remove(timer *Timer[T]) {
+	if timer.prev != nil {
+		timer.prev.next = timer.next
+	} else {
+		w.slots[timer.level][timer.slot] = timer.next
+	}
+	if timer.next != nil {
+		timer.next.prev = timer.prev
+	}
+	w.counts[timer.level]--
+	timer.next, timer.prev = nil, nil
+	timer.scheduled = false
+}
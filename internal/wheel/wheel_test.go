@@ -0,0 +1,103 @@
+package wheel_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/djdv/go-clockpro/internal/wheel"
+)
+
+func TestWheel(t *testing.T) {
+	t.Run("expires once on time", expiresOnTime)
+	t.Run("cancel", cancel)
+	t.Run("overflow", overflow)
+}
+
+func expiresOnTime(t *testing.T) {
+	t.Parallel()
+	const (
+		resolution = 10
+		timerCount = 4096
+		horizon    = 1 << 24
+		steps      = 2048
+	)
+	var (
+		rng     = rand.New(rand.NewSource(1))
+		clock   int64
+		w       = wheel.New[int](clock, resolution)
+		timers  = make([]wheel.Timer[int], timerCount)
+		expired = make([]bool, timerCount)
+	)
+	for i := range timers {
+		timers[i].Value = i
+		w.Schedule(&timers[i], rng.Int63n(horizon))
+	}
+	for step := range steps {
+		if step%2 == 0 { // Alternate between whole ticks and arbitrary steps.
+			clock += rng.Int63n(horizon/steps/resolution) * resolution
+		} else {
+			clock += rng.Int63n(horizon / steps)
+		}
+		w.Advance(clock, func(timer *wheel.Timer[int]) {
+			index := timer.Value
+			if expired[index] {
+				t.Fatalf("timer %d expired twice", index)
+			}
+			if deadline := timer.Deadline(); deadline > clock {
+				t.Fatalf("timer %d expired early: %d < %d",
+					index, clock, deadline)
+			}
+			expired[index] = true
+		})
+		for i := range timers {
+			// Deadlines are rounded up to the next tick.
+			due := (timers[i].Deadline()+resolution-1)/resolution*resolution <= clock
+			if due && !expired[i] {
+				t.Fatalf("timer %d did not expire: %d >= %d",
+					i, clock, timers[i].Deadline())
+			}
+		}
+	}
+	w.Advance(horizon+resolution, func(timer *wheel.Timer[int]) {
+		expired[timer.Value] = true
+	})
+	if got := w.Len(); got != 0 {
+		t.Errorf("expected all timers to expire but %d remain", got)
+	}
+}
+
+func cancel(t *testing.T) {
+	t.Parallel()
+	var (
+		w     = wheel.New[int](0, 1)
+		timer wheel.Timer[int]
+	)
+	w.Schedule(&timer, 100)
+	w.Cancel(&timer)
+	if timer.Scheduled() || w.Len() != 0 {
+		t.Fatal("expected timer to be removed from the wheel")
+	}
+	w.Advance(200, func(*wheel.Timer[int]) {
+		t.Fatal("canceled timer expired")
+	})
+}
+
+func overflow(t *testing.T) {
+	t.Parallel()
+	const deadline = 1 << 40 // Beyond the highest level.
+	var (
+		w     = wheel.New[int](0, 1)
+		timer wheel.Timer[int]
+		fired bool
+	)
+	w.Schedule(&timer, deadline)
+	expire := func(*wheel.Timer[int]) { fired = true }
+	w.Advance(deadline-1, expire)
+	if fired {
+		t.Fatal("timer expired early")
+	}
+	w.Advance(deadline, expire)
+	if !fired {
+		t.Fatal("timer did not expire")
+	}
+}
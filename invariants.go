@@ -0,0 +1,159 @@
+package clockpro
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// CheckInvariants inspects the internal state of the cache,
+// and returns an error wrapping [ErrInvariant]
+// for each inconsistency that it finds.
+// It is intended for tests, and takes time
+// proportional to the amount of pages.
+func (c *Cache[Key, Value]) CheckInvariants() error {
+	var (
+		errs  []error
+		fault = func(format string, args ...any) {
+			errs = append(errs, fmt.Errorf(
+				"%w: "+format,
+				append([]any{ErrInvariant}, args...)...,
+			))
+		}
+		hot, cold, test, demoted int
+		weight                   int
+		hands                    = map[string]*page[Key, Value]{
+			"hot": c.hot, "cold": c.cold, "test": c.test,
+		}
+	)
+	if err := c.clock.Validate(); err != nil {
+		fault("clock %v", err) // The clock may not be traversable.
+	} else {
+		for page := range c.clock.All() {
+			switch {
+			case page.LIR:
+				hot++
+				if !page.Resident {
+					fault("hot page %v is not resident", page.Name)
+				}
+			case page.Resident:
+				cold++
+			default:
+				test++
+				if !page.Stacked {
+					fault("test page %v is not stacked", page.Name)
+				}
+			}
+			if page.Demoted {
+				demoted++
+			}
+			if page.Resident && c.weigh != nil {
+				weight += c.weigh(page.Name, page.Value)
+			}
+			if indexed, _ := c.index.get(page.Name); indexed != page {
+				fault("page %v is not indexed", page.Name)
+			}
+			for name, hand := range hands {
+				if hand == page {
+					delete(hands, name)
+				}
+			}
+		}
+	}
+	for name, hand := range hands {
+		if hand != nil {
+			fault("%s hand is not within the clock", name)
+		}
+	}
+	if hot != c.hotCount || cold != c.coldCount || test != c.testCount {
+		fault("counted %d hot, %d cold, and %d test pages but expected %d, %d, and %d",
+			hot, cold, test, c.hotCount, c.coldCount, c.testCount)
+	}
+	if pages := hot + cold + test; pages != c.index.len() {
+		fault("clock holds %d pages but index holds %d", pages, c.index.len())
+	}
+	if demoted != c.demotions {
+		fault("counted %d demoted pages but expected %d", demoted, c.demotions)
+	}
+	if weight != c.weight {
+		fault("counted weight %d but expected %d", weight, c.weight)
+	}
+	if c.weigh != nil && c.weight > c.maxWeight {
+		fault("weight %d exceeds maximum %d", c.weight, c.maxWeight)
+	}
+	if residents := hot + cold; residents > c.capacity {
+		fault("%d resident pages exceed capacity %d", residents, c.capacity)
+	}
+	if (hot+cold != 0 && c.hot == nil) ||
+		(cold != 0 && c.cold == nil) ||
+		(test != 0 && c.test == nil) {
+		fault("hand is missing")
+	}
+	if c.coldTarget < c.coldMinimum || c.coldTarget > c.coldMaximum ||
+		c.hotTarget != c.capacity-c.coldTarget {
+		fault("targets %d cold and %d hot are out of bounds",
+			c.coldTarget, c.hotTarget)
+	}
+	if c.sampled != nil {
+		if sampled := len(c.sampled.pages); sampled != hot+cold {
+			fault("%d sampled pages for %d resident pages", sampled, hot+cold)
+		}
+		for i, ref := range c.sampled.pages {
+			page := c.clock.At(ref)
+			if indexed, _ := c.index.get(page.Name); !page.Resident || indexed != page || int(page.Slot) != i+1 {
+				fault("sampled page %v is not resident in slot %d", page.Name, i+1)
+			}
+		}
+	}
+	for key := range c.expiry.timers {
+		if page, ok := c.index.get(key); !ok || !page.Resident {
+			fault("expiration scheduled for nonresident key %v", key)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkEntries reports entries served to concurrent readers
+// which do not match the resident pages of the cache.
+func (c *Cache[Key, Value]) checkEntries(entries iter.Seq2[Key, *syncedEntry[Key, Value]]) error {
+	var (
+		errs  []error
+		count int
+	)
+	for key, entry := range entries {
+		count++
+		page, ok := c.index.get(key)
+		if !ok || page != entry.page || !page.Resident ||
+			page.Generation() != entry.generation {
+			errs = append(errs, fmt.Errorf(
+				"%w: entry for key %v is not resident",
+				ErrInvariant, key,
+			))
+			continue
+		}
+		if deadline, _ := c.expiry.deadline(key); deadline != entry.deadline {
+			errs = append(errs, fmt.Errorf(
+				"%w: entry for key %v has deadline %d but expected %d",
+				ErrInvariant, key, entry.deadline, deadline,
+			))
+		}
+	}
+	if residents := c.Len(); count != residents {
+		errs = append(errs, fmt.Errorf(
+			"%w: %d entries for %d resident pages",
+			ErrInvariant, count, residents,
+		))
+	}
+	return errors.Join(errs...)
+}
+
+// checkPartitions joins the errors of each partition.
+func checkPartitions[Partition any](kind string, partitions []Partition, check func(Partition) error) error {
+	errs := make([]error, len(partitions))
+	for i, partition := range partitions {
+		if err := check(partition); err != nil {
+			errs[i] = fmt.Errorf("%s %d: %w", kind, i, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,133 @@
+package clockpro
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+)
+
+type (
+	// Journal appends a record to a writer whenever a key
+	// becomes resident or is dropped from a cache, so that
+	// its warm set may be reconstructed by [ReadJournal]
+	// without taking snapshots. See [WithJournal].
+	Journal[Key comparable] struct {
+		encoder *gob.Encoder
+		err     error
+	}
+	journalRecord[Key comparable] struct {
+		Key     Key
+		Dropped bool
+	}
+)
+
+// NewJournal constructs a [Journal] which
+// encodes records to w with [encoding/gob].
+// Keys must be encodable by gob.
+func NewJournal[Key comparable](w io.Writer) *Journal[Key] {
+	return &Journal[Key]{encoder: gob.NewEncoder(w)}
+}
+
+// WithJournal records the keys which become resident
+// or are dropped from the cache to journal.
+// Records are written while the cache is being modified,
+// so writes to the journal's writer should be buffered.
+// A journal must not be shared by caches which may be
+// modified concurrently, such as the shards of [Sharded].
+// See [Cache.CompactJournal] to bound the journal's size.
+func WithJournal[Key comparable, Value any](journal *Journal[Key]) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if journal == nil {
+			return fmt.Errorf("%w: journal must not be nil", ErrInvalidOption)
+		}
+		set.journal = journal
+		return nil
+	}
+}
+
+// Err returns the error of the first record which
+// could not be written, if any. Once a write fails,
+// no further records are written.
+func (j *Journal[_]) Err() error { return j.err }
+
+// CompactJournal replaces the writer of the cache's journal
+// with w, and records each resident key to it, in the order
+// of [Cache.Pages], so that w holds only the current warm set.
+// Once it returns, the previous journal may be discarded.
+// CompactJournal returns [ErrInvalidOption] if the cache
+// was not constructed with [WithJournal].
+func (c *Cache[Key, Value]) CompactJournal(w io.Writer) error {
+	journal := c.journal
+	if journal == nil {
+		return fmt.Errorf("%w: cache has no journal", ErrInvalidOption)
+	}
+	journal.encoder = gob.NewEncoder(w)
+	journal.err = nil
+	for page := range c.clock.All() {
+		if page.Resident {
+			journal.record(page.Name, false)
+		}
+	}
+	return journal.err
+}
+
+// ReadJournal replays the records of a [Journal] from r,
+// and returns the keys which were resident at the end of it,
+// in the order that their records were written. If the journal ends
+// with an incomplete record, such as when a process exits
+// during a write, the keys before it are returned with the error.
+func ReadJournal[Key comparable](r io.Reader) ([]Key, error) {
+	var (
+		decoder = gob.NewDecoder(r)
+		order   = make(map[Key]int)
+		inserts int
+		err     error
+	)
+	for {
+		var record journalRecord[Key]
+		if err = decoder.Decode(&record); err != nil {
+			break
+		}
+		if record.Dropped {
+			delete(order, record.Key)
+			continue
+		}
+		order[record.Key] = inserts
+		inserts++
+	}
+	if errors.Is(err, io.EOF) {
+		err = nil
+	}
+	keys := make([]Key, inserts)
+	present := make([]bool, inserts)
+	for key, i := range order {
+		keys[i], present[i] = key, true
+	}
+	resident := keys[:0]
+	for i, key := range keys {
+		if present[i] {
+			resident = append(resident, key)
+		}
+	}
+	return resident, err
+}
+
+func (j *Journal[Key]) inserted(key Key) {
+	if j != nil {
+		j.record(key, false)
+	}
+}
+
+func (j *Journal[Key]) dropped(key Key) {
+	if j != nil {
+		j.record(key, true)
+	}
+}
+
+func (j *Journal[Key]) record(key Key, dropped bool) {
+	if j.err != nil {
+		return
+	}
+	j.err = j.encoder.Encode(journalRecord[Key]{Key: key, Dropped: dropped})
+}
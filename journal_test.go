@@ -0,0 +1,147 @@
+package clockpro_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestJournal(t *testing.T) {
+	t.Run("invalid", journalInvalid)
+	t.Run("replay", journalReplay)
+	t.Run("compact", journalCompact)
+	t.Run("truncated", journalTruncated)
+}
+
+func newJournaled(t *testing.T, w io.Writer) *clockpro.Cache[int, int] {
+	t.Helper()
+	cache, err := clockpro.New(8,
+		clockpro.WithJournal[int, int](clockpro.NewJournal[int](w)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key := range 32 {
+		cache.Set(key%12, key)
+		if key%5 == 0 {
+			cache.Delete(key / 2)
+		}
+	}
+	cache.Invalidate(clockOrder(cache)[0])
+	return cache
+}
+
+// clockOrder returns the resident keys of
+// cache, in the order of [clockpro.Cache.Pages].
+func clockOrder(cache *clockpro.Cache[int, int]) []int {
+	var keys []int
+	for key, info := range cache.Pages() {
+		if info.Resident {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// checkJournal compares the keys of the journal with the
+// resident keys of the cache, in order if ordered is true.
+func checkJournal(t *testing.T, journal []byte, cache *clockpro.Cache[int, int], ordered bool) {
+	t.Helper()
+	got, err := clockpro.ReadJournal[int](bytes.NewReader(journal))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := clockOrder(cache)
+	if !ordered {
+		slices.Sort(got)
+		slices.Sort(want)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf(
+			"journal does not match resident keys"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			got, want,
+		)
+	}
+}
+
+func journalInvalid(t *testing.T) {
+	t.Parallel()
+	if _, err := clockpro.New(8,
+		clockpro.WithJournal[int, int](nil),
+	); !errors.Is(err, clockpro.ErrInvalidOption) {
+		t.Errorf(
+			"expected error to match"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			err, clockpro.ErrInvalidOption,
+		)
+	}
+	cache, err := clockpro.New[int, int](8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.CompactJournal(io.Discard); !errors.Is(err, clockpro.ErrInvalidOption) {
+		t.Errorf(
+			"expected error to match"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			err, clockpro.ErrInvalidOption,
+		)
+	}
+}
+
+func journalReplay(t *testing.T) {
+	t.Parallel()
+	var journal bytes.Buffer
+	cache := newJournaled(t, &journal)
+	checkJournal(t, journal.Bytes(), cache, false)
+}
+
+func journalCompact(t *testing.T) {
+	t.Parallel()
+	var (
+		journal, compacted bytes.Buffer
+		cache              = newJournaled(t, &journal)
+	)
+	if err := cache.CompactJournal(&compacted); err != nil {
+		t.Fatal(err)
+	}
+	if compacted.Len() >= journal.Len() {
+		t.Errorf(
+			"compacted journal is not smaller"+
+				"\n\tgot: %d"+
+				"\n\twant: <%d",
+			compacted.Len(), journal.Len(),
+		)
+	}
+	checkJournal(t, compacted.Bytes(), cache, true)
+	cache.Set(100, 100)
+	checkJournal(t, compacted.Bytes(), cache, false)
+	cache.Purge(nil)
+	checkJournal(t, compacted.Bytes(), cache, false)
+}
+
+func journalTruncated(t *testing.T) {
+	t.Parallel()
+	var journal bytes.Buffer
+	newJournaled(t, &journal)
+	truncated := journal.Bytes()[:journal.Len()-1]
+	keys, err := clockpro.ReadJournal[int](bytes.NewReader(truncated))
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf(
+			"expected error to match"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			err, io.ErrUnexpectedEOF,
+		)
+	}
+	if len(keys) == 0 {
+		t.Error("keys before the truncated record were not returned")
+	}
+}
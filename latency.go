@@ -0,0 +1,75 @@
+package clockpro
+
+import (
+	"math/bits"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// latencies counts the durations of operations,
+	// in nanoseconds. Buckets are updated atomically,
+	// since the fetches of concurrent caches are timed
+	// without holding their lock.
+	latencies struct {
+		get, set, fetch atomicHistogram
+	}
+	atomicHistogram struct {
+		buckets [len(Histogram{}.Buckets)]atomic.Uint64
+	}
+)
+
+// WithLatencies records the durations of [Cache.Get],
+// of the modifications made by [Cache.Set] (and its variants),
+// and of the fetch functions of [Cache.Load] (and its variants),
+// in [Stats.GetLatencies], [Stats.SetLatencies],
+// and [Stats.FetchLatencies] respectively, such as to find
+// whether the cache itself contributes to tail latency.
+// Hits served without locking the cache,
+// by [Synced], [Striped], and [Actor], are not timed.
+func WithLatencies[Key comparable, Value any]() Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		set.latencies = new(latencies)
+		return nil
+	}
+}
+
+// record adds the time elapsed since start to ah.
+func (ah *atomicHistogram) record(start time.Time) {
+	elapsed := uint64(max(time.Since(start), 0))
+	ah.buckets[bits.Len64(elapsed)].Add(1)
+}
+
+func (ah *atomicHistogram) load() Histogram {
+	var histogram Histogram
+	for i := range ah.buckets {
+		histogram.Buckets[i] = ah.buckets[i].Load()
+	}
+	return histogram
+}
+
+func (ah *atomicHistogram) store(histogram *Histogram) {
+	for i, bucket := range histogram.Buckets {
+		ah.buckets[i].Store(bucket)
+	}
+}
+
+// fill sets the latencies of stats.
+func (lt *latencies) fill(stats *Stats) {
+	stats.GetLatencies = lt.get.load()
+	stats.SetLatencies = lt.set.load()
+	stats.FetchLatencies = lt.fetch.load()
+}
+
+func (lt *latencies) clone() *latencies {
+	clone := new(latencies)
+	for _, histograms := range [...][2]*atomicHistogram{
+		{&lt.get, &clone.get},
+		{&lt.set, &clone.set},
+		{&lt.fetch, &clone.fetch},
+	} {
+		histogram := histograms[0].load()
+		histograms[1].store(&histogram)
+	}
+	return clone
+}
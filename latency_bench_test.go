@@ -0,0 +1,114 @@
+package clockpro_test
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/djdv/go-clockpro"
+)
+
+// BenchmarkTailLatency times each Set individually under
+// patterns which make the policy do more work than usual
+// within some operations, and reports the quantiles
+// of their durations alongside the mean.
+func BenchmarkTailLatency(b *testing.B) {
+	const capacity = 1 << 12
+	for _, pattern := range []struct {
+		name string
+		// next returns the key of the i'th Set,
+		// and may modify the cache beforehand,
+		// without being timed.
+		next func(cache *clockpro.Cache[int, int], rng *rand.Rand, i int) int
+	}{
+		{
+			"uniform",
+			func(_ *clockpro.Cache[int, int], rng *rand.Rand, _ int) int {
+				return rng.Intn(capacity * 2)
+			},
+		},
+		{
+			// Looping over more keys than the capacity
+			// resurrects test pages, promoting them en masse.
+			"ghost promotion",
+			func(_ *clockpro.Cache[int, int], _ *rand.Rand, i int) int {
+				return i % (capacity * 3 / 2)
+			},
+		},
+		{
+			// Swinging the target between its bounds leaves
+			// many hot pages to be demoted by promotions.
+			"target swings",
+			func(cache *clockpro.Cache[int, int], rng *rand.Rand, i int) int {
+				if i%capacity == 0 {
+					target := 1
+					if i/capacity%2 == 0 {
+						target = capacity / 2
+					}
+					if err := cache.SetColdTarget(target); err != nil {
+						b.Fatal(err)
+					}
+				}
+				return rng.Intn(capacity * 3 / 2)
+			},
+		},
+		{
+			// Alternating scans and loops move
+			// the target between its extremes.
+			"scan and loop phases",
+			func(_ *clockpro.Cache[int, int], rng *rand.Rand, i int) int {
+				const phase = capacity * 4
+				if i/phase%2 == 0 {
+					return capacity + i // Never repeats.
+				}
+				return rng.Intn(capacity / 2)
+			},
+		},
+	} {
+		b.Run(pattern.name, func(b *testing.B) {
+			cache, err := clockpro.New[int, int](capacity)
+			if err != nil {
+				b.Fatal(err)
+			}
+			var (
+				rng       = rand.New(rand.NewSource(rngSeed))
+				latencies []time.Duration
+				i         int
+			)
+			b.ResetTimer()
+			for b.Loop() {
+				b.StopTimer()
+				key := pattern.next(cache, rng, i)
+				b.StartTimer()
+				start := time.Now()
+				cache.Set(key, key)
+				latencies = append(latencies, time.Since(start))
+				i++
+			}
+			b.StopTimer()
+			reportQuantiles(b, latencies)
+		})
+	}
+}
+
+// reportQuantiles reports the median, tail
+// quantiles, and maximum of latencies.
+func reportQuantiles(b *testing.B, latencies []time.Duration) {
+	if len(latencies) == 0 {
+		return
+	}
+	slices.Sort(latencies)
+	for _, quantile := range []struct {
+		unit string
+		q    float64
+	}{
+		{"p50-ns", 0.5},
+		{"p99-ns", 0.99},
+		{"p999-ns", 0.999},
+		{"max-ns", 1},
+	} {
+		rank := min(int(quantile.q*float64(len(latencies))), len(latencies)-1)
+		b.ReportMetric(float64(latencies[rank].Nanoseconds()), quantile.unit)
+	}
+}
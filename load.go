@@ -0,0 +1,116 @@
+package clockpro
+
+// GetMany is like [Cache.Get] for each of keys,
+// returning the values of the keys which hit, and the
+// keys which missed, in the order given, without duplicates,
+// such that they may be passed to the fetch function
+// of [Cache.LoadMany] or [NewBatcher].
+func (c *Cache[Key, Value]) GetMany(keys []Key) (found map[Key]Value, missing []Key) {
+	return getMany(keys, c.Get)
+}
+
+// getMany calls get for each distinct key,
+// like [Cache.GetMany].
+func getMany[Key comparable, Value any](keys []Key, get func(Key) (Value, bool)) (found map[Key]Value, missing []Key) {
+	found = make(map[Key]Value, len(keys))
+	seen := make(map[Key]struct{}, len(keys))
+	for _, key := range keys {
+		if _, dupe := seen[key]; dupe {
+			continue
+		}
+		seen[key] = struct{}{}
+		if value, hit := get(key); hit {
+			found[key] = value
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	return found, missing
+}
+
+// LoadMany returns the cached values for keys (if resident).
+// Otherwise, it calls fetch once with all of the missing keys,
+// inserting and returning the values it provides on success.
+// Keys missing from both the cache and fetch's result
+// are omitted from the returned map.
+// If fetch returns an error, its values are not cached
+// and only the values that were resident are returned,
+// with the error wrapped by [ErrFetchFailed].
+// Like [Cache.Load], fetch may use the cache, and the
+// values of keys which it sets itself are retained.
+func (c *Cache[Key, Value]) LoadMany(keys []Key, fetch func(missing []Key) (map[Key]Value, error)) (map[Key]Value, error) {
+	values, missing := c.GetMany(keys)
+	if len(missing) == 0 {
+		return values, nil
+	}
+	fetched, err := fetch(missing)
+	if err != nil {
+		return values, fetchError(err)
+	}
+	for _, key := range missing {
+		value, ok := fetched[key]
+		if !ok {
+			continue
+		}
+		values[key], _ = c.setIfAbsent(key, value)
+	}
+	return values, nil
+}
+
+// LoadManyPartial is like [Cache.LoadMany], but fetch reports
+// an error for each key which it failed to fetch, rather than
+// failing the whole batch, so that the values which were fetched
+// are cached regardless. The errors are returned by key,
+// wrapped by [ErrFetchFailed], along with [ErrNotFound] for the
+// keys which are missing from both of fetch's results.
+// The returned error map is nil if there were no errors.
+func (c *Cache[Key, Value]) LoadManyPartial(keys []Key, fetch func(missing []Key) (map[Key]Value, map[Key]error)) (map[Key]Value, map[Key]error) {
+	values, missing := c.GetMany(keys)
+	if len(missing) == 0 {
+		return values, nil
+	}
+	var (
+		fetched, failed = fetch(missing)
+		errs            map[Key]error
+	)
+	for _, key := range missing {
+		if value, ok := fetched[key]; ok {
+			values[key], _ = c.setIfAbsent(key, value)
+			continue
+		}
+		if errs == nil {
+			errs = make(map[Key]error)
+		}
+		if err, ok := failed[key]; ok {
+			errs[key] = fetchError(err)
+		} else {
+			errs[key] = ErrNotFound
+		}
+	}
+	return values, errs
+}
+
+// Prime inserts the values of keys which are not resident,
+// in the order given, such as to warm a new cache with the
+// keys which were popular within another. Keys for which
+// fetch returns an error are skipped. Once the cache is full,
+// keys are inserted by eviction as usual, so keys near the
+// end of a list longer than the capacity are favored.
+// Priming does not count as an access of resident keys.
+// Prime returns the amount of keys which were inserted.
+func (c *Cache[Key, Value]) Prime(keys []Key, fetch func(Key) (Value, error)) int {
+	var inserted int
+	for _, key := range keys {
+		if page, ok := c.index.get(key); ok && page.Resident && !c.expired(key) {
+			continue
+		}
+		value, err := fetch(key)
+		if err != nil {
+			continue
+		}
+		if _, loaded := c.setIfAbsent(key, value); !loaded {
+			inserted++
+		}
+	}
+	return inserted
+}
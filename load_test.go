@@ -0,0 +1,508 @@
+package clockpro_test
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"runtime/pprof"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("ghost", loadGhost)
+	t.Run("report", loadReport)
+	t.Run("many", loadMany)
+	t.Run("get many", loadGetMany)
+	t.Run("reentrant", loadReentrant)
+	t.Run("fetch sets key", loadFetchSetsKey)
+	t.Run("prime", loadPrime)
+	t.Run("profile labels", loadProfileLabels)
+	t.Run("spans", loadSpans)
+	t.Run("retries", loadRetries)
+}
+
+// loadSpans checks that each load is reported to
+// the span function, and that the context it returns
+// is given to fetch.
+func loadRetries(t *testing.T) {
+	t.Parallel()
+	var (
+		transient = errors.New("transient")
+		permanent = errors.New("permanent")
+		retryable = func(err error) bool { return errors.Is(err, transient) }
+	)
+	cache, err := clockpro.New(8,
+		clockpro.WithRetries[int, int](2, time.Millisecond, retryable),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, test := range []struct {
+		name      string
+		failures  []error
+		wantCalls int
+		wantErr   error
+	}{
+		{"recovers", []error{transient, transient}, 3, nil},
+		{"gives up", []error{transient, transient, transient}, 3, transient},
+		{"permanent", []error{permanent}, 1, permanent},
+	} {
+		var calls int
+		_, err := cache.Load(key, func() (int, error) {
+			if calls++; calls <= len(test.failures) {
+				return 0, test.failures[calls-1]
+			}
+			return key, nil
+		})
+		if !errors.Is(err, test.wantErr) || calls != test.wantCalls {
+			t.Errorf(
+				"%s: unexpected result of retries"+
+					"\n\tgot: %d calls, %v"+
+					"\n\twant: %d calls, %v",
+				test.name, calls, err, test.wantCalls, test.wantErr,
+			)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var calls int
+	if _, err := cache.LoadContext(ctx, 10, func(context.Context) (int, error) {
+		calls++
+		return 0, transient
+	}); !errors.Is(err, transient) || calls != 1 {
+		t.Errorf("expected retries to stop once the context is done, got: %d calls, %v", calls, err)
+	}
+	if _, err := clockpro.New(8,
+		clockpro.WithRetries[int, int](0, 0, nil),
+	); !errors.Is(err, clockpro.ErrInvalidOption) {
+		t.Errorf("expected invalid retries to be rejected, got: %v", err)
+	}
+}
+
+func loadSpans(t *testing.T) {
+	t.Parallel()
+	type (
+		spanKey struct{}
+		span    struct {
+			key  int
+			hit  bool
+			err  bool
+			ends int
+		}
+	)
+	var (
+		spans []*span
+		start = func(ctx context.Context, key int, _ uint64) (context.Context, func(bool, error)) {
+			s := &span{key: key}
+			spans = append(spans, s)
+			return context.WithValue(ctx, spanKey{}, s), func(hit bool, err error) {
+				s.hit, s.err = hit, err != nil
+				s.ends++
+			}
+		}
+		fetchErr = errors.New("backend unavailable")
+		fetch    = func(ctx context.Context) (int, error) {
+			if ctx.Value(spanKey{}) != spans[len(spans)-1] {
+				t.Error("fetch was not given the context of its span")
+			}
+			if spans[len(spans)-1].key == 3 {
+				return 0, fetchErr
+			}
+			return 1, nil
+		}
+	)
+	if _, err := clockpro.New(8, clockpro.WithLoadSpans[int, int](nil)); err == nil {
+		t.Error("expected an error for a nil span function")
+	}
+	cache, err := clockpro.NewSynced(8, clockpro.WithLoadSpans[int, int](start))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	cache.LoadContext(ctx, 1, fetch)
+	cache.LoadContext(ctx, 1, fetch)
+	if _, err := cache.LoadContext(ctx, 3, fetch); !errors.Is(err, fetchErr) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	cache.Load(2, func() (int, error) { return 2, nil })
+	want := []span{
+		{key: 1, ends: 1},
+		{key: 1, hit: true, ends: 1},
+		{key: 3, err: true, ends: 1},
+		{key: 2, ends: 1},
+	}
+	if len(spans) != len(want) {
+		t.Fatalf(
+			"unexpected span count"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			len(spans), len(want))
+	}
+	for i, got := range spans {
+		if *got != want[i] {
+			t.Errorf(
+				"unexpected span %d"+
+					"\n\tgot: %+v"+
+					"\n\twant: %+v",
+				i, *got, want[i])
+		}
+	}
+}
+
+// loadProfileLabels checks that fetches are labeled,
+// as reported by the goroutine profile.
+func loadProfileLabels(t *testing.T) {
+	t.Parallel()
+	const name = "load-profile-labels"
+	if _, err := clockpro.New(8, clockpro.WithProfileLabels[int, int]("")); err == nil {
+		t.Error("expected an error for an empty name")
+	}
+	cache, err := clockpro.NewSynced(8, clockpro.WithProfileLabels[int, int](name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var profile strings.Builder
+	if _, err := cache.Load(1, func() (int, error) {
+		return 1, pprof.Lookup("goroutine").WriteTo(&profile, 1)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for _, label := range []string{
+		strconv.Quote(clockpro.ProfileLabelCache) + ":" + strconv.Quote(name),
+		strconv.Quote(clockpro.ProfileLabelKeyBucket) + ":",
+	} {
+		if !strings.Contains(profile.String(), label) {
+			t.Errorf("goroutine profile does not contain label %s", label)
+		}
+	}
+}
+
+func loadPrime(t *testing.T) {
+	t.Parallel()
+	var (
+		cache, err = clockpro.New[int, int](8)
+		fetched    []int
+		fetch      = func(key int) (int, error) {
+			fetched = append(fetched, key)
+			if key == 3 {
+				return 0, errors.New("backend does not have 3")
+			}
+			return key, nil
+		}
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Set(1, 1)
+	if got, want := cache.Prime([]int{4, 1, 3, 2}, fetch), 2; got != want {
+		t.Errorf(
+			"unexpected amount of keys inserted"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			got, want)
+	}
+	if want := []int{4, 3, 2}; !slices.Equal(fetched, want) {
+		t.Errorf(
+			"expected only non-resident keys to be fetched in order"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			fetched, want)
+	}
+	for _, key := range []int{1, 2, 4} {
+		checkGet(t, cache, key, key, "after Prime")
+	}
+	mustMiss(t, cache, 3, "fetch error")
+}
+
+func loadGhost(t *testing.T) {
+	t.Parallel()
+	const capacity = 2
+	var (
+		ghostHit bool
+		hooks    = clockpro.Hooks[int, int]{
+			OnGhostHit: func(int, int) { ghostHit = true },
+		}
+		cache, err = clockpro.New(capacity, clockpro.WithHooks(hooks))
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addIncrementingInts(cache, capacity)
+	cache.Set(3, 3) // Evicts 2 (cold).
+	if _, err := cache.Load(2, func() (int, error) { return 2, nil }); err != nil {
+		t.Fatal(err)
+	}
+	if !ghostHit {
+		t.Error("expected Load to resurrect the test page of an evicted key")
+	}
+}
+
+func loadReport(t *testing.T) {
+	t.Parallel()
+	const capacity = 2
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		fetchErr = errors.New("backend unavailable")
+		fetch    = func() (int, error) { return 1, nil }
+		fail     = func() (int, error) { return 0, fetchErr }
+	)
+	for _, test := range []struct {
+		name  string
+		key   int
+		fetch func() (int, error)
+		hit   bool
+		err   error
+	}{
+		{"miss", 1, fetch, false, nil},
+		{"hit", 1, fail, true, nil},
+		{"failed fetch", 2, fail, false, fetchErr},
+		{"failed fetch sentinel", 2, fail, false, clockpro.ErrFetchFailed},
+	} {
+		_, hit, err := cache.LoadReport(test.key, test.fetch)
+		if hit != test.hit || !errors.Is(err, test.err) {
+			t.Errorf(
+				"unexpected %s report"+
+					"\n\tgot: %t, %v"+
+					"\n\twant: %t, %v",
+				test.name, hit, err, test.hit, test.err)
+		}
+	}
+}
+
+func loadMany(t *testing.T) {
+	const capacity = 8
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+	t.Run("fetches misses once", func(t *testing.T) {
+		var (
+			calls   int
+			fetched []int
+			keys    = []int{1, 2, 3, 4, 3}
+			fetch   = func(missing []int) (map[int]int, error) {
+				calls++
+				fetched = append(fetched, missing...)
+				values := make(map[int]int, len(missing))
+				for _, key := range missing {
+					if key != 4 { // Backend does not have 4.
+						values[key] = key
+					}
+				}
+				return values, nil
+			}
+		)
+		values, err := cache.LoadMany(keys, fetch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if calls != 1 {
+			t.Errorf("expected fetch to be called once, was called %d times", calls)
+		}
+		if want := []int{3, 4}; !slices.Equal(fetched, want) {
+			t.Errorf(
+				"expected only missing keys to be fetched"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				fetched, want)
+		}
+		got := slices.Sorted(maps.Keys(values))
+		if want := []int{1, 2, 3}; !slices.Equal(got, want) {
+			t.Errorf(
+				"unexpected keys returned"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				got, want)
+		}
+		checkGet(t, cache, 3, 3, "after LoadMany")
+	})
+	t.Run("fetch error", func(t *testing.T) {
+		fetchErr := errors.New("backend unavailable")
+		values, err := cache.LoadMany([]int{1, 5}, func([]int) (map[int]int, error) {
+			return map[int]int{5: 5}, fetchErr
+		})
+		if !errors.Is(err, fetchErr) || !errors.Is(err, clockpro.ErrFetchFailed) {
+			t.Fatalf("expected fetch error, got: %v", err)
+		}
+		if _, ok := values[1]; !ok {
+			t.Error("expected resident values to be returned with fetch error")
+		}
+		mustMiss(t, cache, 5, "fetch error")
+	})
+	t.Run("partial", func(t *testing.T) {
+		fetchErr := errors.New("shard unavailable")
+		values, errs := cache.LoadManyPartial([]int{1, 6, 7, 8}, func([]int) (map[int]int, map[int]error) {
+			return map[int]int{6: 6}, map[int]error{7: fetchErr}
+		})
+		got := slices.Sorted(maps.Keys(values))
+		if want := []int{1, 6}; !slices.Equal(got, want) {
+			t.Errorf(
+				"unexpected keys returned"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				got, want)
+		}
+		checkGet(t, cache, 6, 6, "after LoadManyPartial")
+		if err := errs[7]; !errors.Is(err, fetchErr) || !errors.Is(err, clockpro.ErrFetchFailed) {
+			t.Errorf("expected fetch error for key 7, got: %v", err)
+		}
+		if err := errs[8]; !errors.Is(err, clockpro.ErrNotFound) {
+			t.Errorf("expected not found error for key 8, got: %v", err)
+		}
+		if len(errs) != 2 {
+			t.Errorf("expected errors for 2 keys, got: %v", errs)
+		}
+	})
+}
+
+func loadGetMany(t *testing.T) {
+	t.Parallel()
+	const capacity = 8
+	type batchCache interface {
+		Set(int, int)
+		GetMany([]int) (map[int]int, []int)
+	}
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		name  string
+		cache batchCache
+	}{
+		{"cache", cache},
+		{"synced", newSynced(t, capacity)},
+		{"sharded", newSharded(t, capacity, 2)},
+	} {
+		test.cache.Set(1, 1)
+		test.cache.Set(3, 3)
+		found, missing := test.cache.GetMany([]int{4, 1, 2, 4, 3, 1})
+		if want := map[int]int{1: 1, 3: 3}; !maps.Equal(found, want) {
+			t.Errorf(
+				"%s: unexpected values"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				test.name, found, want)
+		}
+		if want := []int{4, 2}; !slices.Equal(missing, want) {
+			t.Errorf(
+				"%s: unexpected missing keys"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				test.name, missing, want)
+		}
+	}
+}
+
+// loadReentrant fetches values which depend on other keys,
+// which are loaded from within fetch.
+func loadReentrant(t *testing.T) {
+	t.Parallel()
+	type loadingCache interface {
+		testCache[int, int]
+		Load(int, func() (int, error)) (int, error)
+		Delete(int) bool
+	}
+	const (
+		capacity = 16
+		shards   = 2
+		// Every key fits within a single partition,
+		// however they are distributed.
+		depth = capacity/shards/2 - 1
+	)
+	for _, test := range []struct {
+		name  string
+		cache loadingCache
+	}{
+		{"cache", newCache[int, int](t, capacity).(loadingCache)},
+		{"synced", newSynced(t, capacity)},
+		{"sharded", newSharded(t, capacity, shards)},
+		{"striped", newStriped(t, capacity, shards)},
+		{"actor", newActor(t, capacity)},
+	} {
+		var (
+			cache = test.cache
+			load  func(key int) (int, error)
+		)
+		load = func(key int) (int, error) {
+			return cache.Load(key, func() (int, error) {
+				cache.Delete(key)
+				if key == 0 {
+					return 0, nil
+				}
+				previous, err := load(key - 1)
+				return previous + 1, err
+			})
+		}
+		if _, err := load(depth); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		for key := range depth + 1 {
+			checkGet(t, cache, key, key, test.name+" loaded value")
+		}
+	}
+}
+
+// loadFetchSetsKey loads keys of each state
+// (new, resident test pages, and forgotten) with
+// fetch functions which set the key being loaded.
+func loadFetchSetsKey(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 8
+		keys     = capacity * 4
+	)
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	setting := func(key int) (int, error) {
+		cache.Set(key, -key)
+		return key, nil
+	}
+	for key := range keys {
+		value, err := cache.Load(key, func() (int, error) { return setting(key) })
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value != -key {
+			t.Errorf(
+				"expected value set by fetch to be returned"+
+					"\n\tgot: %d"+
+					"\n\twant: %d",
+				value, -key,
+			)
+		}
+		if err := cache.CheckInvariants(); err != nil {
+			t.Fatalf("after loading key %d: %v", key, err)
+		}
+	}
+	_, err = cache.LoadMany([]int{0, 1, 2, keys}, func(missing []int) (map[int]int, error) {
+		values := make(map[int]int, len(missing))
+		for _, key := range missing {
+			values[key], _ = setting(key)
+		}
+		return values, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.CheckInvariants(); err != nil {
+		t.Fatalf("after LoadMany: %v", err)
+	}
+	cache.Prime([]int{3, 4, keys + 1}, setting)
+	if err := cache.CheckInvariants(); err != nil {
+		t.Fatalf("after Prime: %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package clockpro
+
+import (
+	"unsafe"
+
+	"github.com/djdv/go-clockpro/internal/list"
+	"github.com/djdv/go-clockpro/internal/wheel"
+)
+
+// MemoryUsage estimates the amount of bytes held by the cache,
+// counting its pages (including the test pages of evicted keys),
+// the index of them, and the timers of entries which expire.
+// If the cache was constructed with [WithMaxWeight], the total
+// weight of the resident values is added, as if it was
+// measured in bytes. Otherwise, memory referenced by keys and
+// values, such as the contents of strings, is not counted,
+// and neither are the structures of optional features,
+// such as the filters of [WithDoorkeeper] and [WithGhosts].
+func (c *Cache[Key, Value]) MemoryUsage() int {
+	var (
+		key     Key
+		keySize = int(unsafe.Sizeof(key))
+		pointer = int(unsafe.Sizeof(uintptr(0)))
+		ref     = int(unsafe.Sizeof(list.Ref(0)))
+		pages   = c.clock.Len()
+		usage   = int(unsafe.Sizeof(*c)) +
+			c.clock.Cap()*int(unsafe.Sizeof(page[Key, Value]{}))
+	)
+	if table := c.index.table; table != nil {
+		usage += len(table.slots) * int(unsafe.Sizeof(tableSlot{}))
+	} else {
+		usage += mapSize(pages, keySize+ref)
+	}
+	if timers := len(c.expiry.timers); timers != 0 {
+		usage += timers*int(unsafe.Sizeof(wheel.Timer[Key]{})) +
+			mapSize(timers, keySize+pointer)
+	}
+	return usage + c.weight
+}
+
+// mapSize estimates the amount of bytes held by a map
+// of count entries, which are each size bytes,
+// accounting for a control byte per entry,
+// and the map's maximum load factor of 7/8.
+func mapSize(count, size int) int {
+	return count * (size + 1) * 8 / 7
+}
@@ -0,0 +1,48 @@
+package clockpro_test
+
+import (
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestMemoryUsage(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 64
+		size     = 100
+	)
+	var (
+		weigh          = func(int, []byte) int { return size }
+		plain, err     = clockpro.New[int, []byte](capacity)
+		weighted, wErr = clockpro.New(capacity, clockpro.WithMaxWeight(weigh, capacity*size))
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wErr != nil {
+		t.Fatal(wErr)
+	}
+	empty := plain.MemoryUsage()
+	for key := range capacity {
+		plain.Set(key, make([]byte, size))
+		weighted.Set(key, make([]byte, size))
+	}
+	filled := plain.MemoryUsage()
+	if filled <= empty {
+		t.Errorf(
+			"expected usage to grow with entries"+
+				"\n\tgot: %d"+
+				"\n\twant: >%d",
+			filled, empty,
+		)
+	}
+	if got, want := weighted.MemoryUsage()-filled, weighted.Weight(); got != want {
+		t.Errorf(
+			"expected usage to include weight"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			got, want,
+		)
+	}
+}
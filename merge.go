@@ -0,0 +1,61 @@
+package clockpro
+
+// Merge imports the resident entries of other into the cache,
+// hot entries first, until the cache is at capacity, or,
+// if it was constructed with [WithMaxWeight], until the next
+// entry would exceed its maximum weight. Entries of other
+// which have expired are not imported.
+// If a key is resident in both caches, its value is replaced
+// with the result of onConflict, which is called with the cache's
+// value and other's value, as if by [Cache.Set]; so a merged value
+// which weighs more than the value it replaces may evict entries.
+// Otherwise, entries already resident in the cache are never
+// evicted by the merge. If onConflict is nil,
+// the cache's value is retained.
+// Expiration deadlines of imported entries are retained.
+func (c *Cache[Key, Value]) Merge(other *Cache[Key, Value], onConflict func(a, b Value) Value) {
+	if other == c || other.clock.Len() == 0 {
+		return
+	}
+	var conflicts, imports []*page[Key, Value]
+	for page := range other.residents() {
+		if other.expired(page.Name) {
+			continue
+		}
+		if mine, ok := c.index.get(page.Name); ok && mine.Resident {
+			if onConflict != nil {
+				conflicts = append(conflicts, page)
+			}
+		} else {
+			imports = append(imports, page)
+		}
+	}
+	for _, page := range conflicts {
+		if mine, ok := c.index.get(page.Name); ok && mine.Resident {
+			merged := onConflict(c.decoded(mine.Value), other.decoded(page.Value))
+			c.set(page.Name, merged, 0)
+		}
+	}
+	var (
+		free   = c.capacity - c.Len()
+		weight = c.maxWeight - c.weight // With [WithMaxWeight].
+	)
+	imports = imports[:min(free, len(imports))]
+	if c.weigh != nil {
+		for i, page := range imports {
+			weight -= c.weigh(page.Name, c.encoded(other.decoded(page.Value)))
+			if weight < 0 {
+				imports = imports[:i]
+				break
+			}
+		}
+	}
+	// Least recently used first, to retain relative recency.
+	for i := len(imports) - 1; i >= 0; i-- {
+		page := imports[i]
+		c.Set(page.Name, other.decoded(page.Value))
+		if deadline, ok := other.expiry.deadline(page.Name); ok {
+			c.expiry.schedule(page.Name, c.now().UnixNano(), deadline)
+		}
+	}
+}
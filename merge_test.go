@@ -0,0 +1,136 @@
+package clockpro_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestMerge(t *testing.T) {
+	t.Run("conflicts", mergeConflicts)
+	t.Run("hot first", mergeHotFirst)
+	t.Run("expired", mergeExpired)
+	t.Run("weight", mergeWeight)
+}
+
+func mergeConflicts(t *testing.T) {
+	t.Parallel()
+	const capacity = 4
+	var (
+		cache = newMergeCache(t, capacity)
+		other = newMergeCache(t, capacity)
+		sum   = func(a, b int) int { return a + b }
+	)
+	cache.Set(1, 1)
+	other.Set(1, 10)
+	other.Set(2, 20)
+	cache.Merge(other, sum)
+	checkGet(t, cache, 1, 11, "merged conflict")
+	checkGet(t, cache, 2, 20, "merged import")
+	cache.Merge(other, nil)
+	checkGet(t, cache, 1, 11, "merged without conflict handler")
+}
+
+// mergeExpired expects expired entries not to be imported,
+// and merged values to be stored as if by Set,
+// replacing the expiry of the cache's value.
+func mergeExpired(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 4
+		ttl      = time.Minute
+	)
+	var (
+		cache, clock      = newExpiringCache(t, capacity)
+		other, otherClock = newExpiringCache(t, capacity)
+		sum               = func(a, b int) int { return a + b }
+	)
+	cache.SetWithTTL(1, 1, ttl)
+	other.Set(1, 10)
+	other.SetWithTTL(2, 20, ttl)
+	otherClock.advance(ttl)
+	cache.Merge(other, sum)
+	mustMiss(t, cache, 2, "importing an expired entry")
+	clock.advance(ttl)
+	checkGet(t, cache, 1, 11, "merged conflict after the expiry of the cache's value")
+}
+
+// mergeWeight expects entries to be imported while they
+// fit within the maximum weight, and merged values which
+// weigh more to evict others as if by Set.
+func mergeWeight(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity  = 8
+		maxWeight = 10
+	)
+	newCache := func() *clockpro.Cache[int, int] {
+		cache, err := clockpro.New(capacity,
+			clockpro.WithMaxWeight(func(_, value int) int { return value }, maxWeight),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cache
+	}
+	var (
+		cache = newCache()
+		other = newCache()
+	)
+	cache.Set(1, 4)
+	other.Set(2, 4)
+	other.Set(3, 4)
+	cache.Merge(other, nil)
+	if got := cache.Len(); got != 2 {
+		t.Errorf(
+			"unexpected entries after merging"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			got, 2,
+		)
+	}
+	checkGet(t, cache, 1, 4, "resident entry")
+	other.Set(1, 8)
+	cache.Merge(other, func(_, b int) int { return b })
+	checkGet(t, cache, 1, 8, "merged conflict")
+	if got := cache.Weight(); got > maxWeight {
+		t.Errorf(
+			"weight exceeds its maximum after merging"+
+				"\n\tgot: %d"+
+				"\n\twant: <=%d",
+			got, maxWeight,
+		)
+	}
+}
+
+func mergeHotFirst(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 2
+		key      = 5
+	)
+	var (
+		cache = newMergeCache(t, capacity)
+		other = newMergeCache(t, capacity)
+	)
+	addIncrementingInts(other, capacity)
+	other.Set(3, 3) // Evicts 2 (cold).
+	other.Set(2, 2) // Resurrects 2 (hot), demoting 1.
+	cache.Set(key, key)
+	cache.Merge(other, nil)
+	keysMatch(t, cache, []int{key, 2}, "merging into a cache with one free page")
+	if got := slices.Collect(other.HotKeys()); !slices.Equal(got, []int{2}) {
+		t.Fatalf("expected 2 to be hot in the source cache, got: %v", got)
+	}
+}
+
+func newMergeCache(tb testing.TB, capacity int) *clockpro.Cache[int, int] {
+	tb.Helper()
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return cache
+}
@@ -0,0 +1,251 @@
+package clockpro
+
+import (
+	"encoding/gob"
+	"errors"
+	"io"
+	"maps"
+	"slices"
+)
+
+type (
+	// metaEntry is the saved metadata of a page.
+	metaEntry[Key comparable] struct {
+		Key   Key
+		Class PageClass
+	}
+	// restoredKeys holds the keys restored by [Cache.LoadMeta]
+	// whose values have not been fetched yet.
+	restoredKeys[Key comparable, Value any] struct {
+		fetch func(Key) (Value, error)
+		hot   map[Key]bool // The saved class of each key.
+		// order holds the keys to fetch, hot keys first,
+		// most recently inserted first within each class.
+		// Keys which were fetched or forgotten are skipped;
+		// the first key is always yet to be fetched.
+		order []Key
+	}
+)
+
+// SaveMeta writes the key and class of every page within the clock,
+// in the order of [Cache.Pages], to w with [encoding/gob].
+// Values are not written, so that the access history of the cache
+// can be restored by [Cache.LoadMeta] even if its values
+// cannot be serialized. Keys restored by LoadMeta
+// whose values have not been fetched yet are written
+// with their saved class, before every page.
+// Keys must be encodable by gob.
+// Saving does not count as an access.
+func (c *Cache[Key, Value]) SaveMeta(w io.Writer) error {
+	entries := make([]metaEntry[Key], 0, c.index.len())
+	if restored := c.restored; restored != nil {
+		for _, key := range slices.Backward(restored.order) {
+			hot, ok := restored.hot[key]
+			if !ok {
+				continue
+			}
+			class := ClassCold
+			if hot {
+				class = ClassHot
+			}
+			entries = append(entries, metaEntry[Key]{Key: key, Class: class})
+		}
+	}
+	for key, info := range c.Pages() {
+		entries = append(entries, metaEntry[Key]{Key: key, Class: info.Class})
+	}
+	return gob.NewEncoder(w).Encode(entries)
+}
+
+// LoadMeta replaces the contents of the cache with
+// the metadata written by [Cache.SaveMeta].
+// Keys which were test pages are restored as test pages.
+// Values are fetched lazily: the first [Cache.Get]
+// (or [Cache.Load]) of a key which was resident
+// calls fetch for its value, and stores it with
+// the class it was saved with, as if it was never evicted.
+// [Cache.FetchRestored] fetches the values of
+// restored keys ahead of their first access, hot keys first.
+// A restored key is no longer fetched once it has been
+// set, deleted, or fetched unsuccessfully.
+// fetch is called while the cache is in use,
+// and must not use the cache itself.
+// If the metadata cannot be decoded,
+// the cache is not modified.
+func (c *Cache[Key, Value]) LoadMeta(r io.Reader, fetch func(Key) (Value, error)) error {
+	var entries []metaEntry[Key]
+	if err := gob.NewDecoder(r).Decode(&entries); err != nil {
+		return err
+	}
+	c.Purge(nil)
+	restored := &restoredKeys[Key, Value]{
+		fetch: fetch,
+		hot:   make(map[Key]bool),
+	}
+	for _, class := range []PageClass{ClassHot, ClassCold} {
+		for _, entry := range slices.Backward(entries) {
+			if entry.Class != class {
+				continue
+			}
+			if _, ok := restored.hot[entry.Key]; ok {
+				continue // Duplicated.
+			}
+			restored.hot[entry.Key] = class == ClassHot
+			restored.order = append(restored.order, entry.Key)
+		}
+	}
+	for _, entry := range entries {
+		if entry.Class != ClassTest {
+			continue
+		}
+		if _, ok := c.index.get(entry.Key); ok {
+			continue // Duplicated.
+		}
+		if _, ok := restored.hot[entry.Key]; ok {
+			continue
+		}
+		if c.ghosts != nil {
+			c.ghosts.add(entry.Key)
+			continue
+		}
+		var zero Value
+		c.restorePage(entry.Key, zero, false, false)
+	}
+	c.pruneTest()
+	if len(restored.order) != 0 {
+		c.restored = restored
+	}
+	return nil
+}
+
+// FetchRestored calls the fetch function given to
+// [Cache.LoadMeta] for up to n keys whose values
+// have not been fetched since they were restored,
+// hot keys first, most recently inserted first within each class,
+// and returns how many values were stored.
+// Errors returned by fetch are joined,
+// each wrapped by [ErrFetchFailed].
+func (c *Cache[Key, Value]) FetchRestored(n int) (int, error) {
+	var (
+		stored int
+		errs   []error
+	)
+	for ; n > 0 && c.restored != nil; n-- {
+		var (
+			restored = c.restored
+			key      = restored.order[0]
+			hot      = restored.hot[key]
+		)
+		c.forgetRestored(key)
+		value, err := restored.fetch(key)
+		if err != nil {
+			errs = append(errs, fetchError(err))
+			continue
+		}
+		if c.restorePage(key, c.encoded(value), true, hot) {
+			stored++
+		}
+	}
+	return stored, errors.Join(errs...)
+}
+
+// fetchRestored fetches and stores the value of key
+// if it was restored by [Cache.LoadMeta] and
+// has not been fetched yet.
+func (c *Cache[Key, Value]) fetchRestored(key Key) (Value, bool) {
+	var (
+		zero     Value
+		restored = c.restored
+	)
+	hot, ok := restored.hot[key]
+	if !ok {
+		return zero, false
+	}
+	c.forgetRestored(key)
+	value, err := restored.fetch(key)
+	if err != nil ||
+		!c.restorePage(key, c.encoded(value), true, hot) {
+		return zero, false
+	}
+	return value, true
+}
+
+// forgetRestored stops the value of key from being fetched
+// by [Cache.fetchRestored], and drops the restored keys
+// once none are left to fetch.
+func (c *Cache[Key, Value]) forgetRestored(key Key) {
+	restored := c.restored
+	if restored == nil {
+		return
+	}
+	delete(restored.hot, key)
+	if len(restored.hot) == 0 {
+		c.restored = nil
+		return
+	}
+	for len(restored.order) != 0 {
+		if _, ok := restored.hot[restored.order[0]]; ok {
+			break
+		}
+		restored.order = restored.order[1:]
+	}
+}
+
+func (r *restoredKeys[Key, Value]) clone() *restoredKeys[Key, Value] {
+	if r == nil {
+		return nil
+	}
+	return &restoredKeys[Key, Value]{
+		fetch: r.fetch,
+		hot:   maps.Clone(r.hot),
+		order: slices.Clone(r.order),
+	}
+}
+
+// restorePage adds a page to the clock without adapting,
+// as a hot page if hot is true and the hot target allows it.
+// A resident page replaces the test page of key, if any,
+// and evicts another page if the cache is full.
+// restorePage reports whether the page was added,
+// which it is not if its value exceeds the maximum weight.
+func (c *Cache[Key, Value]) restorePage(key Key, value Value, resident, hot bool) bool {
+	if resident {
+		if c.overweight(key, value) {
+			return false
+		}
+		if test, ok := c.index.get(key); ok {
+			c.remove(test)
+		}
+		if c.Len() >= c.capacity {
+			c.EvictN(1)
+		}
+	}
+	page := c.newPage(metadata[Key]{
+		Name:     key,
+		Resident: resident,
+		LIR:      resident && hot && c.hotCount < c.hotTarget,
+		Stacked:  true,
+	}, value)
+	c.touch(page)
+	c.addToClock(page)
+	switch {
+	case page.LIR:
+		c.hotCount++
+	case resident:
+		if c.cold == nil {
+			c.cold = page
+		}
+		c.coldCount++
+	default:
+		if c.test == nil {
+			c.test = page
+		}
+		c.testCount++
+		return true
+	}
+	c.hooks.inserted(key, value)
+	c.becameResident(page)
+	c.stored(key)
+	c.trimWeight()
+	return true
+}
@@ -0,0 +1,336 @@
+package clockpro_test
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestMeta(t *testing.T) {
+	t.Run("restore", metaRestore)
+	t.Run("fetch failed", metaFetchFailed)
+	t.Run("corrupt", metaCorrupt)
+	t.Run("lazy", metaLazy)
+	t.Run("forget", metaForget)
+	t.Run("weight", metaWeight)
+	t.Run("resave", metaResave)
+}
+
+type metaPage struct {
+	key   int
+	class clockpro.PageClass
+}
+
+// savedMeta returns the metadata of the
+// "resurrect" sequence of [TestDump].
+func savedMeta(t *testing.T) []byte {
+	t.Helper()
+	const capacity = 4
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fill(cache, 0, capacity+2)
+	cache.Set(capacity-1, capacity-1)
+	var buffer bytes.Buffer
+	if err := cache.SaveMeta(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+func loadMeta(t *testing.T, saved []byte, fetch func(int) (int, error)) (*clockpro.Cache[int, int], []metaPage, error) {
+	t.Helper()
+	cache, err := clockpro.New[int, int](4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Set(-1, -1) // Replaced by the load.
+	err = cache.LoadMeta(bytes.NewReader(saved), fetch)
+	return cache, pagesOf(t, cache), err
+}
+
+func metaRestore(t *testing.T) {
+	t.Parallel()
+	var (
+		fetched []int
+		fetch   = func(key int) (int, error) {
+			fetched = append(fetched, key)
+			return key, nil
+		}
+		cache, _, err = loadMeta(t, savedMeta(t), fetch)
+		want          = []metaPage{
+			{4, clockpro.ClassTest},
+			{5, clockpro.ClassTest},
+			{3, clockpro.ClassHot},
+			{2, clockpro.ClassHot},
+			{1, clockpro.ClassCold},
+			{0, clockpro.ClassCold},
+		}
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fetched) != 0 {
+		t.Errorf("values were fetched by the load: %v", fetched)
+	}
+	if stored, err := cache.FetchRestored(len(want)); err != nil || stored != 4 {
+		t.Fatalf(
+			"unexpected fetch result"+
+				"\n\tgot: %d, %v"+
+				"\n\twant: %d, %v",
+			stored, err, 4, nil,
+		)
+	}
+	if got := pagesOf(t, cache); !slices.Equal(got, want) {
+		t.Errorf(
+			"unexpected pages"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			got, want,
+		)
+	}
+	if want := []int{3, 2, 1, 0}; !slices.Equal(fetched, want) {
+		t.Errorf(
+			"unexpected fetch order"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			fetched, want,
+		)
+	}
+	for _, key := range []int{0, 1, 2, 3} {
+		if value, ok := cache.Get(key); !ok || value != key {
+			t.Errorf("key %d was not restored", key)
+		}
+	}
+}
+
+func metaFetchFailed(t *testing.T) {
+	t.Parallel()
+	var (
+		failure = errors.New("fetch failed")
+		fetch   = func(key int) (int, error) {
+			if key == 3 {
+				return 0, failure
+			}
+			return key, nil
+		}
+		cache, _, err = loadMeta(t, savedMeta(t), fetch)
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := cache.FetchRestored(4)
+	if stored != 3 {
+		t.Errorf(
+			"unexpected count of stored values"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			stored, 3,
+		)
+	}
+	for _, want := range []error{clockpro.ErrFetchFailed, failure} {
+		if !errors.Is(err, want) {
+			t.Errorf(
+				"expected error to match"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				err, want,
+			)
+		}
+	}
+	if _, ok := cache.Get(3); ok {
+		t.Error("failed key was fetched again")
+	}
+}
+
+func metaCorrupt(t *testing.T) {
+	t.Parallel()
+	cache, err := clockpro.New[int, int](4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Set(1, 1)
+	fetch := func(key int) (int, error) { return key, nil }
+	if err := cache.LoadMeta(strings.NewReader("corrupt"), fetch); err == nil {
+		t.Error("expected corrupt metadata to be rejected")
+	}
+	if _, ok := cache.Get(1); !ok {
+		t.Error("cache was modified by a failed load")
+	}
+}
+
+func metaLazy(t *testing.T) {
+	t.Parallel()
+	var (
+		fetched []int
+		fetch   = func(key int) (int, error) {
+			fetched = append(fetched, key)
+			return key, nil
+		}
+		cache, got, err = loadMeta(t, savedMeta(t), fetch)
+		want            = []metaPage{
+			{4, clockpro.ClassTest},
+			{5, clockpro.ClassTest},
+		}
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf(
+			"unexpected pages"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			got, want,
+		)
+	}
+	if outcome := cache.SetReport(5, 5); outcome != clockpro.SetResurrected {
+		t.Errorf(
+			"test page was not restored"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			outcome, clockpro.SetResurrected,
+		)
+	}
+	for range 2 {
+		if got := mustGet(t, cache, 2); got != 2 {
+			t.Errorf("unexpected value for key 2: %d", got)
+		}
+	}
+	value, hit, err := cache.LoadReport(0, func() (int, error) {
+		t.Error("load fetcher was called for a restored key")
+		return 0, nil
+	})
+	if err != nil || !hit || value != 0 {
+		t.Errorf(
+			"restored key was not loaded"+
+				"\n\tgot: %d, %t, %v"+
+				"\n\twant: %d, %t, %v",
+			value, hit, err, 0, true, nil,
+		)
+	}
+	mustMiss(t, cache, 4, "test page")
+	if want := []int{2, 0}; !slices.Equal(fetched, want) {
+		t.Errorf(
+			"unexpected fetches"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			fetched, want,
+		)
+	}
+	want = []metaPage{
+		{4, clockpro.ClassTest},
+		{5, clockpro.ClassHot},
+		{2, clockpro.ClassHot},
+		{0, clockpro.ClassCold},
+	}
+	if got := pagesOf(t, cache); !slices.Equal(got, want) {
+		t.Errorf(
+			"restored keys were not stored with their class"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			got, want,
+		)
+	}
+}
+
+func metaForget(t *testing.T) {
+	t.Parallel()
+	fetch := func(key int) (int, error) {
+		t.Errorf("forgotten key %d was fetched", key)
+		return key, nil
+	}
+	cache, _, err := loadMeta(t, savedMeta(t), fetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Delete(3)
+	mustMiss(t, cache, 3, "delete")
+	cache.Set(2, 20)
+	cache.Delete(2)
+	mustMiss(t, cache, 2, "delete after set")
+	cache.Purge(nil)
+	mustMiss(t, cache, 1, "purge")
+}
+
+func metaWeight(t *testing.T) {
+	t.Parallel()
+	const maxWeight = 2
+	cache, err := clockpro.New(4,
+		clockpro.WithMaxWeight(func(int, int) int { return 1 }, maxWeight),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fetch := func(key int) (int, error) { return key, nil }
+	if err := cache.LoadMeta(bytes.NewReader(savedMeta(t)), fetch); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.FetchRestored(4); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.CheckInvariants(); err != nil {
+		t.Fatal(err)
+	}
+	if got := cache.Weight(); got > maxWeight {
+		t.Errorf(
+			"restored values exceed the maximum weight"+
+				"\n\tgot: %d"+
+				"\n\twant: <= %d",
+			got, maxWeight,
+		)
+	}
+}
+
+func metaResave(t *testing.T) {
+	t.Parallel()
+	fetch := func(key int) (int, error) { return key, nil }
+	cache, _, err := loadMeta(t, savedMeta(t), fetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustGet(t, cache, 3)
+	var buffer bytes.Buffer
+	if err := cache.SaveMeta(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	resaved, _, err := loadMeta(t, buffer.Bytes(), fetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.FetchRestored(4); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := resaved.FetchRestored(4); err != nil {
+		t.Fatal(err)
+	}
+	var (
+		got  = pagesOf(t, resaved)
+		want = pagesOf(t, cache)
+	)
+	if !slices.Equal(got, want) {
+		t.Errorf(
+			"unfetched keys were not saved"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			got, want,
+		)
+	}
+}
+
+func pagesOf(t *testing.T, cache *clockpro.Cache[int, int]) []metaPage {
+	t.Helper()
+	if err := cache.CheckInvariants(); err != nil {
+		t.Fatal(err)
+	}
+	var pages []metaPage
+	for key, info := range cache.Pages() {
+		pages = append(pages, metaPage{key, info.Class})
+	}
+	return pages
+}
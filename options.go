@@ -0,0 +1,71 @@
+package clockpro
+
+import "time"
+
+type (
+	// Option configures a [Cache] during [New].
+	Option[Key comparable, Value any]   func(*settings[Key, Value]) error
+	settings[Key comparable, Value any] struct {
+		hooks              Hooks[Key, Value]
+		adaptationRecorder func(AdaptationSample)
+		timeSource         func() time.Time
+		maxIdle            time.Duration
+		recording          *Recording[Key]
+		reuse              *reuseSampler[Key]
+		doorkeeper         *doorkeeper[Key]
+		victims            *victimSelection[Key, Value]
+		releaser           *releaser[Key, Value]
+		evictions          *evictionChannel[Key, Value]
+		journal            *Journal[Key]
+		shifts             *shiftDetector
+		residency          residency[Key, Value]
+		profileName        string
+		loadSpan           LoadSpan[Key]
+		latencies          *latencies
+		retries            *retryPolicy
+		shardHash          func(Key) uint64
+		indexHash          func(Key) uint64
+		tracer             func(Trace[Key])
+		faults             func(FaultPoint, Key) error
+		assertionHandler   func(error)
+		finalizer          func(Key, Value)
+		weigh              func(Key, Value) int
+		encode, decode     func(Value) Value
+		equal              func(Value, Value) bool
+		scanThreshold      int
+		secondChances      int
+		writeBuffer        int
+		maxWeight          int
+		adaptationStep     int // See [WithAdaptationRateLimit].
+		adaptationWindow   int
+		coldRatios         *[2]float64
+		softWatermark      float64
+		hotFraction        float64
+		trackAges,
+		countHits,
+		assertions,
+		sampling,
+		retainValues,
+		ghostSketch,
+		limitChances bool
+	}
+)
+
+func applyOptions[Key comparable, Value any](options []Option[Key, Value]) (settings[Key, Value], error) {
+	var set settings[Key, Value]
+	for _, apply := range options {
+		if err := apply(&set); err != nil {
+			return settings[Key, Value]{}, err
+		}
+	}
+	return set, nil
+}
+
+// WithHooks subscribes hooks to the cache's policy transitions.
+// Nil functions within hooks are ignored.
+func WithHooks[Key comparable, Value any](hooks Hooks[Key, Value]) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		set.hooks = hooks
+		return nil
+	}
+}
@@ -0,0 +1,129 @@
+package clockpro
+
+import (
+	"iter"
+	"strconv"
+)
+
+type (
+	// PageClass identifies the class of a page.
+	PageClass uint8
+	// PageInfo describes a page tracked by the cache,
+	// including the pages of evicted keys.
+	PageInfo struct {
+		EntryInfo
+		Class PageClass
+		// Position is the index of the page within the clock,
+		// from the least recently inserted page.
+		Position int
+		// distances are indexed by [Hand] - 1.
+		distances [HandTest]int
+	}
+)
+
+const (
+	// ClassHot pages are resident (LIR) pages.
+	ClassHot PageClass = iota + 1
+	// ClassCold pages are resident (HIR) pages.
+	ClassCold
+	// ClassTest pages are nonresident (HIR) pages,
+	// retained after eviction during their test period.
+	ClassTest
+)
+
+// Pages returns an iterator over every page within the clock,
+// from the least to the most recently inserted,
+// which is the order that the hands move in.
+// Iterating does not count as an access.
+// If yield modifies the cache, iteration stops after it returns,
+// since the order of the remaining pages is undefined.
+func (c *Cache[Key, Value]) Pages() iter.Seq2[Key, PageInfo] {
+	return c.pages(false)
+}
+
+// PagesBack is like [Cache.Pages], but iterates
+// from the most to the least recently inserted page,
+// such that the oldest pages may be reached last.
+// Positions are counted from the least recently
+// inserted page, as with [Cache.Pages].
+func (c *Cache[Key, Value]) PagesBack() iter.Seq2[Key, PageInfo] {
+	return c.pages(true)
+}
+
+func (c *Cache[Key, Value]) pages(backward bool) iter.Seq2[Key, PageInfo] {
+	return func(yield func(Key, PageInfo) bool) {
+		var (
+			hands         = [HandTest]*page[Key, Value]{c.hot, c.cold, c.test}
+			handPositions = [HandTest]int{-1, -1, -1}
+			length        int
+		)
+		for page := range c.clock.All() {
+			for i, hand := range hands {
+				if hand == page {
+					handPositions[i] = length
+				}
+			}
+			length++
+		}
+		var (
+			position, step = 0, 1
+			pages          = c.clock.All()
+			modifications  = c.modifications
+		)
+		if backward {
+			position, step = length-1, -1
+			pages = c.clock.Backward()
+		}
+		for page := range pages {
+			info := PageInfo{
+				EntryInfo: c.infoOf(page),
+				Class:     classOf(page),
+				Position:  position,
+			}
+			for i, handPosition := range handPositions {
+				info.distances[i] = -1
+				if handPosition != -1 {
+					info.distances[i] = (position - handPosition + length) % length
+				}
+			}
+			if !yield(page.Name, info) || c.modifications != modifications {
+				return
+			}
+			position += step
+		}
+	}
+}
+
+// Distance returns the amount of pages that hand
+// must pass before it reaches the page, or -1
+// if the hand does not point to any page.
+func (info PageInfo) Distance(hand Hand) int {
+	if hand < HandHot || hand > HandTest {
+		return -1
+	}
+	return info.distances[hand-1]
+}
+
+func classOf[Key comparable, Value any](page *page[Key, Value]) PageClass {
+	switch {
+	case page.LIR:
+		return ClassHot
+	case page.Resident:
+		return ClassCold
+	default:
+		return ClassTest
+	}
+}
+
+func (class PageClass) String() string {
+	switch class {
+	case ClassHot:
+		return "hot"
+	case ClassCold:
+		return "cold"
+	case ClassTest:
+		return "test"
+	default:
+		return "PageClass(" + strconv.Itoa(int(class)) + ")"
+	}
+}
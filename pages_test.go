@@ -0,0 +1,140 @@
+package clockpro_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestPages(t *testing.T) {
+	t.Run("resurrect", pagesResurrect)
+	t.Run("back", pagesBack)
+	t.Run("modified", pagesModified)
+	t.Run("class string", pageClassString)
+}
+
+// pagesResurrect compares pages against
+// the "resurrect" sequence of [TestDump].
+func pagesResurrect(t *testing.T) {
+	t.Parallel()
+	const capacity = 4
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fill(cache, 0, capacity+2)
+	cache.Set(capacity-1, capacity-1)
+	type page struct {
+		key      int
+		class    clockpro.PageClass
+		demoted  bool
+		position int
+		hot,
+		cold,
+		test int
+	}
+	var (
+		got  []page
+		want = []page{
+			{2, clockpro.ClassHot, false, 0, 0, 2, 5},
+			{4, clockpro.ClassTest, false, 1, 1, 3, 0},
+			{5, clockpro.ClassTest, false, 2, 2, 4, 1},
+			{3, clockpro.ClassHot, false, 3, 3, 5, 2},
+			{0, clockpro.ClassCold, true, 4, 4, 0, 3},
+			{1, clockpro.ClassCold, true, 5, 5, 1, 4},
+		}
+	)
+	for key, info := range cache.Pages() {
+		got = append(got, page{
+			key, info.Class, info.Demoted, info.Position,
+			info.Distance(clockpro.HandHot),
+			info.Distance(clockpro.HandCold),
+			info.Distance(clockpro.HandTest),
+		})
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf(
+			"unexpected pages"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			got, want,
+		)
+	}
+}
+
+func pagesBack(t *testing.T) {
+	t.Parallel()
+	const capacity = 4
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fill(cache, 0, capacity+2)
+	cache.Set(capacity-1, capacity-1)
+	type page struct {
+		key, position,
+		cold int
+	}
+	var forward, backward []page
+	for key, info := range cache.Pages() {
+		forward = append(forward, page{key, info.Position, info.Distance(clockpro.HandCold)})
+	}
+	for key, info := range cache.PagesBack() {
+		backward = append(backward, page{key, info.Position, info.Distance(clockpro.HandCold)})
+	}
+	slices.Reverse(backward)
+	if !slices.Equal(backward, forward) {
+		t.Errorf(
+			"backward pages differ from reversed forward pages"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			backward, forward,
+		)
+	}
+}
+
+func pagesModified(t *testing.T) {
+	t.Parallel()
+	const capacity = 4
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fill(cache, 0, capacity+2)
+	var yielded int
+	for key := range cache.Pages() {
+		yielded++
+		cache.Delete(key)
+	}
+	if yielded != 1 {
+		t.Errorf(
+			"iteration continued after modification"+
+				"\n\tgot: %d pages"+
+				"\n\twant: 1 page",
+			yielded,
+		)
+	}
+	if err := cache.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+}
+
+func pageClassString(t *testing.T) {
+	t.Parallel()
+	for class, want := range map[clockpro.PageClass]string{
+		clockpro.ClassHot:  "hot",
+		clockpro.ClassCold: "cold",
+		clockpro.ClassTest: "test",
+		0:                  "PageClass(0)",
+	} {
+		if got := class.String(); got != want {
+			t.Errorf(
+				"unexpected string"+
+					"\n\tgot: %s"+
+					"\n\twant: %s",
+				got, want,
+			)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package clockpro
+
+import (
+	"context"
+	"fmt"
+	"hash/maphash"
+	"runtime/pprof"
+	"strconv"
+	"time"
+)
+
+const (
+	// ProfileLabelCache is the profiler label
+	// which holds the name given to [WithProfileLabels].
+	ProfileLabelCache = "clockpro.cache"
+	// ProfileLabelKeyBucket is the profiler label
+	// which holds the bucket of a fetched key's hash.
+	ProfileLabelKeyBucket = "clockpro.key_bucket"
+	// profileKeyBuckets is the amount of buckets that
+	// fetched keys are labeled with, such that the key
+	// space responsible for misses may be identified,
+	// without the cardinality of the keys themselves.
+	profileKeyBuckets = 64
+)
+
+// profileSeed is shared by every cache, so that
+// keys are labeled with the same bucket by each.
+var profileSeed = maphash.MakeSeed()
+
+// WithProfileLabels labels the goroutine which calls
+// the fetch function of Load with the cache's name
+// and the bucket of the key's hash, via [pprof.Do],
+// so that CPU profiles of miss storms attribute the
+// time spent fetching to the cache and its key space.
+// Labels of the context given to LoadContext are retained,
+// but Load does not receive a context, so the labels of
+// the calling goroutine are replaced during its fetch.
+func WithProfileLabels[Key comparable, Value any](name string) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if name == "" {
+			return fmt.Errorf(
+				"%w: profile label name must not be empty",
+				ErrInvalidOption,
+			)
+		}
+		set.profileName = name
+		return nil
+	}
+}
+
+// fetch calls fetch for key, with profiler labels
+// if the cache was constructed with [WithProfileLabels],
+// retrying it if it was constructed with [WithRetries],
+// injecting faults if it was constructed with [WithFaultInjection],
+// and times it if it was constructed with [WithLatencies].
+func (set *settings[Key, Value]) fetch(ctx context.Context, key Key, fetch fetcher[Value]) (value Value, err error) {
+	if set.latencies != nil {
+		defer set.latencies.fetch.record(time.Now())
+	}
+	fetch = set.faultyFetch(key, fetch)
+	if set.profileName == "" {
+		return retry(ctx, set.retries, fetch)
+	}
+	bucket := maphash.Comparable(profileSeed, key) % profileKeyBuckets
+	labels := pprof.Labels(
+		ProfileLabelCache, set.profileName,
+		ProfileLabelKeyBucket, strconv.FormatUint(bucket, 10),
+	)
+	pprof.Do(ctx, labels, func(ctx context.Context) {
+		value, err = retry(ctx, set.retries, fetch)
+	})
+	return value, err
+}
@@ -0,0 +1,312 @@
+package clockpro_test
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"testing"
+	"testing/quick"
+
+	"github.com/djdv/go-clockpro"
+	"github.com/djdv/go-clockpro/sieve"
+)
+
+// TestProperties applies random sequences of operations
+// and compares the cache against a model of its residents
+// after each operation.
+func TestProperties(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		options []clockpro.Option[int, int]
+	}{
+		{"default", nil},
+		{"second chances", []clockpro.Option[int, int]{
+			clockpro.WithSecondChances[int, int](2),
+		}},
+		{"scan detection", []clockpro.Option[int, int]{
+			clockpro.WithScanDetection[int, int](4),
+		}},
+		{"target bounds", []clockpro.Option[int, int]{
+			clockpro.WithColdTargetBounds[int, int](0.25, 0.75),
+		}},
+		{"sampling", []clockpro.Option[int, int]{
+			clockpro.WithSampling[int, int](),
+		}},
+		{"integer index", []clockpro.Option[int, int]{
+			clockpro.WithIntegerIndex[int, int](),
+		}},
+		{"assertions", []clockpro.Option[int, int]{
+			clockpro.WithAssertions[int, int](),
+			clockpro.WithSecondChances[int, int](1),
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			property := func(capacity uint8, ops []uint16) bool {
+				err := checkProperties(int(capacity%32)+clockpro.MinimumCapacity, ops, test.options)
+				if err != nil {
+					t.Log(err)
+				}
+				return err == nil
+			}
+			config := &quick.Config{
+				MaxCount: 128,
+				Rand:     newReproducibleRNG(),
+			}
+			if err := quick.Check(property, config); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// checkProperties decodes each op into a kind and key,
+// applies it to a new cache, and returns the first
+// property which does not hold afterwards.
+func checkProperties(capacity int, ops []uint16, options []clockpro.Option[int, int]) error {
+	const kinds = 8
+	cache, err := clockpro.New(capacity, options...)
+	if err != nil {
+		return err
+	}
+	var (
+		residents  = make(map[int]int)
+		upperBound = capacity * 3
+	)
+	for i, op := range ops {
+		var (
+			key   = int(op/kinds) % upperBound
+			value = i
+		)
+		switch kind := op % kinds; kind {
+		case 0, 1, 2:
+			got, ok := cache.Get(key)
+			want, resident := residents[key]
+			if ok != resident || got != want {
+				return fmt.Errorf(
+					"op %d: get %d"+
+						"\n\tgot: %d, %t"+
+						"\n\twant: %d, %t",
+					i, key, got, ok, want, resident,
+				)
+			}
+		case 3, 4:
+			evictedKey, _, evicted := cache.SetGetEvicted(key, value)
+			if evicted {
+				delete(residents, evictedKey)
+			}
+			residents[key] = value
+		case 5:
+			_, resident := residents[key]
+			if got := cache.Delete(key); got != resident {
+				return fmt.Errorf(
+					"op %d: delete %d"+
+						"\n\tgot: %t"+
+						"\n\twant: %t",
+					i, key, got, resident,
+				)
+			}
+			delete(residents, key)
+		case 6:
+			cache.Invalidate(key)
+			delete(residents, key)
+		case 7:
+			if key%2 == 0 {
+				cache.NewEpoch()
+				break
+			}
+			cache.EvictN(1)
+			keys := slices.Collect(cache.Keys())
+			maps.DeleteFunc(residents, func(key, _ int) bool {
+				return !slices.Contains(keys, key)
+			})
+		}
+		if err := checkModel(cache, capacity, residents); err != nil {
+			return fmt.Errorf("op %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// TestSieveProperties applies random sequences of operations
+// to a [sieve.Cache] and to a direct model of the SIEVE policy,
+// and compares their residents after each operation.
+func TestSieveProperties(t *testing.T) {
+	t.Parallel()
+	property := func(capacity uint8, ops []uint16) bool {
+		err := checkSieveProperties(int(capacity%32)+1, ops)
+		if err != nil {
+			t.Log(err)
+		}
+		return err == nil
+	}
+	config := &quick.Config{
+		MaxCount: 128,
+		Rand:     newReproducibleRNG(),
+	}
+	if err := quick.Check(property, config); err != nil {
+		t.Error(err)
+	}
+}
+
+// sieveModel keeps its keys in insertion order,
+// with the hand at an index of keys.
+type sieveModel struct {
+	keys     []int
+	values   map[int]int
+	visited  map[int]bool
+	hand     int
+	capacity int
+}
+
+func (m *sieveModel) get(key int) (int, bool) {
+	value, ok := m.values[key]
+	if ok {
+		m.visited[key] = true
+	}
+	return value, ok
+}
+
+func (m *sieveModel) set(key, value int) {
+	if _, ok := m.values[key]; ok {
+		m.values[key] = value
+		m.visited[key] = true
+		return
+	}
+	if len(m.keys) == m.capacity {
+		hand := m.hand
+		for m.visited[m.keys[hand]] {
+			m.visited[m.keys[hand]] = false
+			hand = (hand + 1) % len(m.keys)
+		}
+		m.hand = hand
+		m.delete(m.keys[hand])
+	}
+	m.keys = append(m.keys, key)
+	m.values[key] = value
+}
+
+func (m *sieveModel) delete(key int) bool {
+	i := slices.Index(m.keys, key)
+	if i < 0 {
+		return false
+	}
+	m.keys = slices.Delete(m.keys, i, i+1)
+	delete(m.values, key)
+	delete(m.visited, key)
+	if i < m.hand {
+		m.hand--
+	}
+	if m.hand == len(m.keys) {
+		m.hand = 0 // Wraps around to the oldest key.
+	}
+	return true
+}
+
+// checkSieveProperties decodes each op into a kind and key,
+// applies it to a new [sieve.Cache] and model, and returns
+// the first difference between them.
+func checkSieveProperties(capacity int, ops []uint16) error {
+	const kinds = 6
+	cache, err := sieve.New[int, int](capacity)
+	if err != nil {
+		return err
+	}
+	var (
+		model = &sieveModel{
+			values:   make(map[int]int),
+			visited:  make(map[int]bool),
+			capacity: capacity,
+		}
+		upperBound = capacity * 3
+	)
+	for i, op := range ops {
+		var (
+			key   = int(op/kinds) % upperBound
+			value = i
+		)
+		switch kind := op % kinds; kind {
+		case 0, 1, 2:
+			got, ok := cache.Get(key)
+			want, resident := model.get(key)
+			if ok != resident || got != want {
+				return fmt.Errorf(
+					"op %d: get %d"+
+						"\n\tgot: %d, %t"+
+						"\n\twant: %d, %t",
+					i, key, got, ok, want, resident,
+				)
+			}
+		case 3, 4:
+			cache.Set(key, value)
+			model.set(key, value)
+		case 5:
+			if got, want := cache.Delete(key), model.delete(key); got != want {
+				return fmt.Errorf(
+					"op %d: delete %d"+
+						"\n\tgot: %t"+
+						"\n\twant: %t",
+					i, key, got, want,
+				)
+			}
+		}
+		if got, want := cache.Len(), len(model.keys); got != want {
+			return fmt.Errorf(
+				"op %d: unexpected length"+
+					"\n\tgot: %d"+
+					"\n\twant: %d",
+				i, got, want,
+			)
+		}
+	}
+	// Get marks keys as visited,
+	// so residents are only compared last.
+	for _, key := range slices.Clone(model.keys) {
+		if got, ok := cache.Get(key); !ok || got != model.values[key] {
+			return fmt.Errorf(
+				"key %d differs from the model"+
+					"\n\tgot: %d, %t"+
+					"\n\twant: %d, true",
+				key, got, ok, model.values[key],
+			)
+		}
+	}
+	return nil
+}
+
+func checkModel(cache *clockpro.Cache[int, int], capacity int, residents map[int]int) error {
+	if err := cache.CheckInvariants(); err != nil {
+		return err
+	}
+	if length := cache.Len(); length > capacity || length != len(residents) {
+		return fmt.Errorf(
+			"unexpected length"+
+				"\n\tgot: %d"+
+				"\n\twant: %d (<=%d)",
+			length, len(residents), capacity,
+		)
+	}
+	var dump bytes.Buffer
+	if err := cache.Dump(&dump); err != nil {
+		return err
+	}
+	const header = 2
+	if pages := bytes.Count(dump.Bytes(), []byte{'\n'}) - header; pages > capacity*2 {
+		return fmt.Errorf(
+			"metadata exceeded limit"+
+				"\n\tgot: %d"+
+				"\n\twant: <=%d",
+			pages, capacity*2,
+		)
+	}
+	if keys := slices.Sorted(cache.Keys()); !slices.Equal(keys, slices.Sorted(maps.Keys(residents))) {
+		return fmt.Errorf(
+			"unexpected residents"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			keys, slices.Sorted(maps.Keys(residents)),
+		)
+	}
+	return nil
+}
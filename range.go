@@ -0,0 +1,47 @@
+package clockpro
+
+import "cmp"
+
+// DeleteRange removes the keys within [lo, hi] from any of
+// the cache types, including their test pages, like [Cache.Delete],
+// such as to invalidate the blocks of a truncated file.
+// The keys forgotten into the filter of [WithGhosts] are not removed.
+// DeleteRange takes time proportional to the amount of tracked keys,
+// and returns the amount of keys whose values were resident.
+func DeleteRange[Key cmp.Ordered](cache interface{ deleteWhere(func(Key) bool) int }, lo, hi Key) int {
+	return cache.deleteWhere(func(key Key) bool {
+		return key >= lo && key <= hi
+	})
+}
+
+// deleteWhere deletes the tracked keys which match,
+// and returns the amount which were resident.
+func (c *Cache[Key, Value]) deleteWhere(match func(Key) bool) int {
+	var matched []Key
+	for key := range c.tracked() {
+		if match(key) {
+			matched = append(matched, key)
+		}
+	}
+	var deleted int
+	for _, key := range matched {
+		if c.Delete(key) {
+			deleted++
+		}
+	}
+	return deleted
+}
+
+func (s *Synced[Key, Value]) deleteWhere(match func(Key) bool) int {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.deleteWhere(match)
+}
+
+func (sc *Sharded[Key, Value]) deleteWhere(match func(Key) bool) int {
+	var deleted int
+	for _, shard := range sc.shards {
+		deleted += shard.deleteWhere(match)
+	}
+	return deleted
+}
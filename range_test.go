@@ -0,0 +1,57 @@
+package clockpro_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestDeleteRange(t *testing.T) {
+	t.Parallel()
+	const capacity = 8
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addIncrementingInts(cache, capacity+2) // Evicts 8 and 9.
+	tracked := func() (keys []int) {
+		for key := range cache.TrackedKeys() {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		return keys
+	}
+	if got := clockpro.DeleteRange(cache, 1, capacity); got != capacity-1 {
+		t.Errorf(
+			"unexpected amount of resident keys deleted"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			got, capacity-1,
+		)
+	}
+	if got, want := tracked(), []int{9, 10}; !slices.Equal(got, want) {
+		t.Errorf(
+			"unexpected keys tracked after deletion"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			got, want,
+		)
+	}
+	synced, err := clockpro.NewSynced[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key := range capacity {
+		synced.Set(key, key)
+	}
+	if got := clockpro.DeleteRange(synced, 2, capacity); got != capacity-2 {
+		t.Errorf(
+			"unexpected amount of resident keys deleted"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			got, capacity-2,
+		)
+	}
+	mustMiss(t, synced, 2, "deleted range")
+}
@@ -0,0 +1,116 @@
+// Package readercache caches the contents of an [io.ReaderAt],
+// such as an [os.File], in fixed-size chunks which are
+// replaced by the CLOCK-Pro+ policy.
+package readercache
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/djdv/go-clockpro"
+)
+
+// ReaderAt reads from an [io.ReaderAt] through a cache
+// of chunks, whose memory is bounded by the total length
+// of the cached chunks, as weighed by [clockpro.WithMaxWeight].
+// Chunks are read whole, at offsets which are multiples
+// of the chunk size. ReaderAt is safe for concurrent use
+// if the underlying reader is.
+type ReaderAt struct {
+	reader    io.ReaderAt
+	chunks    *clockpro.Synced[int64, []byte]
+	chunkSize int64
+}
+
+// New constructs a [ReaderAt] which caches up to
+// size bytes of reader, in chunks of chunkSize bytes.
+// Size must hold at least [clockpro.MinimumCapacity] chunks.
+// The contents of reader must not change.
+func New(reader io.ReaderAt, size, chunkSize int) (*ReaderAt, error) {
+	if chunkSize < 1 {
+		return nil, fmt.Errorf(
+			"%w: chunk size must be >=1 but %d was requested",
+			clockpro.ErrInvalidSize, chunkSize,
+		)
+	}
+	var (
+		// The weight binds before the entries, unless every
+		// chunk is whole, as the last chunk may be shorter.
+		entries = size/chunkSize + 1
+		weigh   = func(_ int64, chunk []byte) int { return cap(chunk) }
+	)
+	if size/chunkSize < clockpro.MinimumCapacity {
+		return nil, fmt.Errorf(
+			"%w: size must hold >=%d chunks of %d bytes but %d bytes were requested",
+			clockpro.ErrInvalidCapacity, clockpro.MinimumCapacity, chunkSize, size,
+		)
+	}
+	chunks, err := clockpro.NewSynced(entries,
+		clockpro.WithIntegerIndex[int64, []byte](),
+		clockpro.WithMaxWeight(weigh, size),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &ReaderAt{
+		reader:    reader,
+		chunks:    chunks,
+		chunkSize: int64(chunkSize),
+	}, nil
+}
+
+// ReadAt implements [io.ReaderAt].
+// Errors of the underlying reader other than [io.EOF]
+// are wrapped by [clockpro.ErrFetchFailed].
+func (ra *ReaderAt) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, fmt.Errorf("readercache: negative offset %d", off)
+	}
+	for n < len(p) {
+		var (
+			position = off + int64(n)
+			index    = position / ra.chunkSize
+			chunk    []byte
+		)
+		if chunk, err = ra.chunk(index); err != nil {
+			return n, err
+		}
+		start := position - index*ra.chunkSize
+		if start >= int64(len(chunk)) {
+			return n, io.EOF
+		}
+		n += copy(p[n:], chunk[start:])
+		if int64(len(chunk)) < ra.chunkSize && n < len(p) {
+			return n, io.EOF // Chunk ends at the end of the reader.
+		}
+	}
+	return n, nil
+}
+
+// chunk returns the chunk at index, which is shorter
+// than the chunk size if it is the last chunk.
+func (ra *ReaderAt) chunk(index int64) ([]byte, error) {
+	chunk, err := ra.chunks.Load(index, func() ([]byte, error) {
+		chunk := make([]byte, ra.chunkSize)
+		n, err := ra.reader.ReadAt(chunk, index*ra.chunkSize)
+		if n != 0 && errors.Is(err, io.EOF) {
+			err = nil
+		}
+		if n < len(chunk) { // Only weigh what was read.
+			return bytes.Clone(chunk[:n]), err
+		}
+		return chunk, err
+	})
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+	return chunk, err
+}
+
+// Size returns the amount of bytes held by the cached chunks.
+func (ra *ReaderAt) Size() int { return ra.chunks.Weight() }
+
+// Stats is like [clockpro.Cache.Stats].
+func (ra *ReaderAt) Stats() clockpro.Stats { return ra.chunks.Stats() }
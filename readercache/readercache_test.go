@@ -0,0 +1,174 @@
+package readercache_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+	"github.com/djdv/go-clockpro/readercache"
+)
+
+const (
+	capacity  = 4
+	chunkSize = 64
+	memory    = capacity * chunkSize
+	size      = chunkSize*capacity*2 + chunkSize/2
+)
+
+// countingReader counts reads of the underlying reader.
+type countingReader struct {
+	io.ReaderAt
+	reads atomic.Int64
+}
+
+func (cr *countingReader) ReadAt(p []byte, off int64) (int, error) {
+	cr.reads.Add(1)
+	return cr.ReaderAt.ReadAt(p, off)
+}
+
+func newReader(t *testing.T) (*readercache.ReaderAt, *countingReader, []byte) {
+	t.Helper()
+	data := make([]byte, size)
+	rand.New(rand.NewSource(1)).Read(data)
+	counting := &countingReader{ReaderAt: bytes.NewReader(data)}
+	reader, err := readercache.New(counting, memory, chunkSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reader, counting, data
+}
+
+func TestReaderAt(t *testing.T) {
+	t.Run("invalid", invalid)
+	t.Run("contents", contents)
+	t.Run("cached", cached)
+	t.Run("concurrent", concurrent)
+	t.Run("bounded", bounded)
+}
+
+// bounded reads the whole reader repeatedly, including
+// its short last chunk, and checks the cached size.
+func bounded(t *testing.T) {
+	t.Parallel()
+	reader, _, data := newReader(t)
+	buffer := make([]byte, len(data))
+	for range 4 {
+		if _, err := reader.ReadAt(buffer, 0); err != nil {
+			t.Fatal(err)
+		}
+		if cached := reader.Size(); cached > memory || cached == 0 {
+			t.Errorf(
+				"unexpected size of cached chunks"+
+					"\n\tgot: %d"+
+					"\n\twant: (0,%d]",
+				cached, memory,
+			)
+		}
+	}
+}
+
+func invalid(t *testing.T) {
+	t.Parallel()
+	reader := bytes.NewReader(nil)
+	for _, test := range []struct {
+		memory, chunkSize int
+		want              error
+	}{
+		{memory, 0, clockpro.ErrInvalidSize},
+		{0, chunkSize, clockpro.ErrInvalidCapacity},
+		{chunkSize, chunkSize, clockpro.ErrInvalidCapacity},
+	} {
+		_, err := readercache.New(reader, test.memory, test.chunkSize)
+		if !errors.Is(err, test.want) {
+			t.Errorf(
+				"expected error to match"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				err, test.want,
+			)
+		}
+	}
+}
+
+// contents compares reads of several lengths at many offsets
+// against the underlying reader, including past its end.
+func contents(t *testing.T) {
+	t.Parallel()
+	reader, _, data := newReader(t)
+	direct := bytes.NewReader(data)
+	for _, length := range []int{1, chunkSize - 1, chunkSize, chunkSize*2 + 1, size} {
+		for off := int64(0); off <= size+1; off += chunkSize / 4 {
+			var (
+				got, want         = make([]byte, length), make([]byte, length)
+				gotN, gotErr      = reader.ReadAt(got, off)
+				wantN, wantErr    = direct.ReadAt(want, off)
+				gotEOF, wantEOF   = gotErr == io.EOF, wantErr == io.EOF
+				unexpectedFailure = (gotErr != nil && !gotEOF)
+			)
+			if unexpectedFailure || gotN != wantN || gotEOF != wantEOF ||
+				!bytes.Equal(got[:gotN], want[:wantN]) {
+				t.Fatalf(
+					"unexpected read of %d bytes at %d"+
+						"\n\tgot: %d, %v"+
+						"\n\twant: %d, %v",
+					length, off, gotN, gotErr, wantN, wantErr,
+				)
+			}
+		}
+	}
+}
+
+func cached(t *testing.T) {
+	t.Parallel()
+	reader, counting, _ := newReader(t)
+	buffer := make([]byte, chunkSize/2)
+	for range 4 {
+		for off := int64(0); off < chunkSize*capacity/2; off += int64(len(buffer)) {
+			if _, err := reader.ReadAt(buffer, off); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if reads, want := counting.reads.Load(), int64(capacity/2); reads != want {
+		t.Errorf(
+			"unexpected reads of the underlying reader"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			reads, want,
+		)
+	}
+}
+
+func concurrent(t *testing.T) {
+	t.Parallel()
+	const workers = 4
+	var (
+		reader, _, data = newReader(t)
+		wg              sync.WaitGroup
+	)
+	for worker := range workers {
+		wg.Go(func() {
+			var (
+				rng    = rand.New(rand.NewSource(int64(worker)))
+				buffer = make([]byte, chunkSize)
+			)
+			for range 256 {
+				off := rng.Int63n(size - chunkSize)
+				if _, err := reader.ReadAt(buffer, off); err != nil {
+					t.Error(err)
+					return
+				}
+				if !bytes.Equal(buffer, data[off:off+chunkSize]) {
+					t.Errorf("unexpected contents at %d", off)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
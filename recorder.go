@@ -0,0 +1,141 @@
+package clockpro
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"iter"
+	"strconv"
+	"time"
+)
+
+type (
+	// AdaptationSample is a snapshot of the cache's adaptive state,
+	// taken whenever the cold target is adjusted.
+	AdaptationSample struct {
+		Time       time.Time `json:"time"`
+		ColdTarget int       `json:"coldTarget"`
+		HotTarget  int       `json:"hotTarget"`
+		Demotions  int       `json:"demotions"`
+		TestPages  int       `json:"testPages"`
+	}
+	// AdaptationHistory retains the most recent [AdaptationSample]s
+	// in a fixed size ring buffer.
+	// Its [AdaptationHistory.Record] method may be
+	// passed to [WithAdaptationRecorder].
+	// Constructed by [NewAdaptationHistory].
+	AdaptationHistory struct {
+		samples []AdaptationSample
+		next    int
+		full    bool
+	}
+)
+
+// WithAdaptationRecorder calls record with a sample
+// of the cache's state every time its targets adapt.
+func WithAdaptationRecorder[Key comparable, Value any](record func(AdaptationSample)) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		set.adaptationRecorder = record
+		return nil
+	}
+}
+
+// NewAdaptationHistory creates an [AdaptationHistory]
+// that retains at most size samples.
+func NewAdaptationHistory(size int) (*AdaptationHistory, error) {
+	if size < 1 {
+		return nil, minHistoryError(size)
+	}
+	return &AdaptationHistory{
+		samples: make([]AdaptationSample, size),
+	}, nil
+}
+
+// Record stores the sample, overwriting
+// the oldest sample if the history is full.
+func (ah *AdaptationHistory) Record(sample AdaptationSample) {
+	ah.samples[ah.next] = sample
+	if ah.next++; ah.next == len(ah.samples) {
+		ah.next = 0
+		ah.full = true
+	}
+}
+
+// Len returns the number of retained samples.
+func (ah *AdaptationHistory) Len() int {
+	if ah.full {
+		return len(ah.samples)
+	}
+	return ah.next
+}
+
+// Samples returns an iterator over the retained samples,
+// from oldest to newest.
+func (ah *AdaptationHistory) Samples() iter.Seq[AdaptationSample] {
+	return func(yield func(AdaptationSample) bool) {
+		if ah.full {
+			for _, sample := range ah.samples[ah.next:] {
+				if !yield(sample) {
+					return
+				}
+			}
+		}
+		for _, sample := range ah.samples[:ah.next] {
+			if !yield(sample) {
+				return
+			}
+		}
+	}
+}
+
+// WriteCSV writes the retained samples to w
+// as CSV records, preceded by a header record.
+func (ah *AdaptationHistory) WriteCSV(w io.Writer) error {
+	var (
+		writer = csv.NewWriter(w)
+		header = []string{
+			"time", "coldTarget", "hotTarget",
+			"demotions", "testPages",
+		}
+	)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for sample := range ah.Samples() {
+		record := []string{
+			sample.Time.Format(time.RFC3339Nano),
+			strconv.Itoa(sample.ColdTarget),
+			strconv.Itoa(sample.HotTarget),
+			strconv.Itoa(sample.Demotions),
+			strconv.Itoa(sample.TestPages),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJSON writes the retained samples to w as a JSON array.
+func (ah *AdaptationHistory) WriteJSON(w io.Writer) error {
+	samples := make([]AdaptationSample, 0, ah.Len())
+	for sample := range ah.Samples() {
+		samples = append(samples, sample)
+	}
+	return json.NewEncoder(w).Encode(samples)
+}
+
+func (c *Cache[_, _]) recordAdaptation() {
+	record := c.adaptationRecorder
+	if record == nil {
+		return
+	}
+	record(AdaptationSample{
+		Time:       time.Now(),
+		ColdTarget: c.coldTarget,
+		HotTarget:  c.hotTarget,
+		Demotions:  c.demotions,
+		TestPages:  c.testCount,
+	})
+}
@@ -0,0 +1,123 @@
+package clockpro_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestAdaptationHistory(t *testing.T) {
+	t.Run("invalid size", invalidHistorySize)
+	t.Run("wraps", historyWraps)
+	t.Run("records adaptation", historyRecords)
+}
+
+func invalidHistorySize(t *testing.T) {
+	t.Parallel()
+	_, err := clockpro.NewAdaptationHistory(0)
+	if !errors.Is(err, clockpro.ErrInvalidSize) {
+		t.Errorf(
+			"expected error to match"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			err, clockpro.ErrInvalidSize)
+	}
+}
+
+func historyWraps(t *testing.T) {
+	t.Parallel()
+	const size = 3
+	history := newHistory(t, size)
+	for target := range size * 2 {
+		history.Record(clockpro.AdaptationSample{ColdTarget: target})
+	}
+	if got := history.Len(); got != size {
+		t.Fatalf(
+			"expected history length to be bound"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			got, size)
+	}
+	want := size
+	for sample := range history.Samples() {
+		if got := sample.ColdTarget; got != want {
+			t.Fatalf(
+				"expected samples to be ordered oldest to newest"+
+					"\n\tgot: %d"+
+					"\n\twant: %d",
+				got, want)
+		}
+		want++
+	}
+}
+
+func historyRecords(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 2
+		size     = 8
+	)
+	var (
+		history    = newHistory(t, size)
+		record     = clockpro.WithAdaptationRecorder[int, int](history.Record)
+		cache, err = clockpro.New(capacity, record)
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addIncrementingInts(cache, capacity)
+	cache.Set(3, 3) // Evicts 2 (cold).
+	cache.Set(2, 2) // Ghost hit adapts targets.
+	if history.Len() == 0 {
+		t.Fatal("expected ghost hit to record an adaptation sample")
+	}
+	t.Run("csv", func(t *testing.T) {
+		var buffer bytes.Buffer
+		if err := history.WriteCSV(&buffer); err != nil {
+			t.Fatal(err)
+		}
+		records, err := csv.NewReader(&buffer).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := len(records), history.Len()+1; got != want {
+			t.Errorf(
+				"expected header and one record per sample"+
+					"\n\tgot: %d"+
+					"\n\twant: %d",
+				got, want)
+		}
+	})
+	t.Run("json", func(t *testing.T) {
+		var (
+			buffer  bytes.Buffer
+			samples []clockpro.AdaptationSample
+		)
+		if err := history.WriteJSON(&buffer); err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(buffer.Bytes(), &samples); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := len(samples), history.Len(); got != want {
+			t.Errorf(
+				"expected one element per sample"+
+					"\n\tgot: %d"+
+					"\n\twant: %d",
+				got, want)
+		}
+	})
+}
+
+func newHistory(tb testing.TB, size int) *clockpro.AdaptationHistory {
+	tb.Helper()
+	history, err := clockpro.NewAdaptationHistory(size)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return history
+}
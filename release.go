@@ -0,0 +1,162 @@
+package clockpro
+
+import (
+	"fmt"
+	"sync"
+)
+
+type (
+	// releaser dispatches released values to a pool
+	// of workers. See [WithAsyncRelease].
+	releaser[Key comparable, Value any] struct {
+		release  func(Key, Value, EntryInfo)
+		finalize func(Key, Value)
+		queue    chan releasedEntry[Key, Value]
+		idle     sync.Cond
+		mu       sync.Mutex
+		workers,
+		active,
+		pending int
+	}
+	releasedEntry[Key comparable, Value any] struct {
+		key      Key
+		value    Value
+		info     EntryInfo
+		finalize bool
+	}
+)
+
+// WithAsyncRelease calls [Hooks.OnRelease], and the finalizer
+// given to [WithFinalizer] or [WithClose], from up to workers
+// goroutines, rather than from the method which released the value,
+// so that slow hooks do not delay modifications of the cache,
+// nor hold the locks of concurrent caches.
+// Up to queue values may wait for a worker;
+// releasing more waits until a worker is available.
+// Workers are started as needed, and exit while the queue is empty.
+// Hooks may be called concurrently and out of order;
+// see [Cache.Flush] to wait for them to return.
+func WithAsyncRelease[Key comparable, Value any](workers, queue int) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if workers < 1 {
+			return fmt.Errorf(
+				"%w: release workers must be >=1 but %d was provided",
+				ErrInvalidOption, workers,
+			)
+		}
+		if queue < 1 {
+			return fmt.Errorf(
+				"%w: release queue size must be >=1 but %d was provided",
+				ErrInvalidOption, queue,
+			)
+		}
+		rl := &releaser[Key, Value]{
+			queue:   make(chan releasedEntry[Key, Value], queue),
+			workers: workers,
+		}
+		rl.idle.L = &rl.mu
+		set.releaser = rl
+		return nil
+	}
+}
+
+// WithRetainedValues retains the values of evicted pages
+// until their test pages are removed or resurrected,
+// rather than clearing them so that they may be collected.
+// This avoids clearing values which hold no references,
+// or which are recycled by [Hooks.OnRelease] anyway.
+func WithRetainedValues[Key comparable, Value any]() Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		set.retainValues = true
+		return nil
+	}
+}
+
+// Flush waits for the functions dispatched by [WithAsyncRelease]
+// to return. Unlike other methods, Flush may be called concurrently
+// with modifications of the cache, which may release more values.
+func (c *Cache[_, _]) Flush() {
+	if rl := c.releaser; rl != nil {
+		rl.wait()
+	}
+}
+
+// release calls the release hook for the value of page,
+// and its finalizer unless the value is returned to the caller,
+// or dispatches them to the releaser.
+func (c *Cache[Key, Value]) release(page *page[Key, Value], returned bool) {
+	var (
+		notify   = c.hooks.OnRelease != nil
+		finalize = c.finalizer != nil && !returned
+	)
+	if !notify && !finalize {
+		return
+	}
+	entry := releasedEntry[Key, Value]{
+		key:      page.Name,
+		value:    page.Value,
+		finalize: finalize,
+	}
+	if notify {
+		entry.info = c.infoOf(page)
+	}
+	if rl := c.releaser; rl != nil {
+		rl.dispatch(entry)
+		return
+	}
+	c.hooks.released(entry.key, entry.value, entry.info)
+	if finalize {
+		c.finalizer(entry.key, entry.value)
+	}
+}
+
+func (rl *releaser[Key, Value]) dispatch(entry releasedEntry[Key, Value]) {
+	rl.mu.Lock()
+	rl.pending++
+	rl.mu.Unlock()
+	rl.queue <- entry
+	rl.mu.Lock()
+	if rl.active < rl.workers {
+		rl.active++
+		go rl.work()
+	}
+	rl.mu.Unlock()
+}
+
+func (rl *releaser[_, _]) work() {
+	for {
+		select {
+		case entry := <-rl.queue:
+			if rl.release != nil {
+				rl.release(entry.key, entry.value, entry.info)
+			}
+			if entry.finalize {
+				rl.finalize(entry.key, entry.value)
+			}
+			rl.mu.Lock()
+			if rl.pending--; rl.pending == 0 {
+				rl.idle.Broadcast()
+			}
+			rl.mu.Unlock()
+		default:
+			rl.mu.Lock()
+			// Entries queued before the lock was acquired
+			// are received by this worker, and entries
+			// queued after are received by a new one.
+			if len(rl.queue) == 0 {
+				rl.active--
+				rl.mu.Unlock()
+				return
+			}
+			rl.mu.Unlock()
+		}
+	}
+}
+
+func (rl *releaser[_, _]) wait() {
+	rl.mu.Lock()
+	for rl.pending != 0 {
+		rl.idle.Wait()
+	}
+	rl.mu.Unlock()
+}
@@ -0,0 +1,169 @@
+package clockpro_test
+
+import (
+	"errors"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+	"weak"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestAsyncRelease(t *testing.T) {
+	t.Run("invalid", asyncReleaseInvalid)
+	t.Run("nonblocking", asyncReleaseNonblocking)
+	t.Run("synced", asyncReleaseSynced)
+}
+
+// TestRetainedValues checks whether the value
+// of an evicted page may be collected.
+func TestRetainedValues(t *testing.T) {
+	const capacity = 2
+	// Large enough to not share an allocation.
+	type value [4]int
+	for _, test := range []struct {
+		name     string
+		options  []clockpro.Option[int, *value]
+		retained bool
+	}{
+		{"cleared", nil, false},
+		{"retained", []clockpro.Option[int, *value]{
+			clockpro.WithRetainedValues[int, *value](),
+		}, true},
+	} {
+		cache, err := clockpro.New(capacity, test.options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		evicted := new(value)
+		pointer := weak.Make(evicted)
+		cache.Set(1, new(value))
+		cache.Set(2, evicted)
+		cache.Set(3, new(value)) // Evicts 2.
+		evicted = nil
+		runtime.GC()
+		if retained := pointer.Value() != nil; retained != test.retained {
+			t.Errorf(
+				"%s: unexpected retention of evicted value"+
+					"\n\tgot: %t"+
+					"\n\twant: %t",
+				test.name, retained, test.retained,
+			)
+		}
+		runtime.KeepAlive(cache)
+	}
+}
+
+func asyncReleaseInvalid(t *testing.T) {
+	t.Parallel()
+	for _, option := range []clockpro.Option[int, int]{
+		clockpro.WithAsyncRelease[int, int](0, 1),
+		clockpro.WithAsyncRelease[int, int](1, 0),
+	} {
+		if _, err := clockpro.New(2, option); !errors.Is(err, clockpro.ErrInvalidOption) {
+			t.Errorf(
+				"expected error to match"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				err, clockpro.ErrInvalidOption)
+		}
+	}
+}
+
+// asyncReleaseNonblocking blocks the release hook,
+// and checks that evictions up to the size of
+// the queue do not wait for it.
+func asyncReleaseNonblocking(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 4
+		workers  = 2
+		queue    = capacity
+	)
+	var (
+		released []int
+		mu       sync.Mutex
+		unblock  = make(chan struct{})
+		hooks    = clockpro.Hooks[int, int]{
+			OnRelease: func(key, _ int, _ clockpro.EntryInfo) {
+				<-unblock
+				mu.Lock()
+				released = append(released, key)
+				mu.Unlock()
+			},
+		}
+		cache, err = clockpro.New(capacity,
+			clockpro.WithHooks(hooks),
+			clockpro.WithAsyncRelease[int, int](workers, queue),
+		)
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each insertion beyond capacity evicts a page.
+	// Workers take the first evictions from the queue.
+	const evictions = workers + queue
+	addIncrementingInts(cache, capacity+evictions)
+	close(unblock)
+	cache.Flush()
+	if len(released) != evictions {
+		t.Errorf(
+			"unexpected release count"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			len(released), evictions,
+		)
+	}
+	for _, key := range released {
+		if slices.Contains(slices.Collect(cache.Keys()), key) {
+			t.Errorf("resident key %d was released", key)
+		}
+	}
+}
+
+func asyncReleaseSynced(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 16
+		keys     = capacity * 8
+	)
+	var (
+		inserted, released int
+		mu                 sync.Mutex
+		hooks              = clockpro.Hooks[int, int]{
+			OnInsert: func(int, int) { inserted++ }, // Called with the lock held.
+			OnRelease: func(int, int, clockpro.EntryInfo) {
+				mu.Lock()
+				released++
+				mu.Unlock()
+			},
+		}
+		cache = newSynced(t, capacity,
+			clockpro.WithHooks(hooks),
+			clockpro.WithAsyncRelease[int, int](4, 1),
+		)
+		wg sync.WaitGroup
+	)
+	for worker := range 4 {
+		wg.Go(func() {
+			for key := range keys {
+				cache.Set(key, worker)
+			}
+		})
+	}
+	wg.Wait()
+	cache.Flush()
+	// Every insert or update releases a value,
+	// except for those which remain resident.
+	updates := 4*keys - inserted
+	if want := inserted + updates - cache.Len(); released != want {
+		t.Errorf(
+			"unexpected release count"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			released, want,
+		)
+	}
+}
@@ -0,0 +1,239 @@
+package clockpro
+
+import (
+	"fmt"
+	"strconv"
+)
+
+type (
+	// OperationKind identifies an operation applied to a [Cache].
+	OperationKind uint8
+	// Operation is a single operation applied to a [Cache].
+	Operation[Key comparable] struct {
+		Key  Key
+		Kind OperationKind
+		// Cost is the cost provided to [Cache.SetWithCost],
+		// or 0 if none was provided.
+		Cost float64
+	}
+	// DecisionKind identifies an action taken by the replacement policy.
+	DecisionKind uint8
+	// Decision is a single action taken by the replacement policy,
+	// while processing the operation at index Operation
+	// of the [Recording.Operations].
+	Decision[Key comparable] struct {
+		Key       Key
+		Operation int
+		Kind      DecisionKind
+	}
+	// Recording is a log of the operations applied to a [Cache]
+	// and the decisions it made while applying them.
+	// Recordings can be serialized (e.g. with encoding/gob)
+	// and checked against other versions of the policy by [Replay].
+	Recording[Key comparable] struct {
+		Operations []Operation[Key]
+		Decisions  []Decision[Key]
+		Capacity   int
+	}
+)
+
+const (
+	// OperationGet is recorded for Get and the lookups of Load.
+	OperationGet OperationKind = iota + 1
+	// OperationSet is recorded for Set and the insertions of Load.
+	OperationSet
+	// OperationRemove is recorded when a page is removed
+	// from the cache, such as by [Cache.Delete].
+	OperationRemove
+	// OperationEvict is recorded for each page
+	// evicted by [Cache.EvictN].
+	OperationEvict
+	// OperationInvalidate is recorded for [Cache.Invalidate],
+	// and when a page expires.
+	OperationInvalidate
+	// OperationEpoch is recorded for [Cache.NewEpoch].
+	OperationEpoch
+)
+
+const (
+	// DecisionInsert marks a new page being added to the clock.
+	DecisionInsert DecisionKind = iota + 1
+	// DecisionPromote marks a cold page becoming hot.
+	DecisionPromote
+	// DecisionDemote marks a hot page becoming cold.
+	DecisionDemote
+	// DecisionEvict marks a cold page becoming a test page.
+	DecisionEvict
+	// DecisionResurrect marks a test page becoming resident.
+	DecisionResurrect
+	// DecisionForget marks a test page being removed.
+	DecisionForget
+	// DecisionClear marks a hand clearing a page's reference.
+	DecisionClear
+	// DecisionUnstack marks a cold page leaving the stack.
+	DecisionUnstack
+	// DecisionRestack marks a cold page re-entering the stack.
+	DecisionRestack
+)
+
+// WithRecording logs every operation and policy decision
+// of the cache to recording.
+func WithRecording[Key comparable, Value any](recording *Recording[Key]) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		set.recording = recording
+		return nil
+	}
+}
+
+// Replay applies the operations of recording to a new cache,
+// and returns an error if the new cache's decisions
+// differ from those in the recording.
+// Values are not recorded, and are not
+// considered by the replacement policy.
+func Replay[Key comparable](recording *Recording[Key]) error {
+	var (
+		replayed   = Recording[Key]{Capacity: recording.Capacity}
+		cache, err = New(
+			recording.Capacity,
+			WithRecording[Key, struct{}](&replayed),
+		)
+	)
+	if err != nil {
+		return err
+	}
+	for _, operation := range recording.Operations {
+		switch key := operation.Key; operation.Kind {
+		case OperationGet:
+			cache.Get(key)
+		case OperationSet:
+			if cost := operation.Cost; cost != 0 {
+				cache.SetWithCost(key, struct{}{}, cost)
+			} else {
+				cache.Set(key, struct{}{})
+			}
+		case OperationRemove:
+			if page, ok := cache.index.get(key); ok {
+				cache.remove(page)
+			}
+		case OperationEvict:
+			cache.EvictN(1)
+		case OperationInvalidate:
+			cache.Invalidate(key)
+		case OperationEpoch:
+			cache.NewEpoch()
+		default:
+			return fmt.Errorf(
+				"%w: unexpected operation kind: %d",
+				ErrReplayMismatch, operation.Kind,
+			)
+		}
+	}
+	return compareDecisions(recording.Decisions, replayed.Decisions)
+}
+
+func compareDecisions[Key comparable](want, got []Decision[Key]) error {
+	for i, wantDecision := range want {
+		if i == len(got) {
+			return fmt.Errorf(
+				"%w: decision %d: missing, want: %v",
+				ErrReplayMismatch, i, wantDecision,
+			)
+		}
+		if gotDecision := got[i]; gotDecision != wantDecision {
+			return fmt.Errorf(
+				"%w: decision %d: got: %v, want: %v",
+				ErrReplayMismatch, i, gotDecision, wantDecision,
+			)
+		}
+	}
+	if extra := len(got) - len(want); extra > 0 {
+		return fmt.Errorf(
+			"%w: %d decisions beyond recording, first: %v",
+			ErrReplayMismatch, extra, got[len(want)],
+		)
+	}
+	return nil
+}
+
+func (kind OperationKind) String() string {
+	switch kind {
+	case OperationGet:
+		return "get"
+	case OperationSet:
+		return "set"
+	case OperationRemove:
+		return "remove"
+	case OperationEvict:
+		return "evict"
+	case OperationInvalidate:
+		return "invalidate"
+	case OperationEpoch:
+		return "epoch"
+	default:
+		return "OperationKind(" + strconv.Itoa(int(kind)) + ")"
+	}
+}
+
+func (kind DecisionKind) String() string {
+	switch kind {
+	case DecisionInsert:
+		return "insert"
+	case DecisionPromote:
+		return "promote"
+	case DecisionDemote:
+		return "demote"
+	case DecisionEvict:
+		return "evict"
+	case DecisionResurrect:
+		return "resurrect"
+	case DecisionForget:
+		return "forget"
+	case DecisionClear:
+		return "clear"
+	case DecisionUnstack:
+		return "unstack"
+	case DecisionRestack:
+		return "restack"
+	default:
+		return "DecisionKind(" + strconv.Itoa(int(kind)) + ")"
+	}
+}
+
+func (decision Decision[Key]) String() string {
+	return fmt.Sprintf(
+		"operation %d: %s %v",
+		decision.Operation, decision.Kind, decision.Key,
+	)
+}
+
+func (c *Cache[Key, _]) recordOperation(kind OperationKind, key Key) {
+	recording := c.recording
+	if recording == nil {
+		return
+	}
+	recording.Operations = append(recording.Operations, Operation[Key]{
+		Key:  key,
+		Kind: kind,
+	})
+}
+
+// recordCost sets the cost of the last recorded operation.
+func (c *Cache[_, _]) recordCost(cost float64) {
+	if c.recording == nil || cost == 0 {
+		return
+	}
+	operations := c.recording.Operations
+	operations[len(operations)-1].Cost = cost
+}
+
+func (c *Cache[Key, _]) recordDecision(kind DecisionKind, key Key) {
+	recording := c.recording
+	if recording == nil {
+		return
+	}
+	recording.Decisions = append(recording.Decisions, Decision[Key]{
+		Key:       key,
+		Operation: len(recording.Operations) - 1,
+		Kind:      kind,
+	})
+}
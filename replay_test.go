@@ -0,0 +1,110 @@
+package clockpro_test
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestReplay(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity   = 16
+		universe   = capacity * 4
+		operations = 1 << 12
+		maxTTL     = int64(time.Second)
+		maxCost    = 10
+	)
+	var (
+		recording clockpro.Recording[int]
+		clock     = &fakeClock{now: time.Unix(0, 0)}
+		rng       = rand.New(rand.NewSource(rngSeed))
+		options   = []clockpro.Option[int, int]{
+			clockpro.WithRecording[int, int](&recording),
+			clockpro.WithTimeSource[int, int](clock.Now),
+		}
+		cache, err = clockpro.New(capacity, options...)
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range operations {
+		key := rng.Intn(universe)
+		switch rng.Intn(10) {
+		case 0:
+			cache.Get(key)
+		case 1:
+			cache.Set(key, key)
+		case 2:
+			cache.SetWithTTL(key, key, time.Duration(rng.Int63n(maxTTL)))
+		case 3:
+			clock.advance(time.Duration(rng.Int63n(maxTTL / 8)))
+		case 4:
+			cache.Delete(key)
+		case 5:
+			cache.EvictN(rng.Intn(4))
+		case 6:
+			if rng.Intn(capacity) == 0 {
+				cache.Purge(nil)
+			}
+		case 7:
+			cache.Invalidate(key)
+		case 8:
+			cache.SetWithCost(key, key, rng.Float64()*maxCost)
+		case 9:
+			if rng.Intn(capacity) == 0 {
+				cache.NewEpoch()
+			}
+		}
+		if err := cache.CheckInvariants(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(recording.Decisions) == 0 {
+		t.Fatal("expected decisions to be recorded")
+	}
+	t.Run("identical", func(t *testing.T) {
+		if err := clockpro.Replay(&recording); err != nil {
+			t.Error(err)
+		}
+	})
+	t.Run("transitions", func(t *testing.T) {
+		decisions := make(map[clockpro.DecisionKind]uint64)
+		for _, decision := range recording.Decisions {
+			decisions[decision.Kind]++
+		}
+		stats := cache.Stats()
+		for _, transition := range []struct {
+			kind  clockpro.DecisionKind
+			count uint64
+		}{
+			{clockpro.DecisionPromote, stats.Promotions},
+			{clockpro.DecisionDemote, stats.Demotions},
+			{clockpro.DecisionForget, stats.TestRemovals},
+			{clockpro.DecisionResurrect, stats.Resurrections},
+		} {
+			if want := decisions[transition.kind]; transition.count != want {
+				t.Errorf(
+					"%v: transitions do not match decisions"+
+						"\n\tgot: %d"+
+						"\n\twant: %d",
+					transition.kind, transition.count, want,
+				)
+			}
+		}
+	})
+	t.Run("mismatch", func(t *testing.T) {
+		tampered := recording
+		tampered.Decisions = tampered.Decisions[:len(tampered.Decisions)-1]
+		if err := clockpro.Replay(&tampered); !errors.Is(err, clockpro.ErrReplayMismatch) {
+			t.Errorf(
+				"expected error to match"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				err, clockpro.ErrReplayMismatch)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package clockpro
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// retryPolicy decides which failed fetches are retried,
+// and how long to wait before each retry. See [WithRetries].
+type retryPolicy struct {
+	retryable func(error) bool
+	backoff   time.Duration
+	retries   int
+}
+
+// WithRetries calls the fetch function of Load (and its variants)
+// again up to retries times, while it returns an error for which
+// retryable returns true, before the error is returned, so that
+// transient failures of a backend are not returned for every miss.
+// The first retry waits for backoff, and each retry thereafter
+// waits for twice as long as the previous one.
+// A nil retryable retries every error, except for the errors
+// of the context given to LoadContext. Waiting stops early,
+// without retrying, once that context is done.
+func WithRetries[Key comparable, Value any](retries int, backoff time.Duration, retryable func(error) bool) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if retries < 1 {
+			return fmt.Errorf(
+				"%w: retries must be >=1 but %d was provided",
+				ErrInvalidOption, retries,
+			)
+		}
+		if backoff < 0 {
+			return fmt.Errorf(
+				"%w: backoff must be >=0 but %v was provided",
+				ErrInvalidOption, backoff,
+			)
+		}
+		if retryable == nil {
+			retryable = func(err error) bool {
+				return !errors.Is(err, context.Canceled) &&
+					!errors.Is(err, context.DeadlineExceeded)
+			}
+		}
+		set.retries = &retryPolicy{
+			retryable: retryable,
+			backoff:   backoff,
+			retries:   retries,
+		}
+		return nil
+	}
+}
+
+// retry calls fetch, and retries it
+// as decided by the policy, if any.
+func retry[Value any](ctx context.Context, rp *retryPolicy, fetch fetcher[Value]) (Value, error) {
+	value, err := fetch.call(ctx)
+	if rp == nil {
+		return value, err
+	}
+	wait := rp.backoff
+	for retry := 0; retry < rp.retries && err != nil && rp.retryable(err); retry++ {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return value, err
+		case <-timer.C:
+		}
+		value, err = fetch.call(ctx)
+		wait *= 2
+	}
+	return value, err
+}
@@ -0,0 +1,154 @@
+package clockpro
+
+import (
+	"cmp"
+	"fmt"
+	"hash/maphash"
+	"math"
+	"slices"
+)
+
+// reuseSampler estimates the reuse distance of accesses,
+// (the number of distinct keys accessed between two
+// accesses of the same key) by tracking a spatially
+// sampled subset of keys, selected by their hash.
+// Keys whose reuse distance would exceed the window
+// are forgotten, bounding the keys which are tracked.
+type reuseSampler[Key comparable] struct {
+	seed      maphash.Seed
+	last      map[Key]int // Time of each key's last access.
+	tree      []int       // Fenwick tree of last access times.
+	threshold uint64
+	scale     float64
+	clock     int
+	window    uint64
+	limit     int // Sampled keys retained by compaction.
+}
+
+// WithReuseDistances records the reuse distances
+// of accesses in [Stats.ReuseDistances].
+// Only the accesses of a sampled subset of keys are tracked;
+// rate is the fraction of keys to sample, within (0,1].
+// Distances greater than window are not recorded, as if
+// the key had not been accessed before; the memory used
+// is proportional to window multiplied by rate.
+// To tune capacity, window should be at least
+// [CapacityTuning.Maximum].
+func WithReuseDistances[Key comparable, Value any](rate float64, window int) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if !(rate > 0 && rate <= 1) {
+			return fmt.Errorf(
+				"%w: reuse distance sample rate must be within (0,1] but %f was provided",
+				ErrInvalidOption, rate,
+			)
+		}
+		if window < 1 {
+			return fmt.Errorf(
+				"%w: reuse distance window must be positive but %d was provided",
+				ErrInvalidOption, window,
+			)
+		}
+		set.reuse = newReuseSampler[Key](rate, window)
+		return nil
+	}
+}
+
+func newReuseSampler[Key comparable](rate float64, window int) *reuseSampler[Key] {
+	threshold := uint64(math.MaxUint64)
+	if rate < 1 {
+		threshold = uint64(rate * math.MaxUint64)
+	}
+	return &reuseSampler[Key]{
+		seed:      maphash.MakeSeed(),
+		last:      make(map[Key]int),
+		threshold: threshold,
+		scale:     1 / rate,
+		window:    uint64(window),
+		limit:     int(math.Ceil(float64(window)*rate)) + 1,
+	}
+}
+
+// access records an access of key, and returns
+// its (scaled) reuse distance if key was sampled
+// and accessed previously.
+func (rs *reuseSampler[Key]) access(key Key) (uint64, bool) {
+	if maphash.Comparable(rs.seed, key) > rs.threshold {
+		return 0, false
+	}
+	if rs.clock+1 >= len(rs.tree) {
+		rs.compact()
+	}
+	rs.clock++
+	var (
+		now            = rs.clock
+		previous, seen = rs.last[key]
+		distance       uint64
+	)
+	if seen {
+		distinct := rs.prefix(now-1) - rs.prefix(previous)
+		distance = uint64(float64(distinct) * rs.scale)
+		rs.add(previous, -1)
+	}
+	rs.add(now, 1)
+	rs.last[key] = now
+	return distance, seen && distance <= rs.window
+}
+
+// compact forgets the least recently accessed keys
+// beyond the limit, renumbers the last access times
+// to be contiguous, and resizes the tree
+// to have room for as many future accesses.
+// Keys are forgotten once more distinct keys were
+// accessed since them than the window allows,
+// so their next distance would not be recorded.
+func (rs *reuseSampler[Key]) compact() {
+	type access struct {
+		key  Key
+		time int
+	}
+	const minimumSize = 64
+	accesses := make([]access, 0, len(rs.last))
+	for key, time := range rs.last {
+		accesses = append(accesses, access{key: key, time: time})
+	}
+	slices.SortFunc(accesses, func(a, b access) int {
+		return cmp.Compare(a.time, b.time)
+	})
+	if excess := len(accesses) - rs.limit; excess > 0 {
+		for _, access := range accesses[:excess] {
+			delete(rs.last, access.key)
+		}
+		accesses = accesses[excess:]
+	}
+	size := max(len(accesses)*2, minimumSize)
+	rs.tree = make([]int, size+1)
+	for i, access := range accesses {
+		time := i + 1
+		rs.last[access.key] = time
+		rs.add(time, 1)
+	}
+	rs.clock = len(accesses)
+}
+
+func (rs *reuseSampler[_]) add(index, delta int) {
+	for ; index < len(rs.tree); index += index & -index {
+		rs.tree[index] += delta
+	}
+}
+
+func (rs *reuseSampler[_]) prefix(index int) int {
+	var sum int
+	for ; index > 0; index -= index & -index {
+		sum += rs.tree[index]
+	}
+	return sum
+}
+
+func (c *Cache[Key, _]) sampleReuse(key Key) {
+	if c.reuse == nil {
+		return
+	}
+	if distance, reused := c.reuse.access(key); reused {
+		c.stats.total.reuseDistances.add(distance)
+	}
+}
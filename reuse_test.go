@@ -0,0 +1,103 @@
+package clockpro_test
+
+import (
+	"errors"
+	"math/bits"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestReuseDistances(t *testing.T) {
+	t.Run("invalid rate", invalidReuseRate)
+	t.Run("invalid window", invalidReuseWindow)
+	t.Run("loop", reuseLoop)
+	t.Run("window", reuseWindow)
+}
+
+func invalidReuseRate(t *testing.T) {
+	t.Parallel()
+	for _, rate := range []float64{-1, 0, 1.5} {
+		_, err := clockpro.New(clockpro.MinimumCapacity,
+			clockpro.WithReuseDistances[int, int](rate, clockpro.MinimumCapacity),
+		)
+		if !errors.Is(err, clockpro.ErrInvalidOption) {
+			t.Errorf(
+				"expected error to match for rate %f"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				rate, err, clockpro.ErrInvalidOption)
+		}
+	}
+}
+
+func invalidReuseWindow(t *testing.T) {
+	t.Parallel()
+	for _, window := range []int{-1, 0} {
+		_, err := clockpro.New(clockpro.MinimumCapacity,
+			clockpro.WithReuseDistances[int, int](1, window),
+		)
+		if !errors.Is(err, clockpro.ErrInvalidOption) {
+			t.Errorf(
+				"expected error to match for window %d"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				window, err, clockpro.ErrInvalidOption)
+		}
+	}
+}
+
+const reuseLoopSize = 100
+
+func reuseLoop(t *testing.T) {
+	t.Parallel()
+	// The distance of each reuse is exactly the window.
+	loopReuse(t, reuseLoopSize-1, true)
+}
+
+func reuseWindow(t *testing.T) {
+	t.Parallel()
+	loopReuse(t, reuseLoopSize-2, false)
+}
+
+// loopReuse accesses keys in a loop, and expects their
+// distances to be recorded if they are within the window.
+func loopReuse(t *testing.T, window int, recorded bool) {
+	const (
+		capacity = 16
+		loopSize = reuseLoopSize
+		loops    = 50 // Enough to compact the sampler repeatedly.
+		rate     = 1
+	)
+	cache, err := clockpro.New(capacity,
+		clockpro.WithReuseDistances[int, int](rate, window),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range loops {
+		for key := range loopSize {
+			if _, ok := cache.Get(key); !ok {
+				cache.Set(key, key)
+			}
+		}
+	}
+	var (
+		distances = cache.Stats().ReuseDistances
+		// Misses are followed by a Set of the same key,
+		// which has a distance of 0.
+		misses = cache.Stats().Misses
+		want   clockpro.Histogram
+	)
+	want.Buckets[0] = misses
+	if recorded {
+		want.Buckets[bits.Len(loopSize-1)] = loopSize * (loops - 1)
+	}
+	if distances != want {
+		t.Errorf(
+			"unexpected reuse distances"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			distances.Buckets, want.Buckets)
+	}
+}
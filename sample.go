@@ -0,0 +1,112 @@
+package clockpro
+
+import (
+	"math/rand/v2"
+
+	"github.com/djdv/go-clockpro/internal/list"
+)
+
+// residentSet holds the references of the resident pages
+// in a dense slice, so that they can be sampled in constant time.
+// Each page's Slot is its position in the slice, plus 1.
+type residentSet[Key comparable, Value any] struct {
+	clock *list.List[Key, Value]
+	pages []list.Ref
+}
+
+// WithSampling maintains the resident pages
+// in a form which lets [Cache.Sample] select keys
+// without iterating over the whole cache.
+func WithSampling[Key comparable, Value any]() Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		set.sampling = true
+		return nil
+	}
+}
+
+// Sample returns up to n distinct resident keys,
+// selected uniformly at random.
+// Expired pages may be sampled until they are evicted.
+// Unless the cache was constructed with [WithSampling],
+// Sample iterates over the whole cache.
+// Sampling does not count as an access.
+func (c *Cache[Key, Value]) Sample(n int) []Key {
+	n = min(n, c.Len())
+	if n <= 0 {
+		return nil
+	}
+	if c.sampled == nil {
+		return c.reservoir(n)
+	}
+	var (
+		keys     = make([]Key, 0, n)
+		pages    = c.sampled.pages
+		selected = make(map[int]struct{}, n)
+	)
+	// Floyd's algorithm selects n of the positions.
+	for last := len(pages) - n; last < len(pages); last++ {
+		position := rand.IntN(last + 1)
+		if _, ok := selected[position]; ok {
+			position = last
+		}
+		selected[position] = struct{}{}
+		keys = append(keys, c.clock.At(pages[position]).Name)
+	}
+	return keys
+}
+
+// reservoir samples n resident keys from the index.
+func (c *Cache[Key, Value]) reservoir(n int) []Key {
+	var (
+		keys = make([]Key, 0, n)
+		seen int
+	)
+	for key, page := range c.index.all() {
+		if !page.Resident {
+			continue
+		}
+		if seen++; len(keys) < n {
+			keys = append(keys, key)
+		} else if position := rand.IntN(seen); position < n {
+			keys[position] = key
+		}
+	}
+	return keys
+}
+
+func (rs *residentSet[Key, Value]) add(page *page[Key, Value]) {
+	if page.Slot != 0 {
+		return
+	}
+	rs.pages = append(rs.pages, page.Ref())
+	page.Slot = uint32(len(rs.pages))
+}
+
+func (rs *residentSet[Key, Value]) remove(page *page[Key, Value]) {
+	var (
+		position = page.Slot - 1
+		last     = rs.pages[len(rs.pages)-1]
+	)
+	rs.pages[position] = last
+	rs.clock.At(last).Slot = position + 1
+	rs.pages = rs.pages[:len(rs.pages)-1]
+	page.Slot = 0
+}
+
+func (rs *residentSet[Key, Value]) reset() {
+	rs.pages = rs.pages[:0]
+}
+
+// clone returns the set of the clone's pages,
+// which retain the slots of the originals.
+func (rs *residentSet[Key, Value]) clone(index *pageIndex[Key, Value]) *residentSet[Key, Value] {
+	clone := &residentSet[Key, Value]{
+		clock: index.clock,
+		pages: make([]list.Ref, len(rs.pages)),
+	}
+	for i, ref := range rs.pages {
+		page, _ := index.get(rs.clock.At(ref).Name)
+		clone.pages[i] = page.Ref()
+	}
+	return clone
+}
@@ -0,0 +1,134 @@
+package clockpro_test
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestSample(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		options []clockpro.Option[int, int]
+	}{
+		{"index", nil},
+		{"sampling", []clockpro.Option[int, int]{
+			clockpro.WithSampling[int, int](),
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			newSampled := func(tb testing.TB, capacity int) *clockpro.Cache[int, int] {
+				tb.Helper()
+				cache, err := clockpro.New(capacity, test.options...)
+				if err != nil {
+					tb.Fatal(err)
+				}
+				return cache
+			}
+			t.Run("distinct", func(t *testing.T) {
+				sampleDistinct(t, newSampled)
+			})
+			t.Run("uniform", func(t *testing.T) {
+				sampleUniform(t, newSampled)
+			})
+			t.Run("clone and purge", func(t *testing.T) {
+				sampleClonePurge(t, newSampled)
+			})
+		})
+	}
+}
+
+type newSampledFunc = func(testing.TB, int) *clockpro.Cache[int, int]
+
+func sampleDistinct(t *testing.T, newSampled newSampledFunc) {
+	const capacity = 16
+	cache := newSampled(t, capacity)
+	if keys := cache.Sample(1); len(keys) != 0 {
+		t.Errorf("sampled an empty cache: %v", keys)
+	}
+	addIncrementingInts(cache, capacity*2)
+	cache.Delete(capacity * 2)
+	for _, n := range []int{-1, 0, 1, capacity / 2, capacity, capacity * 2} {
+		checkSample(t, cache, n, fmt.Sprintf("sample of %d", n))
+	}
+}
+
+func sampleUniform(t *testing.T, newSampled newSampledFunc) {
+	const (
+		capacity  = 8
+		samples   = 1 << 14
+		n         = 2
+		tolerance = 0.1
+	)
+	cache := newSampled(t, capacity)
+	addIncrementingInts(cache, capacity)
+	counts := make(map[int]int, capacity)
+	for range samples {
+		for _, key := range cache.Sample(n) {
+			counts[key]++
+		}
+	}
+	want := float64(samples*n) / capacity
+	for key := 1; key <= capacity; key++ {
+		count := counts[key]
+		if deviation := math.Abs(float64(count)-want) / want; deviation > tolerance {
+			t.Errorf(
+				"key %d is not sampled uniformly"+
+					"\n\tgot: %d"+
+					"\n\twant: %.0f",
+				key, count, want,
+			)
+		}
+	}
+}
+
+func sampleClonePurge(t *testing.T, newSampled newSampledFunc) {
+	const capacity = 8
+	cache := newSampled(t, capacity)
+	addIncrementingInts(cache, capacity*2)
+	clone := cache.Clone(nil)
+	cache.Purge(nil)
+	if keys := cache.Sample(capacity); len(keys) != 0 {
+		t.Errorf("sampled a purged cache: %v", keys)
+	}
+	if err := clone.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+	checkSample(t, clone, capacity, "sample of clone")
+	addIncrementingInts(cache, capacity)
+	checkSample(t, cache, capacity, "sample after purge")
+}
+
+func checkSample(t *testing.T, cache *clockpro.Cache[int, int], n int, name string) {
+	t.Helper()
+	var (
+		keys      = cache.Sample(n)
+		want      = max(min(n, cache.Len()), 0)
+		unique    = make(map[int]struct{}, len(keys))
+		residents = slices.Collect(cache.Keys())
+	)
+	if len(keys) != want {
+		t.Errorf(
+			"%s: unexpected key count"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			name, len(keys), want,
+		)
+	}
+	for _, key := range keys {
+		if _, ok := unique[key]; ok {
+			t.Errorf("%s: key %d was sampled twice", name, key)
+		}
+		unique[key] = struct{}{}
+		if !slices.Contains(residents, key) {
+			t.Errorf("%s: key %d is not resident", name, key)
+		}
+	}
+	if err := cache.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,44 @@
+package clockpro
+
+import "fmt"
+
+// WithScanDetection treats runs of at least threshold
+// consecutive insertions of untracked keys as a scan.
+// While a scan is detected, new keys are inserted
+// as cold pages outside of the stack, so that they
+// are forgotten entirely when evicted, rather than
+// displacing test pages of keys which may be reused.
+// A scan ends when a tracked key is inserted.
+// Caches which detect scans cannot be checked by [Replay].
+func WithScanDetection[Key comparable, Value any](threshold int) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if threshold < 1 {
+			return fmt.Errorf(
+				"%w: scan threshold must be >=1 but %d was provided",
+				ErrInvalidOption, threshold,
+			)
+		}
+		set.scanThreshold = threshold
+		return nil
+	}
+}
+
+// observeInsertion should be called when a key is inserted,
+// with whether the key was tracked by the cache.
+func (c *Cache[_, _]) observeInsertion(tracked bool) {
+	if c.scanThreshold == 0 {
+		return
+	}
+	if tracked {
+		c.scanRun = 0
+	} else {
+		c.scanRun++
+	}
+}
+
+// scanning reports whether the recent
+// insertions are considered a scan.
+func (c *Cache[_, _]) scanning() bool {
+	return c.scanThreshold != 0 &&
+		c.scanRun >= c.scanThreshold
+}
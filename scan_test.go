@@ -0,0 +1,58 @@
+package clockpro_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestScanDetection(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 4
+		scanEnd  = capacity * 4
+	)
+	for _, test := range []struct {
+		threshold int
+		want      clockpro.SetOutcome
+	}{
+		{scanEnd * 2, clockpro.SetResurrected}, // Never detected.
+		{capacity, clockpro.SetInserted},
+	} {
+		t.Run(fmt.Sprintf("threshold %d", test.threshold), func(t *testing.T) {
+			t.Parallel()
+			cache, err := clockpro.New(capacity,
+				clockpro.WithScanDetection[int, int](test.threshold),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var lastEvicted int
+			for key := 1; key <= scanEnd; key++ {
+				if evicted, _, ok := cache.SetGetEvicted(key, key); ok {
+					lastEvicted = evicted
+				}
+			}
+			if got := cache.SetReport(lastEvicted, lastEvicted); got != test.want {
+				t.Errorf(
+					"unexpected outcome for key evicted by a scan"+
+						"\n\tgot: %v"+
+						"\n\twant: %v",
+					got, test.want)
+			}
+		})
+	}
+	t.Run("invalid threshold", func(t *testing.T) {
+		t.Parallel()
+		_, err := clockpro.New(capacity, clockpro.WithScanDetection[int, int](0))
+		if !errors.Is(err, clockpro.ErrInvalidOption) {
+			t.Errorf(
+				"expected error to match"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				err, clockpro.ErrInvalidOption)
+		}
+	})
+}
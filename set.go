@@ -0,0 +1,147 @@
+package clockpro
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+type (
+	// SetOutcome identifies how [Cache.SetReport] stored a value.
+	SetOutcome uint8
+	// setResult describes the side effects of storing a value.
+	setResult[Key comparable, Value any] struct {
+		evictedKey   Key
+		evictedValue Value
+		evicted      bool
+		outcome      SetOutcome
+	}
+)
+
+const (
+	// SetInserted is reported when the key was not tracked by the cache.
+	SetInserted SetOutcome = iota + 1
+	// SetUpdated is reported when the key was resident
+	// and its value was replaced in place.
+	SetUpdated
+	// SetResurrected is reported when the key was a test page,
+	// and was made resident again.
+	SetResurrected
+	// SetRejected is reported when the key was not tracked
+	// by the cache, and was denied admission,
+	// or when its value exceeded the maximum weight.
+	// See [WithDoorkeeper] and [WithMaxWeight].
+	SetRejected
+)
+
+// SetGetEvicted is like [Cache.Set] but also returns
+// the entry that was evicted to make room for key, if any.
+// If several entries are evicted, such as by [WithMaxWeight],
+// only the first is returned; the others are discarded as
+// if by [Cache.Set], so they are finalized (see [WithFinalizer])
+// and sent to [Cache.Evictions], like the returned entry.
+func (c *Cache[Key, Value]) SetGetEvicted(key Key, value Value) (evictedKey Key, evictedValue Value, evicted bool) {
+	var returned setResult[Key, Value]
+	c.returnEvicted = &returned
+	c.set(key, value, 0)
+	c.returnEvicted = nil
+	return returned.evictedKey, returned.evictedValue, returned.evicted
+}
+
+// SetReport is like [Cache.Set] but also returns
+// whether key was inserted, updated, or resurrected.
+func (c *Cache[Key, Value]) SetReport(key Key, value Value) SetOutcome {
+	return c.set(key, value, 0).outcome
+}
+
+// set stores value for key. If cost is not 0,
+// it is the cost of the miss which produced value.
+func (c *Cache[Key, Value]) set(key Key, value Value, cost float64) setResult[Key, Value] {
+	if c.latencies != nil {
+		defer c.latencies.set.record(time.Now())
+	}
+	if c.equal != nil && c.refreshed(key, value) {
+		return setResult[Key, Value]{outcome: SetUpdated}
+	}
+	value = c.encoded(value)
+	if c.overweight(key, value) {
+		c.removeKey(key, false)
+		return setResult[Key, Value]{outcome: SetRejected}
+	}
+	page, found := c.index.get(key)
+	if found && page.Resident {
+		c.update(page, value)
+		c.recordCost(cost)
+		c.expiry.cancel(key)
+		c.stored(key)
+		c.trimWeight()
+		return setResult[Key, Value]{outcome: SetUpdated}
+	}
+	result := c.handleMiss(key, value, found, cost)
+	c.stored(key)
+	c.trimWeight()
+	return result
+}
+
+// WithValueEquality makes [Cache.Set] (and its variants)
+// skip storing a value which equal reports is equal to the
+// resident value of its key, such as when an unchanged value
+// is refreshed from its source. The entry is referenced as if
+// it was updated, and its expiry is replaced as usual, but the
+// resident value is retained, so that it is not released (see
+// [Hooks.OnRelease]), its version is retained (see
+// [Cache.SetVersioned]), and concurrent caches do not
+// publish it again. equal is called with the resident value
+// first, and values are compared before they are encoded.
+func WithValueEquality[Key comparable, Value any](equal func(resident, value Value) bool) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if equal == nil {
+			return fmt.Errorf("%w: equality function must not be nil", ErrInvalidOption)
+		}
+		set.equal = equal
+		return nil
+	}
+}
+
+// WithComparableValues is like [WithValueEquality],
+// comparing values with the == operator.
+func WithComparableValues[Key, Value comparable]() Option[Key, Value] {
+	return WithValueEquality[Key](func(resident, value Value) bool {
+		return resident == value
+	})
+}
+
+// refreshed references the resident page of key
+// if its value is equal to value, as if it was updated,
+// and reports whether it was.
+func (c *Cache[Key, Value]) refreshed(key Key, value Value) bool {
+	page, ok := c.index.get(key)
+	if !ok || !page.Resident || c.expired(key) ||
+		!c.equal(c.decoded(page.Value), value) {
+		return false
+	}
+	c.recordOperation(OperationSet, key)
+	c.access(key)
+	c.touch(page)
+	page.Referenced = true
+	if _, scheduled := c.expiry.deadline(key); scheduled {
+		c.expiry.cancel(key)
+		c.stored(key)
+	}
+	return true
+}
+
+func (outcome SetOutcome) String() string {
+	switch outcome {
+	case SetInserted:
+		return "inserted"
+	case SetUpdated:
+		return "updated"
+	case SetResurrected:
+		return "resurrected"
+	case SetRejected:
+		return "rejected"
+	default:
+		return "SetOutcome(" + strconv.Itoa(int(outcome)) + ")"
+	}
+}
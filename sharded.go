@@ -0,0 +1,579 @@
+package clockpro
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/maphash"
+	"iter"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sharded partitions keys across multiple [Synced] caches,
+// so that misses and modifications of different shards
+// do not contend for the same lock.
+// Constructed by [NewSharded].
+//
+// Each shard adapts its own cold target.
+// Capacity may also be moved between shards
+// by [Sharded.Rebalance].
+type Sharded[Key comparable, Value any] struct {
+	shards        []*Synced[Key, Value]
+	resurrections []uint64 // As of the last rebalance.
+	hash          func(Key) uint64
+	hot           *hotKeys[Key, Value] // See [WithHotKeyReplication].
+	rebalancing   sync.Mutex
+}
+
+// shardCapacityTarget is the least capacity
+// given to each shard when the count is chosen automatically.
+const shardCapacityTarget = 64
+
+// rebalanceFraction is the inverse of the fraction
+// of a shard's capacity moved by [Sharded.Rebalance].
+const rebalanceFraction = 16
+
+// NewSharded constructs a [Sharded] cache,
+// dividing capacity evenly between shards.
+// Each shard must receive at least [MinimumCapacity].
+// If shards is 0, the count is chosen from [runtime.GOMAXPROCS]
+// and capacity, such that each shard receives enough capacity
+// to adapt to its workload.
+// Options are applied to each shard individually,
+// so functions provided to them (such as [Hooks])
+// may be called concurrently by different shards.
+// [WithRecording] is not supported.
+func NewSharded[Key comparable, Value any](capacity, shards int, options ...Option[Key, Value]) (*Sharded[Key, Value], error) {
+	partitions, hash, err := partition(capacity, shards, options, NewSynced[Key, Value])
+	if err != nil {
+		return nil, err
+	}
+	sharded := &Sharded[Key, Value]{
+		shards:        partitions,
+		resurrections: make([]uint64, len(partitions)),
+		hash:          hash,
+	}
+	if cache := partitions[0].cache; cache.hotFraction != 0 && cache.maxIdle == 0 {
+		sharded.hot = newHotKeys(partitions, cache.hotFraction)
+		for _, shard := range partitions {
+			shard.hot = sharded.hot
+		}
+	}
+	return sharded, nil
+}
+
+// WithShardHash sets the function used by [Sharded]
+// and [Striped] caches to assign keys to shards.
+// By default, keys are hashed by [maphash.Comparable].
+// Ignored by other constructors.
+func WithShardHash[Key comparable, Value any](hash func(Key) uint64) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if hash == nil {
+			return fmt.Errorf(
+				"%w: shard hash function must not be nil",
+				ErrInvalidOption,
+			)
+		}
+		set.shardHash = hash
+		return nil
+	}
+}
+
+// partition divides capacity evenly between shards,
+// and constructs each of them with options.
+// It also returns the hash function used to assign keys to shards.
+func partition[Key comparable, Value any, Shard any](
+	capacity, shards int, options []Option[Key, Value],
+	construct func(int, ...Option[Key, Value]) (Shard, error),
+) ([]Shard, func(Key) uint64, error) {
+	if capacity < MinimumCapacity {
+		return nil, nil, minCapacityError(capacity)
+	}
+	if shards == 0 {
+		shards = max(min(
+			runtime.GOMAXPROCS(0)*4,
+			capacity/shardCapacityTarget,
+		), 1)
+	}
+	if shards < 1 {
+		return nil, nil, fmt.Errorf(
+			"%w: shard count must be >=0 but %d was requested",
+			ErrInvalidCapacity, shards,
+		)
+	}
+	if perShard := capacity / shards; perShard < MinimumCapacity {
+		return nil, nil, fmt.Errorf(
+			"%w: each shard must receive >=%d but %d/%d was requested",
+			ErrInvalidCapacity, MinimumCapacity, capacity, shards,
+		)
+	}
+	settings, err := applyOptions(options)
+	if err != nil {
+		return nil, nil, err
+	}
+	if settings.recording != nil {
+		return nil, nil, fmt.Errorf(
+			"%w: recordings cannot be shared between shards",
+			ErrInvalidOption,
+		)
+	}
+	partitions := make([]Shard, shards)
+	for i := range partitions {
+		shardCapacity := capacity / shards
+		if i < capacity%shards {
+			shardCapacity++
+		}
+		shard, err := construct(shardCapacity, options...)
+		if err != nil {
+			return nil, nil, err
+		}
+		partitions[i] = shard
+	}
+	hash := settings.shardHash
+	if hash == nil {
+		seed := maphash.MakeSeed()
+		hash = func(key Key) uint64 {
+			return maphash.Comparable(seed, key)
+		}
+	}
+	return partitions, hash, nil
+}
+
+func (sc *Sharded[Key, Value]) shard(key Key) *Synced[Key, Value] {
+	return sc.shards[sc.shardIndex(key)]
+}
+
+func (sc *Sharded[Key, _]) shardIndex(key Key) int {
+	return int(sc.hash(key) % uint64(len(sc.shards)))
+}
+
+// Get is like [Cache.Get].
+func (sc *Sharded[Key, Value]) Get(key Key) (Value, bool) {
+	shard := sc.shard(key)
+	hot := sc.hot
+	if hot == nil {
+		return shard.Get(key)
+	}
+	var (
+		entry, ok = hot.get(key, shard.cache.now)
+		value     Value
+	)
+	if ok {
+		value = shard.cache.decoded(entry.value)
+	} else if value, ok = shard.Get(key); !ok {
+		return value, false
+	}
+	hot.sample(shard, key)
+	return value, true
+}
+
+// GetMany is like [Cache.GetMany],
+// looking up each key in its shard.
+func (sc *Sharded[Key, Value]) GetMany(keys []Key) (found map[Key]Value, missing []Key) {
+	return getMany(keys, sc.Get)
+}
+
+// GetWithExpiry is like [Cache.GetWithExpiry].
+func (sc *Sharded[Key, Value]) GetWithExpiry(key Key) (Value, time.Time, bool) {
+	return sc.shard(key).GetWithExpiry(key)
+}
+
+// Load is like [Synced.Load].
+func (sc *Sharded[Key, Value]) Load(key Key, fetch func() (Value, error)) (Value, error) {
+	return sc.shard(key).Load(key, fetch)
+}
+
+// LoadContext is like [Synced.LoadContext].
+func (sc *Sharded[Key, Value]) LoadContext(ctx context.Context, key Key, fetch func(context.Context) (Value, error)) (Value, error) {
+	return sc.shard(key).LoadContext(ctx, key, fetch)
+}
+
+// GetOrSet is like [Cache.GetOrSet].
+func (sc *Sharded[Key, Value]) GetOrSet(key Key, value Value) (actual Value, loaded bool) {
+	return sc.shard(key).GetOrSet(key, value)
+}
+
+// SetIf is like [Synced.SetIf].
+func (sc *Sharded[Key, Value]) SetIf(key Key, value Value, cond func(old Value, exists bool) bool) bool {
+	return sc.shard(key).SetIf(key, value, cond)
+}
+
+// Compute is like [Synced.Compute].
+func (sc *Sharded[Key, Value]) Compute(key Key, f func(old Value, exists bool) (value Value, keep bool)) (Value, bool) {
+	return sc.shard(key).Compute(key, f)
+}
+
+// Mutate is like [Synced.Mutate].
+func (sc *Sharded[Key, Value]) Mutate(key Key, mutate func(*Value)) bool {
+	return sc.shard(key).Mutate(key, mutate)
+}
+
+// Set is like [Cache.Set].
+func (sc *Sharded[Key, Value]) Set(key Key, value Value) {
+	sc.shard(key).Set(key, value)
+}
+
+// SetWithTTL is like [Cache.SetWithTTL].
+func (sc *Sharded[Key, Value]) SetWithTTL(key Key, value Value, ttl time.Duration) {
+	sc.shard(key).SetWithTTL(key, value, ttl)
+}
+
+// ExtendTTL is like [Cache.ExtendTTL].
+func (sc *Sharded[Key, _]) ExtendTTL(key Key, ttl time.Duration) bool {
+	return sc.shard(key).ExtendTTL(key, ttl)
+}
+
+// SetExpiry is like [Cache.SetExpiry].
+func (sc *Sharded[Key, _]) SetExpiry(key Key, deadline time.Time) bool {
+	return sc.shard(key).SetExpiry(key, deadline)
+}
+
+// SetVersioned is like [Synced.SetVersioned].
+// Versions are assigned by each shard independently.
+func (sc *Sharded[Key, Value]) SetVersioned(key Key, value Value) uint64 {
+	return sc.shard(key).SetVersioned(key, value)
+}
+
+// GetVersioned is like [Synced.GetVersioned].
+func (sc *Sharded[Key, Value]) GetVersioned(key Key) (Value, uint64, bool) {
+	return sc.shard(key).GetVersioned(key)
+}
+
+// CompareAndSetVersion is like [Synced.CompareAndSetVersion].
+func (sc *Sharded[Key, Value]) CompareAndSetVersion(key Key, version uint64, value Value) bool {
+	return sc.shard(key).CompareAndSetVersion(key, version, value)
+}
+
+// Delete is like [Cache.Delete].
+func (sc *Sharded[Key, Value]) Delete(key Key) bool {
+	return sc.shard(key).Delete(key)
+}
+
+// Invalidate is like [Cache.Invalidate].
+func (sc *Sharded[Key, Value]) Invalidate(key Key) bool {
+	return sc.shard(key).Invalidate(key)
+}
+
+// SetTagged is like [Cache.SetTagged].
+func (sc *Sharded[Key, Value]) SetTagged(key Key, value Value, tags ...string) {
+	sc.shard(key).SetTagged(key, value, tags...)
+}
+
+// InvalidateTag calls [Synced.InvalidateTag] for each shard,
+// and returns the total amount of entries invalidated.
+func (sc *Sharded[Key, Value]) InvalidateTag(tag string) int {
+	var invalidated int
+	for _, shard := range sc.shards {
+		invalidated += shard.InvalidateTag(tag)
+	}
+	return invalidated
+}
+
+// Remove is like [Cache.Remove].
+func (sc *Sharded[Key, Value]) Remove(key Key) (Value, bool) {
+	return sc.shard(key).Remove(key)
+}
+
+// Apply is like [Synced.Apply], acquiring the lock
+// of each shard once. Operations are applied in order
+// within each shard, and each shard is applied in turn.
+func (sc *Sharded[Key, Value]) Apply(ops []Op[Key, Value]) []OpResult[Value] {
+	return applyPartitioned(ops, len(sc.shards), sc.shardIndex,
+		func(shard int, ops []Op[Key, Value]) []OpResult[Value] {
+			return sc.shards[shard].Apply(ops)
+		})
+}
+
+// Purge is like [Synced.Purge], applied to each shard in turn.
+func (sc *Sharded[Key, Value]) Purge(onPurge func(Key, Value)) {
+	for _, shard := range sc.shards {
+		shard.Purge(onPurge)
+	}
+}
+
+// Flush is like [Synced.Flush], applied to each shard in turn.
+func (sc *Sharded[_, _]) Flush() {
+	for _, shard := range sc.shards {
+		shard.Flush()
+	}
+}
+
+// Close is like [Synced.Close], applied to each shard in turn.
+func (sc *Sharded[_, _]) Close() error {
+	errs := make([]error, len(sc.shards))
+	for i, shard := range sc.shards {
+		errs[i] = shard.Close()
+	}
+	return errors.Join(errs...)
+}
+
+// Rebalance moves a fraction of the capacity of the
+// shard with the lowest rate of resurrections
+// (inserts of keys shortly after their eviction)
+// to the shard with the highest rate, as measured
+// since the previous call. This allows shards which hold
+// more of the working set to grow, when keys are unevenly
+// distributed between shards. Shards never shrink below
+// [MinimumCapacity], and the total capacity is unchanged.
+// Rebalance reports whether capacity was moved.
+// It is intended to be called periodically;
+// see [Sharded.RebalanceEvery].
+func (sc *Sharded[_, _]) Rebalance() bool {
+	sc.rebalancing.Lock()
+	defer sc.rebalancing.Unlock()
+	var (
+		donor, recipient         = -1, -1
+		donorRate, recipientRate float64
+		capacities               = make([]int, len(sc.shards))
+	)
+	for i, shard := range sc.shards {
+		var (
+			resurrections = shard.Stats().Resurrections
+			delta         = resurrections - sc.resurrections[i]
+			capacity      = shard.capacity()
+			rate          = float64(delta) / float64(capacity)
+		)
+		sc.resurrections[i] = resurrections
+		capacities[i] = capacity
+		if capacity > MinimumCapacity &&
+			(donor == -1 || rate < donorRate) {
+			donor, donorRate = i, rate
+		}
+		if delta != 0 &&
+			(recipient == -1 || rate > recipientRate) {
+			recipient, recipientRate = i, rate
+		}
+	}
+	if donor == -1 || recipient == -1 ||
+		donor == recipient || donorRate >= recipientRate {
+		return false
+	}
+	step := min(
+		max(capacities[donor]/rebalanceFraction, 1),
+		capacities[donor]-MinimumCapacity,
+	)
+	sc.shards[donor].resize(capacities[donor] - step)
+	sc.shards[recipient].resize(capacities[recipient] + step)
+	return true
+}
+
+// RebalanceEvery calls [Sharded.Rebalance] every interval,
+// from a new goroutine, until stop is called.
+func (sc *Sharded[_, _]) RebalanceEvery(interval time.Duration) (stop func()) {
+	return every(interval, func() { sc.Rebalance() })
+}
+
+// Expire calls [Synced.Expire] for each shard in turn,
+// and returns the sum of their results.
+func (sc *Sharded[_, _]) Expire() int {
+	var expired int
+	for _, shard := range sc.shards {
+		expired += shard.Expire()
+	}
+	return expired
+}
+
+// ExpireEvery starts a [Janitor] which calls
+// [Sharded.Expire] every interval, until stop is called.
+func (sc *Sharded[_, _]) ExpireEvery(interval time.Duration) (stop func()) {
+	return NewJanitor(sc, interval).Stop
+}
+
+// every calls fn every interval,
+// from a new goroutine, until stop is called.
+func every(interval time.Duration, fn func()) (stop func()) {
+	var (
+		ticker = time.NewTicker(interval)
+		done   = make(chan struct{})
+		once   sync.Once
+	)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				fn()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() { once.Do(func() { close(done) }) }
+}
+
+// CheckInvariants is like [Synced.CheckInvariants],
+// applied to each shard in turn.
+func (sc *Sharded[Key, Value]) CheckInvariants() error {
+	return checkPartitions("shard", sc.shards, (*Synced[Key, Value]).CheckInvariants)
+}
+
+// Len returns the sum of each shard's [Synced.Len].
+// Shards are not locked simultaneously,
+// so the result may not reflect any single instant.
+func (sc *Sharded[_, _]) Len() int {
+	var length int
+	for _, shard := range sc.shards {
+		length += shard.Len()
+	}
+	return length
+}
+
+// MemoryUsage returns the sum of each shard's [Synced.MemoryUsage].
+func (sc *Sharded[Key, Value]) MemoryUsage() int {
+	return sc.sum((*Synced[Key, Value]).MemoryUsage)
+}
+
+// ResidentHot returns the sum of each shard's [Synced.ResidentHot].
+func (sc *Sharded[Key, Value]) ResidentHot() int {
+	return sc.sum((*Synced[Key, Value]).ResidentHot)
+}
+
+// ResidentCold returns the sum of each shard's [Synced.ResidentCold].
+func (sc *Sharded[Key, Value]) ResidentCold() int {
+	return sc.sum((*Synced[Key, Value]).ResidentCold)
+}
+
+// NonResident returns the sum of each shard's [Synced.NonResident].
+func (sc *Sharded[Key, Value]) NonResident() int {
+	return sc.sum((*Synced[Key, Value]).NonResident)
+}
+
+// sum returns the sum of count for each shard.
+// Like [Sharded.Len], shards are not locked simultaneously.
+func (sc *Sharded[Key, Value]) sum(count func(*Synced[Key, Value]) int) int {
+	var total int
+	for _, shard := range sc.shards {
+		total += count(shard)
+	}
+	return total
+}
+
+// Snapshot returns the [Synced.Snapshot] of each shard in turn.
+// Each shard's entries are consistent with each other,
+// but not with those of other shards.
+func (sc *Sharded[Key, Value]) Snapshot() *ReadOnlyView[Key, Value] {
+	views := make([]*ReadOnlyView[Key, Value], len(sc.shards))
+	for i, shard := range sc.shards {
+		views[i] = shard.Snapshot()
+	}
+	return joinViews(views)
+}
+
+// Keys returns the [Synced.Keys] of each shard in turn.
+func (sc *Sharded[Key, _]) Keys() iter.Seq[Key] {
+	return func(yield func(Key) bool) {
+		for _, shard := range sc.shards {
+			for key := range shard.Keys() {
+				if !yield(key) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// TrackedKeys returns the [Synced.TrackedKeys]
+// of each shard in turn.
+func (sc *Sharded[Key, _]) TrackedKeys() iter.Seq2[Key, bool] {
+	return func(yield func(Key, bool) bool) {
+		for _, shard := range sc.shards {
+			for key, resident := range shard.TrackedKeys() {
+				if !yield(key, resident) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Range calls [Synced.Range] for each shard in turn,
+// until f returns false.
+func (sc *Sharded[Key, Value]) Range(f func(Key, Value) bool) {
+	for _, shard := range sc.shards {
+		stopped := false
+		shard.Range(func(key Key, value Value) bool {
+			stopped = !f(key, value)
+			return !stopped
+		})
+		if stopped {
+			return
+		}
+	}
+}
+
+// EntriesWhere returns the [Synced.EntriesWhere]
+// of each shard in turn.
+func (sc *Sharded[Key, Value]) EntriesWhere(match func(Key, Value) bool) iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		for _, shard := range sc.shards {
+			for key, value := range shard.EntriesWhere(match) {
+				if !yield(key, value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// String returns the [Synced.String] of each shard,
+// one per line.
+func (sc *Sharded[_, _]) String() string {
+	lines := make([]string, len(sc.shards))
+	for i, shard := range sc.shards {
+		lines[i] = shard.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Stats returns the sum of each shard's [Synced.Stats],
+// including the hits served by replicas,
+// which are counted by the shard of the same index.
+// See [WithHotKeyReplication].
+func (sc *Sharded[Key, Value]) Stats() Stats {
+	return sc.sumStats((*Synced[Key, Value]).Stats)
+}
+
+// IntervalStats returns the sum of
+// the [Synced.IntervalStats] of each shard.
+// Shards are reset independently, so each shard's
+// interval may start at a different time;
+// Since and Elapsed are those of the first shard.
+func (sc *Sharded[Key, Value]) IntervalStats() Stats {
+	return sc.sumStats((*Synced[Key, Value]).IntervalStats)
+}
+
+// ResetStats is like [Sharded.IntervalStats],
+// but resets each shard in turn by [Synced.ResetStats].
+func (sc *Sharded[Key, Value]) ResetStats() Stats {
+	return sc.sumStats((*Synced[Key, Value]).ResetStats)
+}
+
+// sumStats returns the sum of the statistics
+// returned by shardStats for each shard.
+func (sc *Sharded[Key, Value]) sumStats(shardStats func(*Synced[Key, Value]) Stats) Stats {
+	var stats Stats
+	for i, shard := range sc.shards {
+		partial := shardStats(shard)
+		if i == 0 {
+			stats.Since, stats.Elapsed = partial.Since, partial.Elapsed
+		}
+		stats.add(partial)
+	}
+	if hot := sc.hot; hot != nil {
+		stats.Replicated = hot.len()
+	}
+	return stats
+}
+
+// ReplicatedKeys returns the keys which are currently
+// replicated across shards, in no particular order.
+// See [WithHotKeyReplication].
+func (sc *Sharded[Key, _]) ReplicatedKeys() []Key {
+	if sc.hot == nil {
+		return nil
+	}
+	return sc.hot.keys()
+}
@@ -0,0 +1,325 @@
+package clockpro_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestSharded(t *testing.T) {
+	t.Run("invalid", shardedInvalid)
+	t.Run("capacity", shardedCapacity)
+	t.Run("automatic shard count", shardedAutomatic)
+	t.Run("stats", shardedStats)
+	t.Run("concurrent", shardedConcurrent)
+	t.Run("rebalance", shardedRebalance)
+	t.Run("rebalance skew", shardedRebalanceSkew)
+	t.Run("hot key replication", shardedHotKeys)
+}
+
+func newSharded(tb testing.TB, capacity, shards int, options ...clockpro.Option[int, int]) *clockpro.Sharded[int, int] {
+	tb.Helper()
+	cache, err := clockpro.NewSharded(capacity, shards, options...)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return cache
+}
+
+func shardedInvalid(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name             string
+		capacity, shards int
+		options          []clockpro.Option[int, int]
+		want             error
+	}{
+		{"negative shards", 8, -1, nil, clockpro.ErrInvalidCapacity},
+		{"small capacity", 1, 0, nil, clockpro.ErrInvalidCapacity},
+		{"small shards", 8, 5, nil, clockpro.ErrInvalidCapacity},
+		{
+			"recording", 8, 2,
+			[]clockpro.Option[int, int]{
+				clockpro.WithRecording[int, int](new(clockpro.Recording[int])),
+			},
+			clockpro.ErrInvalidOption,
+		},
+		{
+			"nil hash", 8, 2,
+			[]clockpro.Option[int, int]{
+				clockpro.WithShardHash[int, int](nil),
+			},
+			clockpro.ErrInvalidOption,
+		},
+		{
+			"hot key fraction", 8, 2,
+			[]clockpro.Option[int, int]{
+				clockpro.WithHotKeyReplication[int, int](1.5),
+			},
+			clockpro.ErrInvalidOption,
+		},
+	} {
+		cache, err := clockpro.NewSharded(test.capacity, test.shards, test.options...)
+		if cache != nil || !errors.Is(err, test.want) {
+			t.Errorf(
+				"%s: unexpected error"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				test.name, err, test.want,
+			)
+		}
+	}
+}
+
+func shardedCapacity(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 30
+		shards   = 4
+	)
+	cache := newSharded(t, capacity, shards)
+	for key := range capacity * 4 {
+		cache.Set(key, key)
+	}
+	if got := cache.Len(); got != capacity {
+		t.Errorf(
+			"shards did not fill to capacity"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			got, capacity,
+		)
+	}
+	var keys int
+	for key := range cache.Keys() {
+		checkGet(t, cache, key, key, "resident key")
+		keys++
+	}
+	if keys != capacity {
+		t.Errorf(
+			"unexpected key count"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			keys, capacity,
+		)
+	}
+	cache.Purge(nil)
+	checkSize(t, cache, 0, "after purge")
+}
+
+func shardedStats(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 16
+		shards   = 4
+		keys     = 8
+	)
+	cache := newSharded(t, capacity, shards)
+	for key := range keys {
+		mustMiss(t, cache, key, "empty cache")
+		cache.Set(key, key)
+		mustGet(t, cache, key)
+	}
+	stats := cache.Stats()
+	if stats.Hits != keys || stats.Misses != keys {
+		t.Errorf(
+			"unexpected hits and misses"+
+				"\n\tgot: %d, %d"+
+				"\n\twant: %d, %d",
+			stats.Hits, stats.Misses, keys, keys,
+		)
+	}
+}
+
+func shardedHotKeys(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 16
+		shards   = 4
+		hot      = 1
+		lookups  = 1 << 14
+	)
+	cache := newSharded(t, capacity, shards,
+		clockpro.WithHotKeyReplication[int, int](0.5),
+	)
+	for key := range shards {
+		cache.Set(key, key)
+	}
+	for i := range lookups {
+		key := hot
+		if i%4 == 0 { // Not hot enough.
+			key = i / 4 % shards
+		}
+		mustGet(t, cache, key)
+	}
+	checkReplicated := func(want []int, context string) {
+		t.Helper()
+		got := cache.ReplicatedKeys()
+		if !slices.Equal(got, want) {
+			t.Errorf(
+				"unexpected replicated keys %s"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				context, got, want,
+			)
+		}
+		if stats := cache.Stats(); stats.Replicated != len(want) {
+			t.Errorf(
+				"unexpected replicated count %s"+
+					"\n\tgot: %d"+
+					"\n\twant: %d",
+				context, stats.Replicated, len(want),
+			)
+		}
+	}
+	checkReplicated([]int{hot}, "after lookups")
+	if stats := cache.Stats(); stats.Hits != lookups {
+		t.Errorf(
+			"replica hits were not counted"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			stats.Hits, lookups,
+		)
+	}
+	cache.Set(hot, -hot)
+	checkGet(t, cache, hot, -hot, "modified replicated key")
+	checkReplicated([]int{hot}, "after modification")
+	cache.Delete(hot)
+	mustMiss(t, cache, hot, "deleted replicated key")
+	checkReplicated(nil, "after deletion")
+	concurrent := newSharded(t, capacity, shards,
+		clockpro.WithHotKeyReplication[int, int](0.01),
+	)
+	exerciseConcurrently(t, concurrent, capacity*2, 8, lookups)
+	resident := slices.Collect(concurrent.Keys())
+	for _, key := range concurrent.ReplicatedKeys() {
+		if !slices.Contains(resident, key) {
+			t.Errorf("nonresident key %d is replicated", key)
+		}
+	}
+}
+
+func shardedConcurrent(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity   = 64
+		shards     = 4
+		upperBound = capacity * 2
+		workers    = 8
+		accesses   = 4096
+	)
+	cache := newSharded(t, capacity, shards)
+	exerciseConcurrently(t, cache, upperBound, workers, accesses)
+	if length := cache.Len(); length > capacity {
+		t.Errorf(
+			"cache exceeded capacity"+
+				"\n\tgot: %d"+
+				"\n\twant: <=%d",
+			length, capacity,
+		)
+	}
+}
+
+func shardedRebalance(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity   = 64
+		shards     = 4
+		upperBound = capacity * 2
+		rounds     = 64
+		accesses   = capacity * 4
+	)
+	var (
+		cache = newSharded(t, capacity, shards)
+		rng   = newReproducibleRNG()
+	)
+	if cache.Rebalance() {
+		t.Error("Rebalance moved capacity without any resurrections")
+	}
+	var moved int
+	for range rounds {
+		for range accesses {
+			key := rng.Intn(upperBound)
+			if _, ok := cache.Get(key); !ok {
+				cache.Set(key, key)
+			}
+		}
+		if cache.Rebalance() {
+			moved++
+		}
+	}
+	if moved == 0 {
+		t.Error("Rebalance never moved capacity")
+	}
+	if cache.Rebalance() {
+		t.Error("Rebalance moved capacity without new resurrections")
+	}
+	for key := range capacity * 16 {
+		cache.Set(upperBound+key, key)
+	}
+	checkSize(t, cache, capacity, "after rebalancing")
+	stop := cache.RebalanceEvery(time.Millisecond)
+	stop()
+	stop()
+}
+
+func shardedAutomatic(t *testing.T) {
+	t.Parallel()
+	for _, capacity := range []int{
+		clockpro.MinimumCapacity, 3, 63, 64, 100, 1 << 12,
+	} {
+		cache := newSharded(t, capacity, 0)
+		for key := range capacity * 16 {
+			cache.Set(key, key)
+		}
+		checkSize(t, cache, capacity, fmt.Sprintf("filling capacity %d", capacity))
+		for key := range capacity * 16 { // Resurrects recently evicted keys.
+			cache.Set(capacity*16-key-1, key)
+		}
+		cache.Rebalance() // Must account for every automatic shard.
+		checkSize(t, cache, capacity, fmt.Sprintf("rebalancing capacity %d", capacity))
+	}
+}
+
+// shardedRebalanceSkew accesses keys of only one shard,
+// which should receive the capacity of the other.
+func shardedRebalanceSkew(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity   = 64
+		shards     = 2
+		upperBound = capacity * 3 / 2
+		rounds     = 64
+		accesses   = capacity * 4
+	)
+	var (
+		byParity = clockpro.WithShardHash[int, int](func(key int) uint64 {
+			return uint64(key)
+		})
+		cache = newSharded(t, capacity, shards, byParity)
+		rng   = newReproducibleRNG()
+	)
+	for range rounds {
+		for range accesses {
+			key := rng.Intn(upperBound) * shards // Shard 0.
+			if _, ok := cache.Get(key); !ok {
+				cache.Set(key, key)
+			}
+		}
+		cache.Rebalance()
+	}
+	for key := range capacity * 4 {
+		cache.Set((upperBound+key)*shards, key)
+	}
+	if got, want := cache.Len(), capacity-clockpro.MinimumCapacity; got != want {
+		t.Errorf(
+			"accessed shard did not receive the capacity of the other"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			got, want,
+		)
+	}
+}
@@ -0,0 +1,108 @@
+package clockpro
+
+import (
+	"fmt"
+	"math"
+)
+
+// shiftDetector compares the rate of test page hits
+// within windows of insertions, to a moving average
+// of the rates of previous windows.
+type shiftDetector struct {
+	threshold, baseline      float64
+	window, insertions, hits int
+	deviation                int // Sign of the previous window's deviation.
+	hasBaseline              bool
+}
+
+// shiftSmoothing is the weight of the newest window
+// in the moving average of test page hit ratios.
+const shiftSmoothing = 1.0 / 4
+
+// WithShiftDetection re-centers the cold target
+// when the workload shifts, rather than adapting
+// to it one step per test page hit.
+// The ratio of test page hits to insertions is measured
+// over consecutive windows of window insertions;
+// if the ratio deviates from its moving average by at least
+// threshold for two consecutive windows, in the same direction,
+// the cold target is moved halfway toward its maximum
+// (if the ratio increased) or minimum (if it decreased),
+// and the moving average is reset.
+// The threshold must be within (0, 1].
+func WithShiftDetection[Key comparable, Value any](window int, threshold float64) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if window < 1 {
+			return fmt.Errorf(
+				"%w: shift detection window must be >=1 but %d was provided",
+				ErrInvalidOption, window,
+			)
+		}
+		if !(threshold > 0 && threshold <= 1) {
+			return fmt.Errorf(
+				"%w: shift detection threshold must be within (0,1] but %f was provided",
+				ErrInvalidOption, threshold,
+			)
+		}
+		set.shifts = &shiftDetector{
+			window:    window,
+			threshold: threshold,
+		}
+		return nil
+	}
+}
+
+// observe records an insertion, and returns the sign
+// of the shift in the test page hit ratio if one was detected,
+// or 0 otherwise.
+func (sd *shiftDetector) observe(hit bool) int {
+	sd.insertions++
+	if hit {
+		sd.hits++
+	}
+	if sd.insertions < sd.window {
+		return 0
+	}
+	ratio := float64(sd.hits) / float64(sd.insertions)
+	sd.insertions, sd.hits = 0, 0
+	if !sd.hasBaseline {
+		sd.baseline, sd.hasBaseline = ratio, true
+		return 0
+	}
+	var (
+		change    = ratio - sd.baseline
+		deviation int
+	)
+	if math.Abs(change) >= sd.threshold {
+		deviation = 1
+		if change < 0 {
+			deviation = -1
+		}
+	}
+	if deviation != 0 && deviation == sd.deviation {
+		sd.baseline, sd.deviation = ratio, 0
+		return deviation
+	}
+	sd.baseline += change * shiftSmoothing
+	sd.deviation = deviation
+	return 0
+}
+
+func (sd *shiftDetector) reset() {
+	sd.insertions, sd.hits = 0, 0
+	sd.deviation, sd.hasBaseline = 0, false
+}
+
+// observeShift should be called for every insertion,
+// with whether it was a test page hit.
+func (c *Cache[_, _]) observeShift(hit bool) {
+	if c.shifts == nil {
+		return
+	}
+	switch c.shifts.observe(hit) {
+	case 1:
+		c.adjustColdTarget((c.coldMaximum - c.coldTarget + 1) / 2)
+	case -1:
+		c.adjustColdTarget(-(c.coldTarget - c.coldMinimum + 1) / 2)
+	}
+}
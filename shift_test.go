@@ -0,0 +1,80 @@
+package clockpro_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestShiftDetection(t *testing.T) {
+	t.Run("invalid", shiftInvalid)
+	t.Run("re-center", shiftRecenter)
+}
+
+func shiftInvalid(t *testing.T) {
+	t.Parallel()
+	for _, option := range []clockpro.Option[int, int]{
+		clockpro.WithShiftDetection[int, int](0, 0.5),
+		clockpro.WithShiftDetection[int, int](1, 0),
+		clockpro.WithShiftDetection[int, int](1, 1.5),
+	} {
+		if _, err := clockpro.New(2, option); !errors.Is(err, clockpro.ErrInvalidOption) {
+			t.Errorf(
+				"expected error to match"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				err, clockpro.ErrInvalidOption)
+		}
+	}
+}
+
+func shiftRecenter(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity  = 64
+		window    = capacity / 4
+		threshold = 0.1
+	)
+	var (
+		gradual  = shiftedColdTarget(t, capacity)
+		detected = shiftedColdTarget(t, capacity,
+			clockpro.WithShiftDetection[int, int](window, threshold),
+		)
+	)
+	if detected <= gradual {
+		t.Errorf(
+			"expected detection to adapt faster to the workload shift"+
+				"\n\tgot: %d"+
+				"\n\twant: >%d",
+			detected, gradual)
+	}
+}
+
+// shiftedColdTarget runs a workload which fits in the cache,
+// followed by a brief workload which does not,
+// and returns the highest cold target reached.
+func shiftedColdTarget(tb testing.TB, capacity int, options ...clockpro.Option[int, int]) int {
+	tb.Helper()
+	var coldTarget int
+	options = append(options, clockpro.WithAdaptationRecorder[int, int](
+		func(sample clockpro.AdaptationSample) { coldTarget = max(coldTarget, sample.ColdTarget) },
+	))
+	cache, err := clockpro.New(capacity, options...)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	access := func(key int) {
+		if _, ok := cache.Get(key); !ok {
+			cache.Set(key, key)
+		}
+	}
+	rng := newReproducibleRNG()
+	for range capacity * 16 {
+		access(rng.Intn(capacity))
+	}
+	for range capacity * 2 {
+		access(rng.Intn(capacity * 2))
+	}
+	return coldTarget
+}
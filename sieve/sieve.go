@@ -0,0 +1,125 @@
+// Package sieve provides a cache replaced by the SIEVE policy,
+// for comparison with CLOCK-Pro+. SIEVE keeps entries
+// in the order that they were inserted, and a hand which
+// moves from the oldest entry towards the newest, sparing
+// (and clearing) entries which were visited since it last
+// passed them, and evicting the first which was not.
+// Unlike CLOCK-Pro+, SIEVE retains no metadata for evicted entries.
+package sieve
+
+import (
+	"fmt"
+
+	"github.com/djdv/go-clockpro"
+	"github.com/djdv/go-clockpro/internal/list"
+)
+
+type (
+	// Cache is a SIEVE cache with the Get and Set
+	// methods of [clockpro.Cache].
+	// Cache is not safe for concurrent use.
+	Cache[Key comparable, Value any] struct {
+		index    map[Key]*entry[Key, Value]
+		hand     *entry[Key, Value] // Next to be examined, or nil.
+		entries  list.List[Key, Value]
+		capacity int
+		hits     uint64
+		misses   uint64
+	}
+	// entry is an element of the queue, whose
+	// Referenced flag records if it was visited.
+	entry[Key comparable, Value any] = list.Element[Key, Value]
+)
+
+// New constructs a [Cache] which holds up to capacity entries.
+func New[Key comparable, Value any](capacity int) (*Cache[Key, Value], error) {
+	if capacity < 1 {
+		return nil, fmt.Errorf(
+			"%w: must be >=1 but %d was requested",
+			clockpro.ErrInvalidCapacity, capacity,
+		)
+	}
+	return &Cache[Key, Value]{
+		index:    make(map[Key]*entry[Key, Value], capacity),
+		capacity: capacity,
+	}, nil
+}
+
+// Get returns the value of key if it is resident,
+// and marks it as visited.
+func (c *Cache[Key, Value]) Get(key Key) (Value, bool) {
+	e, ok := c.index[key]
+	if !ok {
+		c.misses++
+		var zero Value
+		return zero, false
+	}
+	c.hits++
+	e.Referenced = true
+	return e.Value, true
+}
+
+// Set inserts or updates key with value. Updating
+// marks the entry as visited, while inserting
+// into a full cache evicts an entry.
+func (c *Cache[Key, Value]) Set(key Key, value Value) {
+	if e, ok := c.index[key]; ok {
+		e.Value = value
+		e.Referenced = true
+		return
+	}
+	if c.entries.Len() == c.capacity {
+		c.evict()
+	}
+	e := c.entries.New()
+	e.Name, e.Value = key, value
+	c.entries.PushBack(e)
+	c.index[key] = e
+}
+
+// Delete removes key, and reports whether it was resident.
+func (c *Cache[Key, _]) Delete(key Key) bool {
+	e, ok := c.index[key]
+	if !ok {
+		return false
+	}
+	c.remove(e)
+	return true
+}
+
+// Len returns the number of resident entries.
+func (c *Cache[_, _]) Len() int { return c.entries.Len() }
+
+// Stats returns the hits and misses of [Cache.Get].
+func (c *Cache[_, _]) Stats() clockpro.Stats {
+	return clockpro.Stats{Hits: c.hits, Misses: c.misses}
+}
+
+// evict moves the hand from the oldest entry towards
+// the newest, wrapping around to the oldest, clearing
+// the visited entries until it reaches one which was not,
+// which is removed.
+func (c *Cache[Key, Value]) evict() {
+	hand := c.hand
+	if hand == nil {
+		hand = c.entries.Front()
+	}
+	for hand.Referenced {
+		hand.Referenced = false
+		hand = c.entries.Next(hand)
+	}
+	c.hand = hand
+	c.remove(hand)
+}
+
+// remove unlinks e, moving the hand past it.
+func (c *Cache[Key, Value]) remove(e *entry[Key, Value]) {
+	if c.hand == e {
+		if c.hand = c.entries.Next(e); c.hand == e || c.hand == c.entries.Front() {
+			c.hand = nil // Wraps around to the oldest entry.
+		}
+	}
+	delete(c.index, e.Name)
+	c.entries.Remove(e)
+	c.entries.Free(e)
+}
@@ -0,0 +1,147 @@
+package sieve_test
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+	"github.com/djdv/go-clockpro/sieve"
+)
+
+func TestCache(t *testing.T) {
+	t.Run("invalid", invalid)
+	t.Run("eviction order", evictionOrder)
+	t.Run("model", model)
+}
+
+func invalid(t *testing.T) {
+	t.Parallel()
+	if _, err := sieve.New[int, int](0); !errors.Is(err, clockpro.ErrInvalidCapacity) {
+		t.Errorf(
+			"expected error to match"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			err, clockpro.ErrInvalidCapacity,
+		)
+	}
+}
+
+func newCache(tb testing.TB, capacity int) *sieve.Cache[int, int] {
+	tb.Helper()
+	cache, err := sieve.New[int, int](capacity)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return cache
+}
+
+func evictionOrder(t *testing.T) {
+	t.Parallel()
+	cache := newCache(t, 3)
+	for key := 1; key <= 3; key++ {
+		cache.Set(key, key)
+	}
+	cache.Get(1)
+	cache.Set(4, 4) // Spares 1, evicts 2.
+	cache.Set(5, 5) // Evicts 3.
+	cache.Get(4)
+	cache.Set(6, 6) // Spares 4, evicts 5.
+	for key, want := range map[int]bool{
+		1: true, 2: false, 3: false,
+		4: true, 5: false, 6: true,
+	} {
+		if _, got := cache.Get(key); got != want {
+			t.Errorf(
+				"unexpected residency of key %d"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				key, got, want,
+			)
+		}
+	}
+}
+
+// reference is a direct implementation of SIEVE
+// over a slice, ordered from newest to oldest,
+// against which the cache is compared.
+type reference struct {
+	keys     []int
+	visited  map[int]bool
+	hand     int // Index within keys, or -1 for the oldest.
+	capacity int
+}
+
+func (r *reference) get(key int) bool {
+	if !slices.Contains(r.keys, key) {
+		return false
+	}
+	r.visited[key] = true
+	return true
+}
+
+func (r *reference) set(key int) {
+	if r.get(key) {
+		return
+	}
+	if len(r.keys) == r.capacity {
+		if r.hand < 0 {
+			r.hand = len(r.keys) - 1
+		}
+		for r.visited[r.keys[r.hand]] {
+			r.visited[r.keys[r.hand]] = false
+			if r.hand--; r.hand < 0 {
+				r.hand = len(r.keys) - 1
+			}
+		}
+		delete(r.visited, r.keys[r.hand])
+		r.keys = slices.Delete(r.keys, r.hand, r.hand+1)
+		r.hand-- // The newer neighbour, or the oldest if none.
+	}
+	r.keys = slices.Insert(r.keys, 0, key)
+	if r.hand >= 0 {
+		r.hand++
+	}
+}
+
+func model(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 8
+		universe = capacity * 3
+		length   = 1 << 14
+	)
+	var (
+		rng   = rand.New(rand.NewSource(1))
+		cache = newCache(t, capacity)
+		ref   = reference{
+			visited:  make(map[int]bool),
+			hand:     -1,
+			capacity: capacity,
+		}
+	)
+	for i := range length {
+		key := rng.Intn(universe)
+		_, got := cache.Get(key)
+		if want := ref.get(key); got != want {
+			t.Fatalf(
+				"residency of key %d differs from reference at access %d"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				key, i, got, want,
+			)
+		}
+		if !got {
+			cache.Set(key, key)
+			ref.set(key)
+		}
+		if cache.Len() != len(ref.keys) {
+			t.Fatalf("length differs from reference at access %d"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+				i, cache.Len(), len(ref.keys),
+			)
+		}
+	}
+}
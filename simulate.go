@@ -0,0 +1,58 @@
+package clockpro
+
+import "iter"
+
+// SimResult counts the outcomes of the accesses
+// of a trace, as simulated by [Simulate].
+type SimResult struct {
+	// Accesses counts the keys of the trace.
+	Accesses uint64
+	// Hits counts accesses of resident keys.
+	Hits uint64
+	// Misses counts accesses of nonresident keys,
+	// each of which was inserted.
+	Misses uint64
+	// Evictions counts resident keys that were
+	// evicted to make room for missed keys.
+	Evictions uint64
+	// Resurrections counts missed keys which
+	// were inserted again during their test period.
+	Resurrections uint64
+}
+
+// Simulate replays each key of trace against a cache of capacity,
+// as if it was looked up with [Cache.Get], and set with
+// [Cache.Set] if it missed, and returns the outcomes.
+// Values are not stored; the simulated cache is a [Tracker],
+// which holds only the metadata of its pages, so that large
+// traces may be simulated cheaply, such as to plan the
+// capacity of a cache.
+// The options are applied to the simulated cache.
+func Simulate[Key comparable](trace iter.Seq[Key], capacity int, options ...Option[Key, struct{}]) (SimResult, error) {
+	tracker, err := NewTracker(capacity, options...)
+	if err != nil {
+		return SimResult{}, err
+	}
+	var accesses uint64
+	for key := range trace {
+		accesses++
+		tracker.Access(key)
+	}
+	stats := tracker.cache.stats.total
+	return SimResult{
+		Accesses:      accesses,
+		Hits:          stats.hits,
+		Misses:        stats.misses,
+		Evictions:     stats.evictions,
+		Resurrections: stats.resurrections,
+	}, nil
+}
+
+// HitRatio returns the ratio of hits to accesses,
+// or 0 if there were no accesses.
+func (result SimResult) HitRatio() float64 {
+	if result.Accesses == 0 {
+		return 0
+	}
+	return float64(result.Hits) / float64(result.Accesses)
+}
@@ -0,0 +1,63 @@
+package clockpro_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+	"github.com/djdv/go-clockpro/workload"
+)
+
+func TestSimulate(t *testing.T) {
+	t.Parallel()
+	const capacity = 64
+	if _, err := clockpro.Simulate(slices.Values([]int{1}), 0); !errors.Is(err, clockpro.ErrInvalidCapacity) {
+		t.Errorf(
+			"expected error to match"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			err, clockpro.ErrInvalidCapacity,
+		)
+	}
+	keys := workload.Zipf(rngSeed, capacity*8, 1<<12, 1.2, 1)
+	result, err := clockpro.Simulate(slices.Values(keys), capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range keys {
+		if _, ok := cache.Get(key); !ok {
+			cache.Set(key, key)
+		}
+	}
+	var (
+		stats = cache.Stats()
+		want  = clockpro.SimResult{
+			Accesses:      uint64(len(keys)),
+			Hits:          stats.Hits,
+			Misses:        stats.Misses,
+			Evictions:     stats.Evictions,
+			Resurrections: stats.Resurrections,
+		}
+	)
+	if result != want {
+		t.Errorf(
+			"simulation differs from cache"+
+				"\n\tgot: %+v"+
+				"\n\twant: %+v",
+			result, want,
+		)
+	}
+	if got, want := result.HitRatio(), stats.HitRatio(); got != want {
+		t.Errorf(
+			"unexpected hit ratio"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			got, want,
+		)
+	}
+}
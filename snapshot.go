@@ -0,0 +1,183 @@
+package clockpro
+
+import (
+	"iter"
+	"maps"
+	"sync"
+)
+
+type (
+	// ReadOnlyView is an immutable view of the resident
+	// entries of a cache, taken by [Cache.Snapshot].
+	// Values are shared with the cache, not copied.
+	// Views are safe for concurrent use,
+	// including while the cache is modified.
+	ReadOnlyView[Key comparable, Value any] struct {
+		layers  []viewLayer[Key, Value] // One per partition.
+		decode  func(Value) Value
+		now     int64 // Entries which expire by now are excluded.
+		length  int
+		counter sync.Once
+	}
+	// viewLayer is the state of a cache's entries as of
+	// a snapshot: the entries of changes replace those of
+	// base, and a nil entry removes its key.
+	// Neither map is modified once it is shared by a view.
+	viewLayer[Key comparable, Value any] struct {
+		base, changes map[Key]*viewEntry[Key, Value]
+	}
+	viewEntry[Key comparable, Value any] struct {
+		value Value // Encoded, if the cache encodes values.
+		// deadline is the entry's expiration time
+		// in Unix nanoseconds, or 0 if it does not expire.
+		deadline int64
+	}
+	// viewLog records the modifications of
+	// resident entries made since the first snapshot.
+	viewLog[Key comparable, Value any] struct {
+		viewLayer[Key, Value]
+		// shared is set while changes is shared with a view,
+		// so that it is copied before it is modified.
+		shared bool
+	}
+)
+
+// Snapshot returns a view of the entries which are
+// resident and unexpired. The view shares the state of
+// the cache copy-on-write: the first snapshot of a cache
+// copies the keys of every resident entry, after which the
+// cache records the entries that it modifies, copying
+// the record only when it modifies an entry after
+// a snapshot shares it, and folding it into a new copy
+// of the keys once it exceeds the capacity of the cache.
+// Taking a snapshot does not count as an access.
+func (c *Cache[Key, Value]) Snapshot() *ReadOnlyView[Key, Value] {
+	if c.views == nil {
+		base := make(map[Key]*viewEntry[Key, Value], c.Len())
+		for page := range c.residents() {
+			base[page.Name] = c.viewEntry(page.Name, page.Value)
+		}
+		c.views = &viewLog[Key, Value]{
+			viewLayer: viewLayer[Key, Value]{
+				base:    base,
+				changes: make(map[Key]*viewEntry[Key, Value]),
+			},
+		}
+	}
+	c.views.shared = true
+	return &ReadOnlyView[Key, Value]{
+		layers: []viewLayer[Key, Value]{c.views.viewLayer},
+		decode: c.decode,
+		now:    c.now().UnixNano(),
+	}
+}
+
+func (c *Cache[Key, Value]) viewEntry(key Key, value Value) *viewEntry[Key, Value] {
+	deadline, _ := c.expiry.deadline(key)
+	return &viewEntry[Key, Value]{value: value, deadline: deadline}
+}
+
+// record replaces the entry of key for later snapshots,
+// or removes it if entry is nil.
+func (l *viewLog[Key, Value]) record(key Key, entry *viewEntry[Key, Value], capacity int) {
+	if l.shared {
+		l.changes = maps.Clone(l.changes)
+		l.shared = false
+	}
+	l.changes[key] = entry
+	if len(l.changes) <= capacity {
+		return
+	}
+	base := make(map[Key]*viewEntry[Key, Value], len(l.base))
+	for key, entry := range l.all() {
+		base[key] = entry
+	}
+	l.base, l.changes = base, make(map[Key]*viewEntry[Key, Value])
+}
+
+// all returns an iterator over the entries of the layer.
+func (l viewLayer[Key, Value]) all() iter.Seq2[Key, *viewEntry[Key, Value]] {
+	return func(yield func(Key, *viewEntry[Key, Value]) bool) {
+		for key, entry := range l.changes {
+			if entry != nil && !yield(key, entry) {
+				return
+			}
+		}
+		for key, entry := range l.base {
+			if _, changed := l.changes[key]; changed {
+				continue
+			}
+			if !yield(key, entry) {
+				return
+			}
+		}
+	}
+}
+
+func (l viewLayer[Key, Value]) get(key Key) (*viewEntry[Key, Value], bool) {
+	if entry, changed := l.changes[key]; changed {
+		return entry, entry != nil
+	}
+	entry, ok := l.base[key]
+	return entry, ok
+}
+
+// Get returns the value of key, if it was in the view.
+func (v *ReadOnlyView[Key, Value]) Get(key Key) (Value, bool) {
+	for _, layer := range v.layers {
+		if entry, ok := layer.get(key); ok && v.live(entry) {
+			return v.decoded(entry.value), true
+		}
+	}
+	var zero Value
+	return zero, false
+}
+
+// Len returns the number of entries in the view.
+// It is counted by the first call.
+func (v *ReadOnlyView[_, _]) Len() int {
+	v.counter.Do(func() {
+		for range v.All() {
+			v.length++
+		}
+	})
+	return v.length
+}
+
+// All returns an iterator over the entries
+// of the view, in no particular order.
+func (v *ReadOnlyView[Key, Value]) All() iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		for _, layer := range v.layers {
+			for key, entry := range layer.all() {
+				if v.live(entry) && !yield(key, v.decoded(entry.value)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (v *ReadOnlyView[Key, Value]) live(entry *viewEntry[Key, Value]) bool {
+	return entry.deadline == 0 || entry.deadline > v.now
+}
+
+func (v *ReadOnlyView[_, Value]) decoded(value Value) Value {
+	if v.decode != nil {
+		return v.decode(value)
+	}
+	return value
+}
+
+// joinViews returns a view of the entries
+// of each view in turn.
+func joinViews[Key comparable, Value any](views []*ReadOnlyView[Key, Value]) *ReadOnlyView[Key, Value] {
+	joined := &ReadOnlyView[Key, Value]{
+		decode: views[0].decode,
+		now:    views[0].now, // The earliest.
+	}
+	for _, view := range views {
+		joined.layers = append(joined.layers, view.layers...)
+	}
+	return joined
+}
@@ -0,0 +1,177 @@
+package clockpro_test
+
+import (
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Run("immutable", viewImmutable)
+	t.Run("copy on write", viewCopyOnWrite)
+	t.Run("expired", viewExpired)
+	t.Run("partitioned", viewPartitioned)
+	t.Run("concurrent", viewConcurrent)
+}
+
+func viewImmutable(t *testing.T) {
+	t.Parallel()
+	const capacity = 8
+	cache := newMergeCache(t, capacity)
+	addIncrementingInts(cache, capacity)
+	var (
+		view = cache.Snapshot()
+		want = maps.Collect(view.All())
+	)
+	cache.Purge(nil)
+	addIncrementingInts(cache, capacity/2)
+	cache.Set(1, -1)
+	if got := view.Len(); got != capacity {
+		t.Errorf(
+			"view length changed with the cache"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			got, capacity,
+		)
+	}
+	for key := 1; key <= capacity; key++ {
+		checkView(t, view, key, key, "view after purge")
+	}
+	if got := maps.Collect(view.All()); !maps.Equal(got, want) {
+		t.Errorf(
+			"view entries changed with the cache"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			got, want,
+		)
+	}
+}
+
+func viewCopyOnWrite(t *testing.T) {
+	t.Parallel()
+	const capacity = 8
+	cache := newMergeCache(t, capacity)
+	addIncrementingInts(cache, capacity)
+	var (
+		views []*clockpro.ReadOnlyView[int, int]
+		wants []map[int]int
+	)
+	// Enough modifications to fold the
+	// recorded changes into new copies.
+	for i := range capacity * 4 {
+		want := make(map[int]int)
+		cache.Range(func(key, value int) bool {
+			want[key] = value
+			return true
+		})
+		views = append(views, cache.Snapshot())
+		wants = append(wants, want)
+		if i%3 == 0 {
+			cache.Delete(i % capacity)
+		}
+		cache.Set(i%capacity, -i)
+	}
+	for i, view := range views {
+		if got, want := maps.Collect(view.All()), wants[i]; !maps.Equal(got, want) {
+			t.Errorf(
+				"snapshot %d changed with the cache"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				i, got, want,
+			)
+		}
+		if got, want := view.Len(), len(wants[i]); got != want {
+			t.Errorf(
+				"unexpected length of snapshot %d"+
+					"\n\tgot: %d"+
+					"\n\twant: %d",
+				i, got, want,
+			)
+		}
+	}
+}
+
+func viewExpired(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 4
+		ttl      = time.Second
+	)
+	cache, clock := newExpiringCache(t, capacity)
+	cache.Set(1, 1)
+	cache.SetWithTTL(2, 2, ttl)
+	clock.advance(ttl)
+	view := cache.Snapshot()
+	checkView(t, view, 1, 1, "unexpired entry")
+	if _, ok := view.Get(2); ok || view.Len() != 1 {
+		t.Error("view contains an expired entry")
+	}
+}
+
+func viewPartitioned(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 16
+		shards   = 4
+	)
+	for _, test := range []struct {
+		name  string
+		cache tortureCache
+	}{
+		{"synced", newSynced(t, capacity)},
+		{"sharded", newSharded(t, capacity, shards)},
+		{"striped", newStriped(t, capacity, shards)},
+		{"actor", newActor(t, capacity)},
+	} {
+		for key := range capacity * 2 {
+			test.cache.Set(key, key)
+		}
+		view := test.cache.Snapshot()
+		if got, want := view.Len(), test.cache.Len(); got != want {
+			t.Errorf(
+				"%s: view length differs from cache"+
+					"\n\tgot: %d"+
+					"\n\twant: %d",
+				test.name, got, want,
+			)
+		}
+		for key := range test.cache.Keys() {
+			checkView(t, view, key, key, test.name+" view")
+		}
+	}
+}
+
+func checkView(t *testing.T, view *clockpro.ReadOnlyView[int, int], key, want int, msg string) {
+	t.Helper()
+	if got, ok := view.Get(key); !ok || got != want {
+		t.Errorf(
+			"%s: unexpected value for key %d"+
+				"\n\tgot: %d, %t"+
+				"\n\twant: %d, true",
+			msg, key, got, ok, want,
+		)
+	}
+}
+
+func viewConcurrent(t *testing.T) {
+	t.Parallel()
+	const capacity = 64
+	cache := newSynced(t, capacity)
+	addIncrementingInts(cache, capacity)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range capacity * 16 {
+			cache.Set(i%(capacity*2), i)
+		}
+	}()
+	for range 16 {
+		view := cache.Snapshot()
+		for key, value := range view.All() {
+			checkView(t, view, key, value, "concurrent snapshot")
+		}
+	}
+	<-done
+}
@@ -0,0 +1,71 @@
+package clockpro
+
+import (
+	"context"
+	"fmt"
+	"hash/maphash"
+)
+
+type (
+	// LoadSpan is called by Load and LoadContext before the
+	// cache is consulted, such as to start a tracing span.
+	// It receives the context given to LoadContext
+	// (or the background context, for Load), the key,
+	// and its hash, of which the remainder by 64 is
+	// the bucket labeled by [WithProfileLabels].
+	// The context it returns is given to fetch,
+	// and end is called once Load returns, with whether
+	// the value was resident, and the error returned, if any.
+	LoadSpan[Key comparable] func(ctx context.Context, key Key, hash uint64) (_ context.Context, end func(hit bool, err error))
+	// fetcher holds the fetch function
+	// given to either Load or LoadContext.
+	fetcher[Value any] struct {
+		plain       func() (Value, error)
+		withContext func(context.Context) (Value, error)
+	}
+)
+
+// WithLoadSpans calls start for each call of Load
+// and LoadContext, so that distributed traces may show
+// whether latency came from the cache or its backend.
+// For example, with OpenTelemetry:
+//
+//	clockpro.WithLoadSpans[string, []byte](func(ctx context.Context, _ string, hash uint64) (context.Context, func(bool, error)) {
+//		ctx, span := tracer.Start(ctx, "cache.Load",
+//			trace.WithAttributes(attribute.Int64("cache.key_hash", int64(hash))))
+//		return ctx, func(hit bool, err error) {
+//			span.SetAttributes(attribute.Bool("cache.hit", hit))
+//			if err != nil {
+//				span.RecordError(err)
+//			}
+//			span.End()
+//		}
+//	})
+func WithLoadSpans[Key comparable, Value any](start LoadSpan[Key]) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if start == nil {
+			return fmt.Errorf(
+				"%w: load span function must not be nil",
+				ErrInvalidOption,
+			)
+		}
+		set.loadSpan = start
+		return nil
+	}
+}
+
+// startLoad calls the function given to [WithLoadSpans], if any.
+// If there is none, it returns ctx and a nil end function.
+func (set *settings[Key, Value]) startLoad(ctx context.Context, key Key) (context.Context, func(bool, error)) {
+	if set.loadSpan == nil {
+		return ctx, nil
+	}
+	return set.loadSpan(ctx, key, maphash.Comparable(profileSeed, key))
+}
+
+func (fetch fetcher[Value]) call(ctx context.Context) (Value, error) {
+	if fetch.withContext != nil {
+		return fetch.withContext(ctx)
+	}
+	return fetch.plain()
+}
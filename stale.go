@@ -0,0 +1,70 @@
+package clockpro
+
+import (
+	"context"
+	"time"
+)
+
+// LoadStale is like [Synced.LoadContext], but if fetch does not
+// return within timeout, or before ctx is done, and the cache holds
+// a stale value of key, the stale value is returned and stale is true.
+// The fetch continues in the background, with a context which
+// is not canceled along with ctx, and its value replaces the
+// stale value once it returns. Stale values are the values of
+// entries which were evicted or expired recently enough that
+// their test pages remain, and are only retained by caches
+// constructed with [WithRetainedValues], and without [WithFinalizer].
+// If there is no stale value, LoadStale waits for fetch like LoadContext.
+func (s *Synced[Key, Value]) LoadStale(ctx context.Context, key Key, timeout time.Duration,
+	fetch func(context.Context) (Value, error),
+) (value Value, stale bool, err error) {
+	if value, ok := s.Get(key); ok {
+		return value, false, nil
+	}
+	s.lock()
+	staleValue, ok := s.cache.stale(key)
+	s.mu.Unlock()
+	if !ok {
+		value, err = s.LoadContext(ctx, key, fetch)
+		return value, false, err
+	}
+	type result struct {
+		value Value
+		err   error
+	}
+	var (
+		done     = make(chan result, 1)
+		deadline = time.NewTimer(timeout)
+	)
+	defer deadline.Stop()
+	go func() {
+		value, err := s.LoadContext(context.WithoutCancel(ctx), key, fetch)
+		done <- result{value, err}
+	}()
+	select {
+	case fetched := <-done:
+		return fetched.value, false, fetched.err
+	case <-deadline.C:
+	case <-ctx.Done():
+	}
+	return staleValue, true, nil
+}
+
+// LoadStale is like [Synced.LoadStale].
+func (sc *Sharded[Key, Value]) LoadStale(ctx context.Context, key Key, timeout time.Duration,
+	fetch func(context.Context) (Value, error),
+) (Value, bool, error) {
+	return sc.shard(key).LoadStale(ctx, key, timeout, fetch)
+}
+
+// stale returns the value retained by the test page of key,
+// if values are retained and were not finalized.
+func (c *Cache[Key, Value]) stale(key Key) (Value, bool) {
+	page, ok := c.index.get(key)
+	if !ok || page.Resident ||
+		!c.retainValues || c.finalizer != nil {
+		var zero Value
+		return zero, false
+	}
+	return c.decoded(page.Value), true
+}
@@ -0,0 +1,303 @@
+package clockpro
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// Stats counts events that occurred in a [Cache]
+	// during the period starting at Since, and lasting for Elapsed.
+	Stats struct {
+		Since   time.Time
+		Elapsed time.Duration
+		// Hits counts lookups of resident pages.
+		Hits uint64
+		// Misses counts lookups of nonresident pages.
+		Misses uint64
+		// Evictions counts resident pages that were
+		// evicted to make room for new pages.
+		Evictions uint64
+		// Expirations counts resident pages that were
+		// evicted because their TTL elapsed.
+		Expirations uint64
+		// Rejections counts new keys which were
+		// denied admission. See [WithDoorkeeper].
+		Rejections uint64
+		// Resurrections counts nonresident pages
+		// which were inserted again during their test period.
+		Resurrections uint64
+		// Promotions counts cold pages which became hot.
+		Promotions uint64
+		// Demotions counts hot pages which became cold.
+		Demotions uint64
+		// TestRemovals counts nonresident test pages
+		// which were removed by the policy, because their
+		// test period ended, or to bound the metadata.
+		TestRemovals uint64
+		// RecentHitRatio is an exponentially weighted moving
+		// average of the hit ratio, in which the weight of each
+		// lookup decays by half over 1024 lookups.
+		// Unlike the counters, it is not relative to Since.
+		// Lookups served without locking the cache,
+		// by [Synced], [Striped], and [Actor], are included
+		// once the cache is next locked, as if they occurred
+		// then, spread evenly between hits and misses.
+		RecentHitRatio float64
+		// Replicated is the amount of keys which are
+		// replicated across the shards of a [Sharded] cache.
+		// Unlike the counters, it is not relative to Since.
+		// See [WithHotKeyReplication].
+		Replicated int
+		// EvictionAges counts the ages of evicted pages,
+		// measured in cache operations since the page
+		// was inserted or last referenced.
+		// Only populated if the cache was constructed
+		// with [WithEvictionAges].
+		EvictionAges Histogram
+		// ReuseDistances counts the (estimated) reuse distances
+		// of accesses; the amount of distinct keys accessed
+		// since the previous access of the same key.
+		// Only populated if the cache was constructed
+		// with [WithReuseDistances].
+		ReuseDistances Histogram
+		// GetLatencies, SetLatencies, and FetchLatencies
+		// count the durations of lookups, modifications,
+		// and fetches, in nanoseconds. Like RecentHitRatio,
+		// they are not relative to Since.
+		// Only populated if the cache was constructed
+		// with [WithLatencies].
+		GetLatencies, SetLatencies, FetchLatencies Histogram
+	}
+	counters struct {
+		evictionAges, reuseDistances Histogram
+		hits, misses,
+		evictions, expirations,
+		rejections, resurrections,
+		promotions, demotions,
+		testRemovals uint64
+	}
+	statistics struct {
+		created, resetAt time.Time
+		total, atReset   counters
+		recent           movingRatio
+	}
+	// movingRatio is an exponentially weighted
+	// moving average of boolean observations.
+	movingRatio struct {
+		sum, weight float64
+	}
+	// unlockedLookups counts lookups which were served
+	// without locking the cache, until its owner folds
+	// them into the cache's moving average.
+	unlockedLookups struct {
+		hits, misses             atomic.Uint64
+		foldedHits, foldedMisses uint64 // Owned.
+		// resetHits and resetMisses are the folded
+		// lookups as of the last call to ResetStats.
+		resetHits, resetMisses uint64 // Owned.
+	}
+)
+
+// recentHalfLife is the amount of lookups over which
+// their weight in [Stats.RecentHitRatio] decays by half.
+const recentHalfLife = 1024
+
+// Stats returns the cumulative statistics
+// of the cache since it was created.
+func (c *Cache[_, _]) Stats() Stats {
+	var zero counters
+	return c.withLatencies(c.stats.since(c.stats.created, c.now(), zero))
+}
+
+// IntervalStats returns the statistics
+// of the cache since it was last reset
+// by [Cache.ResetStats] (or since it was created).
+func (c *Cache[_, _]) IntervalStats() Stats {
+	return c.withLatencies(c.stats.since(c.stats.resetAt, c.now(), c.stats.atReset))
+}
+
+// ResetStats returns the same statistics
+// as [Cache.IntervalStats] and starts a new interval.
+// Cumulative statistics are not affected.
+func (c *Cache[_, _]) ResetStats() Stats {
+	var (
+		now      = c.now()
+		interval = c.stats.since(c.stats.resetAt, now, c.stats.atReset)
+	)
+	c.stats.resetAt = now
+	c.stats.atReset = c.stats.total
+	return c.withLatencies(interval)
+}
+
+// withLatencies returns stats with the latencies
+// of the cache, if they are recorded.
+func (c *Cache[_, _]) withLatencies(stats Stats) Stats {
+	if c.latencies != nil {
+		c.latencies.fill(&stats)
+	}
+	return stats
+}
+
+// HitRatio returns the ratio of hits to lookups,
+// or 0 if there were no lookups.
+func (st Stats) HitRatio() float64 {
+	lookups := st.Hits + st.Misses
+	if lookups == 0 {
+		return 0
+	}
+	return float64(st.Hits) / float64(lookups)
+}
+
+// PerSecond returns the average rate
+// of count per second over the elapsed period.
+func (st Stats) PerSecond(count uint64) float64 {
+	seconds := st.Elapsed.Seconds()
+	if seconds <= 0 {
+		return 0
+	}
+	return float64(count) / seconds
+}
+
+// WithEvictionAges records the age
+// of evicted pages in [Stats.EvictionAges].
+func WithEvictionAges[Key comparable, Value any]() Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		set.trackAges = true
+		return nil
+	}
+}
+
+func (stats *statistics) since(start, now time.Time, base counters) Stats {
+	total := stats.total
+	return Stats{
+		Since:          start,
+		Elapsed:        now.Sub(start),
+		Hits:           total.hits - base.hits,
+		Misses:         total.misses - base.misses,
+		Evictions:      total.evictions - base.evictions,
+		Expirations:    total.expirations - base.expirations,
+		Rejections:     total.rejections - base.rejections,
+		Resurrections:  total.resurrections - base.resurrections,
+		Promotions:     total.promotions - base.promotions,
+		Demotions:      total.demotions - base.demotions,
+		TestRemovals:   total.testRemovals - base.testRemovals,
+		RecentHitRatio: stats.recent.ratio(),
+		EvictionAges: total.evictionAges.sub(
+			&base.evictionAges,
+		),
+		ReuseDistances: total.reuseDistances.sub(
+			&base.reuseDistances,
+		),
+	}
+}
+
+// add accumulates the counters of other into st.
+// Recent hit ratios are weighted by lookups.
+func (st *Stats) add(other Stats) {
+	var (
+		lookups      = float64(st.Hits + st.Misses)
+		otherLookups = float64(other.Hits + other.Misses)
+	)
+	if total := lookups + otherLookups; total != 0 {
+		st.RecentHitRatio = (st.RecentHitRatio*lookups +
+			other.RecentHitRatio*otherLookups) / total
+	}
+	st.Hits += other.Hits
+	st.Misses += other.Misses
+	st.Evictions += other.Evictions
+	st.Expirations += other.Expirations
+	st.Rejections += other.Rejections
+	st.Resurrections += other.Resurrections
+	st.Promotions += other.Promotions
+	st.Demotions += other.Demotions
+	st.TestRemovals += other.TestRemovals
+	st.EvictionAges.merge(&other.EvictionAges)
+	st.ReuseDistances.merge(&other.ReuseDistances)
+	st.GetLatencies.merge(&other.GetLatencies)
+	st.SetLatencies.merge(&other.SetLatencies)
+	st.FetchLatencies.merge(&other.FetchLatencies)
+}
+
+// recentDecay is the factor by which the weight of previous
+// observations decays with each new observation.
+var recentDecay = math.Pow(0.5, 1.0/recentHalfLife)
+
+func (mr *movingRatio) observe(hit bool) {
+	mr.sum *= recentDecay
+	mr.weight = mr.weight*recentDecay + 1
+	if hit {
+		mr.sum++
+	}
+}
+
+// observeMany is like calling observe for each
+// of lookups observations, of which hits are true,
+// spread evenly among them.
+func (mr *movingRatio) observeMany(hits, lookups uint64) {
+	if lookups == 0 {
+		return
+	}
+	var (
+		decay  = math.Pow(recentDecay, float64(lookups))
+		weight = (1 - decay) / (1 - recentDecay)
+	)
+	mr.sum = mr.sum*decay + weight*float64(hits)/float64(lookups)
+	mr.weight = mr.weight*decay + weight
+}
+
+// fold observes the lookups counted since
+// the previous fold in recent.
+// The caller must own the cache.
+func (ul *unlockedLookups) fold(recent *movingRatio) {
+	var (
+		hits    = ul.hits.Load()
+		misses  = ul.misses.Load()
+		newHits = hits - ul.foldedHits
+	)
+	recent.observeMany(newHits, newHits+misses-ul.foldedMisses)
+	ul.foldedHits, ul.foldedMisses = hits, misses
+}
+
+// addTotal adds the folded lookups to the
+// cumulative statistics of the cache.
+func (ul *unlockedLookups) addTotal(stats *Stats) {
+	stats.Hits += ul.foldedHits
+	stats.Misses += ul.foldedMisses
+}
+
+// addInterval adds the lookups folded since
+// the last reset to the interval statistics of the cache.
+func (ul *unlockedLookups) addInterval(stats *Stats) {
+	stats.Hits += ul.foldedHits - ul.resetHits
+	stats.Misses += ul.foldedMisses - ul.resetMisses
+}
+
+// reset starts a new interval, after its
+// statistics were returned by ResetStats.
+func (ul *unlockedLookups) reset() {
+	ul.resetHits, ul.resetMisses = ul.foldedHits, ul.foldedMisses
+}
+
+// ratio returns the average, corrected for the
+// bias towards 0 of the first observations.
+func (mr *movingRatio) ratio() float64 {
+	if mr.weight == 0 {
+		return 0
+	}
+	return mr.sum / mr.weight
+}
+
+func (c *Cache[Key, Value]) touch(page *page[Key, Value]) {
+	if c.trackAges {
+		page.Accessed = c.operations
+	}
+}
+
+func (c *Cache[Key, Value]) recordEvictionAge(page *page[Key, Value]) {
+	if c.trackAges {
+		age := c.operations - page.Accessed
+		c.stats.total.evictionAges.add(age)
+	}
+}
@@ -0,0 +1,264 @@
+package clockpro_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 2
+		interval = time.Second
+		ttl      = interval / 2
+	)
+	cache, clock := newExpiringCache(t, capacity)
+	addIncrementingInts(cache, capacity)
+	mustGet(t, cache, 1)
+	mustMiss(t, cache, 3, "never set")
+	cache.Set(3, 3) // Evicts 2 (cold).
+	clock.advance(interval)
+	interval1 := cache.ResetStats()
+	checkCount(t, "interval hits", interval1.Hits, 1)
+	checkCount(t, "interval misses", interval1.Misses, 1)
+	checkCount(t, "interval evictions", interval1.Evictions, 1)
+	if got := interval1.PerSecond(interval1.Hits); got != 1 {
+		t.Errorf("expected 1 hit per second, got: %f", got)
+	}
+	cache.SetWithTTL(1, 1, ttl)
+	clock.advance(ttl)
+	mustMiss(t, cache, 1, "expiration")
+	interval2 := cache.IntervalStats()
+	checkCount(t, "interval hits after reset", interval2.Hits, 0)
+	checkCount(t, "interval misses after reset", interval2.Misses, 1)
+	checkCount(t, "interval expirations", interval2.Expirations, 1)
+	total := cache.Stats()
+	checkCount(t, "total hits", total.Hits, 1)
+	checkCount(t, "total misses", total.Misses, 2)
+	if got, want := total.Elapsed, interval+ttl; got != want {
+		t.Errorf(
+			"unexpected elapsed time"+
+				"\n\tgot: %s"+
+				"\n\twant: %s",
+			got, want)
+	}
+	if got, want := total.HitRatio(), 1.0/3.0; got != want {
+		t.Errorf(
+			"unexpected hit ratio"+
+				"\n\tgot: %f"+
+				"\n\twant: %f",
+			got, want)
+	}
+}
+
+func TestRecentHitRatio(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 8
+		halfLife = 1024
+	)
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addIncrementingInts(cache, capacity)
+	mustGet(t, cache, 1)
+	checkRatio(t, "after first hit", cache.Stats().RecentHitRatio, 1)
+	for i := range halfLife * 2 {
+		mustGet(t, cache, i%capacity+1)
+	}
+	for i := range halfLife {
+		mustMiss(t, cache, capacity+1+i, "never set")
+	}
+	// The hits weigh half as much as the misses,
+	// less the portion that decayed before them.
+	var (
+		hits   = 0.5 * 0.75
+		misses = 0.5
+	)
+	checkRatio(t, "after a half-life of misses",
+		cache.Stats().RecentHitRatio, hits/(hits+misses))
+}
+
+type statsCache interface {
+	testCache[int, int]
+	Flush()
+	Stats() clockpro.Stats
+	IntervalStats() clockpro.Stats
+	ResetStats() clockpro.Stats
+}
+
+func TestRecentHitRatioUnlocked(t *testing.T) {
+	t.Parallel()
+	const capacity = 64
+	// Partitions average their ratios over their own lookups,
+	// so a single partition is compared with [TestRecentHitRatio].
+	for _, test := range []struct {
+		name  string
+		cache statsCache
+	}{
+		{"synced", newSynced(t, capacity)},
+		{"sharded", newSharded(t, capacity, 1,
+			clockpro.WithHotKeyReplication[int, int](0.1),
+		)},
+		{"striped", newStriped(t, capacity, 1)},
+		{"actor", newActor(t, capacity)},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			recentHitRatioUnlocked(t, test.cache)
+		})
+	}
+}
+
+func TestIntervalStatsUnlocked(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 64
+		shards   = 4
+	)
+	for _, test := range []struct {
+		name  string
+		cache statsCache
+	}{
+		{"synced", newSynced(t, capacity)},
+		{"sharded", newSharded(t, capacity, shards,
+			clockpro.WithHotKeyReplication[int, int](0.1),
+		)},
+		{"striped", newStriped(t, capacity, shards)},
+		{"actor", newActor(t, capacity)},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			intervalStatsUnlocked(t, test.cache)
+		})
+	}
+}
+
+// intervalStatsUnlocked expects lookups served
+// without the lock to be counted by the interval
+// in which they occurred.
+func intervalStatsUnlocked(t *testing.T, cache statsCache) {
+	const keys = 8
+	for key := range keys {
+		cache.Set(key, key)
+	}
+	cache.Flush()
+	for key := range keys {
+		mustGet(t, cache, key)
+	}
+	first := cache.ResetStats()
+	checkCount(t, "first interval hits", first.Hits, keys)
+	for key := range keys / 2 {
+		mustGet(t, cache, key)
+	}
+	mustMiss(t, cache, keys, "never set")
+	second := cache.IntervalStats()
+	checkCount(t, "second interval hits", second.Hits, keys/2)
+	checkCount(t, "second interval misses", second.Misses, 1)
+	total := cache.Stats()
+	checkCount(t, "total hits", total.Hits, keys+keys/2)
+	checkCount(t, "total misses", total.Misses, 1)
+	reset := cache.ResetStats()
+	checkCount(t, "reset hits", reset.Hits, keys/2)
+	if stats := cache.IntervalStats(); stats.Hits+stats.Misses != 0 {
+		t.Errorf(
+			"lookups were counted after a reset"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			stats.Hits+stats.Misses, 0,
+		)
+	}
+}
+
+// recentHitRatioUnlocked expects the moving average of
+// lookups served without the lock to match
+// the average of [TestRecentHitRatio].
+func recentHitRatioUnlocked(t *testing.T, cache statsCache) {
+	const (
+		keys     = 8
+		halfLife = 1024
+	)
+	for key := range keys {
+		cache.Set(key, key)
+	}
+	cache.Flush()
+	for i := range halfLife * 2 {
+		mustGet(t, cache, i%keys)
+	}
+	cache.Flush() // Observes the hits, in case misses do not lock.
+	for i := range halfLife {
+		mustMiss(t, cache, keys+i, "never set")
+	}
+	var (
+		hits   = 0.5 * 0.75
+		misses = 0.5
+		stats  = cache.Stats()
+	)
+	checkCount(t, "hits", stats.Hits, halfLife*2)
+	checkRatio(t, "after a half-life of misses",
+		stats.RecentHitRatio, hits/(hits+misses))
+}
+
+func TestLatencies(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 4
+		delay    = time.Millisecond
+	)
+	sharded := newSharded(t, capacity*2, 2,
+		clockpro.WithLatencies[int, int](),
+	)
+	for key := range capacity {
+		sharded.Set(key, key)
+		mustGet(t, sharded, key)
+	}
+	if _, err := sharded.Load(capacity, func() (int, error) {
+		time.Sleep(delay)
+		return capacity, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	stats := sharded.Stats()
+	checkCount(t, "timed sets", stats.SetLatencies.Count(), capacity)
+	checkCount(t, "timed fetches", stats.FetchLatencies.Count(), 1)
+	if got := stats.FetchLatencies.Quantile(1); got < uint64(delay) {
+		t.Errorf(
+			"fetch latency is shorter than its delay"+
+				"\n\tgot: %v"+
+				"\n\twant: >=%v",
+			time.Duration(got), delay)
+	}
+	// Hits without the lock are not timed, but the
+	// lookup of the Load's key missed with it.
+	if got := stats.GetLatencies.Count(); got == 0 {
+		t.Error("lookups were not timed")
+	}
+}
+
+func checkRatio(tb testing.TB, name string, got, want float64) {
+	tb.Helper()
+	const tolerance = 0.001
+	if math.Abs(got-want) > tolerance {
+		tb.Errorf(
+			"unexpected recent hit ratio %s"+
+				"\n\tgot: %f"+
+				"\n\twant: %f",
+			name, got, want,
+		)
+	}
+}
+
+func checkCount(tb testing.TB, name string, got, want uint64) {
+	tb.Helper()
+	if got != want {
+		tb.Errorf(
+			"unexpected %s"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			name, got, want)
+	}
+}
@@ -0,0 +1,39 @@
+// Package strcache provides a cache with string keys,
+// specialized for throughput.
+//
+// Pages are indexed by an open-addressing table which retains
+// the hash of each key (see [clockpro.WithStringIndex]),
+// rather than by a map, so that lookups compare the hashes
+// of keys before their contents, and the index grows
+// without hashing keys again.
+// [Cache] has the method set of a [clockpro.Cache] with
+// string keys, so that either may be used through
+// an interface of the methods which a caller uses.
+package strcache
+
+import "github.com/djdv/go-clockpro"
+
+type (
+	// Cache is a [clockpro.Cache] with string keys,
+	// whose pages are indexed by their precomputed hashes.
+	// Constructed by [New].
+	Cache[Value any] struct {
+		*clockpro.Cache[string, Value]
+	}
+	// Option is a [clockpro.Option] for a [Cache].
+	Option[Value any] = clockpro.Option[string, Value]
+)
+
+// New is like [clockpro.New], but the index of the cache
+// retains the hashes of its keys,
+// as if [clockpro.WithStringIndex] was provided.
+func New[Value any](capacity int, options ...Option[Value]) (*Cache[Value], error) {
+	options = append(options[:len(options):len(options)],
+		clockpro.WithStringIndex[string, Value](),
+	)
+	cache, err := clockpro.New(capacity, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &Cache[Value]{Cache: cache}, nil
+}
@@ -0,0 +1,117 @@
+package strcache_test
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+	"github.com/djdv/go-clockpro/strcache"
+)
+
+// cache is implemented by both [strcache.Cache]
+// and [clockpro.Cache] with string keys.
+type cache interface {
+	Get(string) (int, bool)
+	Set(string, int)
+	Delete(string) bool
+	Len() int
+	CheckInvariants() error
+}
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+	const capacity = 64
+	specialized, err := strcache.New(capacity,
+		clockpro.WithSecondChances[string, int](1),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	generic, err := clockpro.New(capacity,
+		clockpro.WithSecondChances[string, int](1),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cache := range []cache{specialized, generic} {
+		exercise(t, cache)
+	}
+	if got, want := specialized.Stats(), generic.Stats(); got.Hits != want.Hits ||
+		got.Misses != want.Misses {
+		t.Errorf(
+			"specialized cache diverged from the generic cache"+
+				"\n\tgot: %d hits, %d misses"+
+				"\n\twant: %d hits, %d misses",
+			got.Hits, got.Misses, want.Hits, want.Misses)
+	}
+}
+
+// exercise inserts more keys than the cache holds,
+// deleting some of them, and checks the values
+// of the keys which remain resident.
+func exercise(t *testing.T, cache cache) {
+	t.Helper()
+	for i := range 1024 {
+		cache.Set(strconv.Itoa(i), i)
+		if i%3 == 0 {
+			cache.Delete(strconv.Itoa(i - 1))
+		}
+		cache.Get(strconv.Itoa(i / 2))
+		if err := cache.CheckInvariants(); err != nil {
+			t.Fatalf("%T: %v", cache, err)
+		}
+	}
+	var resident int
+	for i := range 1024 {
+		value, ok := cache.Get(strconv.Itoa(i))
+		if !ok {
+			continue
+		}
+		if resident++; value != i {
+			t.Errorf(
+				"%T: unexpected value for key %d"+
+					"\n\tgot: %d"+
+					"\n\twant: %d",
+				cache, i, value, i,
+			)
+		}
+	}
+	if resident != cache.Len() {
+		t.Errorf("%T: %d keys were resident but the cache holds %d",
+			cache, resident, cache.Len())
+	}
+}
+
+func BenchmarkGet(b *testing.B) {
+	for _, capacity := range []int{1 << 10, 1 << 18} {
+		for _, constructor := range []struct {
+			name string
+			new  func(int) (cache, error)
+		}{
+			{"generic", func(capacity int) (cache, error) {
+				return clockpro.New[string, int](capacity)
+			}},
+			{"strcache", func(capacity int) (cache, error) {
+				return strcache.New[int](capacity)
+			}},
+		} {
+			name := fmt.Sprintf("Cap%d/%s", capacity, constructor.name)
+			b.Run(name, func(b *testing.B) {
+				cache, err := constructor.new(capacity)
+				if err != nil {
+					b.Fatal(err)
+				}
+				keys := make([]string, capacity)
+				for i := range keys {
+					keys[i] = "key/" + strconv.Itoa(i)
+					cache.Set(keys[i], i)
+				}
+				b.ResetTimer()
+				for i := 0; b.Loop(); i++ {
+					cache.Get(keys[i%capacity])
+				}
+			})
+		}
+	}
+}
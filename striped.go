@@ -0,0 +1,402 @@
+package clockpro
+
+import (
+	"context"
+	"iter"
+	"math/rand/v2"
+	"runtime"
+	"sync"
+	"time"
+)
+
+type (
+	// Striped partitions keys across multiple [Cache] stripes,
+	// each guarded by a [sync.RWMutex], for read-mostly workloads.
+	// Constructed by [NewStriped].
+	//
+	// Hits only acquire a stripe's read lock.
+	// References are recorded in buffers spread across
+	// processors, and applied to the policy in batches,
+	// while the write lock is held for a miss or modification.
+	// References may be dropped if readers outpace writers;
+	// a stripe which is not modified does not need them.
+	Striped[Key comparable, Value any] struct {
+		stripes []*stripe[Key, Value]
+		hash    func(Key) uint64
+	}
+	stripe[Key comparable, Value any] struct {
+		cache   *Cache[Key, Value]
+		buffers []referenceBuffer[Key]
+		pending chan []Key
+		lookups unlockedLookups
+		mu      sync.RWMutex
+	}
+	referenceBuffer[Key comparable] struct {
+		keys []Key
+		mu   sync.Mutex
+	}
+)
+
+const (
+	// referenceBufferSize is the number of references
+	// recorded by a reader before they are submitted.
+	referenceBufferSize = 64
+	// pendingBuffers is the number of submitted buffers
+	// that may be waiting for a stripe's write lock.
+	pendingBuffers = 16
+)
+
+// NewStriped constructs a [Striped] cache,
+// dividing capacity evenly between stripes.
+// Restrictions are the same as [NewSharded].
+func NewStriped[Key comparable, Value any](capacity, stripes int, options ...Option[Key, Value]) (*Striped[Key, Value], error) {
+	partitions, hash, err := partition(capacity, stripes, options, newStripe[Key, Value])
+	if err != nil {
+		return nil, err
+	}
+	return &Striped[Key, Value]{
+		stripes: partitions,
+		hash:    hash,
+	}, nil
+}
+
+func newStripe[Key comparable, Value any](capacity int, options ...Option[Key, Value]) (*stripe[Key, Value], error) {
+	cache, err := New(capacity, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &stripe[Key, Value]{
+		cache:   cache,
+		buffers: make([]referenceBuffer[Key], runtime.GOMAXPROCS(0)),
+		pending: make(chan []Key, pendingBuffers),
+	}, nil
+}
+
+func (sc *Striped[Key, Value]) stripe(key Key) *stripe[Key, Value] {
+	return sc.stripes[sc.stripeIndex(key)]
+}
+
+func (sc *Striped[Key, _]) stripeIndex(key Key) int {
+	return int(sc.hash(key) % uint64(len(sc.stripes)))
+}
+
+// Get is like [Cache.Get].
+func (sc *Striped[Key, Value]) Get(key Key) (Value, bool) {
+	st := sc.stripe(key)
+	if value, ok := st.lookup(key); ok {
+		return value, true
+	}
+	st.lock()
+	defer st.mu.Unlock()
+	return st.cache.Get(key)
+}
+
+// GetOrSet is like [Cache.GetOrSet].
+func (sc *Striped[Key, Value]) GetOrSet(key Key, value Value) (actual Value, loaded bool) {
+	st := sc.stripe(key)
+	if actual, ok := st.lookup(key); ok {
+		return actual, true
+	}
+	st.lock()
+	defer st.mu.Unlock()
+	return st.cache.GetOrSet(key, value)
+}
+
+// SetIf is like [Synced.SetIf].
+func (sc *Striped[Key, Value]) SetIf(key Key, value Value, cond func(old Value, exists bool) bool) bool {
+	st := sc.stripe(key)
+	st.lock()
+	defer st.mu.Unlock()
+	return st.cache.SetIf(key, value, cond)
+}
+
+// Compute is like [Synced.Compute].
+func (sc *Striped[Key, Value]) Compute(key Key, f func(old Value, exists bool) (value Value, keep bool)) (Value, bool) {
+	st := sc.stripe(key)
+	st.lock()
+	defer st.mu.Unlock()
+	return st.cache.Compute(key, f)
+}
+
+// Mutate is like [Synced.Mutate].
+func (sc *Striped[Key, Value]) Mutate(key Key, mutate func(*Value)) bool {
+	st := sc.stripe(key)
+	st.lock()
+	defer st.mu.Unlock()
+	return st.cache.Mutate(key, mutate)
+}
+
+// Load is like [Synced.Load].
+func (sc *Striped[Key, Value]) Load(key Key, fetch func() (Value, error)) (Value, error) {
+	return sc.load(context.Background(), key, fetcher[Value]{plain: fetch})
+}
+
+// LoadContext is like [Synced.LoadContext].
+func (sc *Striped[Key, Value]) LoadContext(ctx context.Context, key Key, fetch func(context.Context) (Value, error)) (Value, error) {
+	return sc.load(ctx, key, fetcher[Value]{withContext: fetch})
+}
+
+func (sc *Striped[Key, Value]) load(ctx context.Context, key Key, fetch fetcher[Value]) (value Value, err error) {
+	st := sc.stripe(key)
+	ctx, end := st.cache.startLoad(ctx, key)
+	var hit bool
+	if end != nil {
+		defer func() { end(hit, err) }()
+	}
+	if value, hit = sc.Get(key); hit {
+		return value, nil
+	}
+	if value, err = st.cache.fetch(ctx, key, fetch); err != nil {
+		return value, fetchError(err)
+	}
+	st.lock()
+	defer st.mu.Unlock()
+	actual, _ := st.cache.setIfAbsent(key, value)
+	return actual, nil
+}
+
+// Set is like [Cache.Set].
+func (sc *Striped[Key, Value]) Set(key Key, value Value) {
+	st := sc.stripe(key)
+	st.lock()
+	defer st.mu.Unlock()
+	st.cache.Set(key, value)
+}
+
+// SetWithTTL is like [Cache.SetWithTTL].
+func (sc *Striped[Key, Value]) SetWithTTL(key Key, value Value, ttl time.Duration) {
+	st := sc.stripe(key)
+	st.lock()
+	defer st.mu.Unlock()
+	st.cache.SetWithTTL(key, value, ttl)
+}
+
+// Delete is like [Cache.Delete].
+func (sc *Striped[Key, Value]) Delete(key Key) bool {
+	st := sc.stripe(key)
+	st.lock()
+	defer st.mu.Unlock()
+	return st.cache.Delete(key)
+}
+
+// Remove is like [Cache.Remove].
+func (sc *Striped[Key, Value]) Remove(key Key) (Value, bool) {
+	st := sc.stripe(key)
+	st.lock()
+	defer st.mu.Unlock()
+	return st.cache.Remove(key)
+}
+
+// Apply is like [Sharded.Apply].
+func (sc *Striped[Key, Value]) Apply(ops []Op[Key, Value]) []OpResult[Value] {
+	return applyPartitioned(ops, len(sc.stripes), sc.stripeIndex,
+		func(stripe int, ops []Op[Key, Value]) []OpResult[Value] {
+			st := sc.stripes[stripe]
+			st.lock()
+			defer st.mu.Unlock()
+			return st.cache.Apply(ops)
+		})
+}
+
+// Purge is like [Synced.Purge], applied to each stripe in turn.
+func (sc *Striped[Key, Value]) Purge(onPurge func(Key, Value)) {
+	for _, st := range sc.stripes {
+		st.lock()
+		st.cache.Purge(onPurge)
+		st.mu.Unlock()
+	}
+}
+
+// Flush is like [Cache.Flush], applied to each stripe in turn.
+func (sc *Striped[_, _]) Flush() {
+	for _, st := range sc.stripes {
+		st.cache.Flush()
+	}
+}
+
+// CheckInvariants is like [Cache.CheckInvariants],
+// applied to each stripe in turn.
+func (sc *Striped[Key, Value]) CheckInvariants() error {
+	return checkPartitions("stripe", sc.stripes, func(st *stripe[Key, Value]) error {
+		st.lock()
+		defer st.mu.Unlock()
+		return st.cache.CheckInvariants()
+	})
+}
+
+// Len is like [Sharded.Len].
+func (sc *Striped[_, _]) Len() int {
+	var length int
+	for _, st := range sc.stripes {
+		st.mu.RLock()
+		length += st.cache.Len()
+		st.mu.RUnlock()
+	}
+	return length
+}
+
+// ResidentHot is like [Sharded.ResidentHot].
+func (sc *Striped[Key, Value]) ResidentHot() int {
+	return sc.sum((*Cache[Key, Value]).ResidentHot)
+}
+
+// ResidentCold is like [Sharded.ResidentCold].
+func (sc *Striped[Key, Value]) ResidentCold() int {
+	return sc.sum((*Cache[Key, Value]).ResidentCold)
+}
+
+// NonResident is like [Sharded.NonResident].
+func (sc *Striped[Key, Value]) NonResident() int {
+	return sc.sum((*Cache[Key, Value]).NonResident)
+}
+
+// sum returns the sum of count for the cache of each stripe.
+func (sc *Striped[Key, Value]) sum(count func(*Cache[Key, Value]) int) int {
+	var total int
+	for _, st := range sc.stripes {
+		st.mu.RLock()
+		total += count(st.cache)
+		st.mu.RUnlock()
+	}
+	return total
+}
+
+// Snapshot is like [Sharded.Snapshot].
+func (sc *Striped[Key, Value]) Snapshot() *ReadOnlyView[Key, Value] {
+	views := make([]*ReadOnlyView[Key, Value], len(sc.stripes))
+	for i, st := range sc.stripes {
+		st.lock()
+		views[i] = st.cache.Snapshot()
+		st.mu.Unlock()
+	}
+	return joinViews(views)
+}
+
+// Keys is like [Sharded.Keys].
+func (sc *Striped[Key, _]) Keys() iter.Seq[Key] {
+	return func(yield func(Key) bool) {
+		for _, st := range sc.stripes {
+			var keys []Key
+			st.mu.RLock()
+			for key := range st.cache.Keys() {
+				keys = append(keys, key)
+			}
+			st.mu.RUnlock()
+			for _, key := range keys {
+				if !yield(key) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Range is like [Sharded.Range].
+func (sc *Striped[Key, Value]) Range(f func(Key, Value) bool) {
+	for _, st := range sc.stripes {
+		st.mu.RLock()
+		keys, values := collectEntries(st.cache, nil)
+		st.mu.RUnlock()
+		if !rangeEntries(keys, values, f) {
+			return
+		}
+	}
+}
+
+// Stats is like [Sharded.Stats].
+func (sc *Striped[Key, Value]) Stats() Stats {
+	return sc.sumStats(func(st *stripe[Key, Value]) Stats {
+		stats := st.cache.Stats()
+		st.lookups.addTotal(&stats)
+		return stats
+	})
+}
+
+// IntervalStats is like [Sharded.IntervalStats].
+func (sc *Striped[Key, Value]) IntervalStats() Stats {
+	return sc.sumStats(func(st *stripe[Key, Value]) Stats {
+		stats := st.cache.IntervalStats()
+		st.lookups.addInterval(&stats)
+		return stats
+	})
+}
+
+// ResetStats is like [Sharded.ResetStats].
+func (sc *Striped[Key, Value]) ResetStats() Stats {
+	return sc.sumStats(func(st *stripe[Key, Value]) Stats {
+		stats := st.cache.ResetStats()
+		st.lookups.addInterval(&stats)
+		st.lookups.reset()
+		return stats
+	})
+}
+
+// sumStats returns the sum of the statistics
+// returned by stripeStats for each stripe,
+// while holding the stripe's lock.
+func (sc *Striped[Key, Value]) sumStats(stripeStats func(*stripe[Key, Value]) Stats) Stats {
+	var stats Stats
+	for i, st := range sc.stripes {
+		st.lock()
+		partial := stripeStats(st)
+		st.mu.Unlock()
+		if i == 0 {
+			stats.Since, stats.Elapsed = partial.Since, partial.Elapsed
+		}
+		stats.add(partial)
+	}
+	return stats
+}
+
+// lookup returns the value of key if it is resident,
+// recording the reference for later.
+func (st *stripe[Key, Value]) lookup(key Key) (Value, bool) {
+	st.mu.RLock()
+	value, ok := st.cache.peek(key)
+	st.mu.RUnlock()
+	if !ok {
+		return value, false
+	}
+	st.lookups.hits.Add(1)
+	st.record(key)
+	return value, true
+}
+
+// record buffers a reference to key.
+// References are dropped rather than waiting
+// for a contended buffer or for writers.
+func (st *stripe[Key, _]) record(key Key) {
+	buffer := &st.buffers[rand.N(len(st.buffers))]
+	if !buffer.mu.TryLock() {
+		return
+	}
+	defer buffer.mu.Unlock()
+	buffer.keys = append(buffer.keys, key)
+	if len(buffer.keys) < referenceBufferSize {
+		return
+	}
+	select {
+	case st.pending <- buffer.keys:
+		buffer.keys = make([]Key, 0, referenceBufferSize)
+	default:
+		buffer.keys = buffer.keys[:0]
+	}
+}
+
+// lock acquires the write lock, observes the lookups
+// served without it, and applies any pending
+// references to the policy.
+func (st *stripe[_, _]) lock() {
+	st.mu.Lock()
+	st.lookups.fold(&st.cache.stats.recent)
+	for {
+		select {
+		case keys := <-st.pending:
+			for _, key := range keys {
+				st.cache.reference(key)
+			}
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package clockpro_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestStriped(t *testing.T) {
+	t.Run("invalid", stripedInvalid)
+	t.Run("deferred references", stripedDeferredReferences)
+	t.Run("stats", stripedStats)
+	t.Run("concurrent", stripedConcurrent)
+}
+
+func newStriped(tb testing.TB, capacity, stripes int) *clockpro.Striped[int, int] {
+	tb.Helper()
+	cache, err := clockpro.NewStriped[int, int](capacity, stripes)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return cache
+}
+
+func stripedInvalid(t *testing.T) {
+	t.Parallel()
+	const capacity = 8
+	cache, err := clockpro.NewStriped[int, int](capacity, capacity)
+	if cache != nil || !errors.Is(err, clockpro.ErrInvalidCapacity) {
+		t.Errorf(
+			"unexpected error"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			err, clockpro.ErrInvalidCapacity,
+		)
+	}
+}
+
+func stripedDeferredReferences(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 4
+		coldKey  = capacity - 1
+		newKey   = capacity
+		// Enough hits to fill at least one
+		// reference buffer on typical machines.
+		hits = 1 << 14
+	)
+	cache := newStriped(t, capacity, 1)
+	for key := range capacity {
+		cache.Set(key, key)
+	}
+	for range hits {
+		mustGet(t, cache, coldKey)
+	}
+	cache.Set(newKey, newKey)
+	if !slices.Contains(slices.Collect(cache.Keys()), coldKey) {
+		t.Errorf(
+			"referenced cold key was evicted"+
+				"\n\tgot: %v"+
+				"\n\twant: %d to be resident",
+			slices.Sorted(cache.Keys()), coldKey,
+		)
+	}
+}
+
+func stripedStats(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 16
+		stripes  = 4
+		keys     = 8
+	)
+	cache := newStriped(t, capacity, stripes)
+	for key := range keys {
+		mustMiss(t, cache, key, "empty cache")
+		cache.Set(key, key)
+		mustGet(t, cache, key)
+	}
+	stats := cache.Stats()
+	if stats.Hits != keys || stats.Misses != keys {
+		t.Errorf(
+			"unexpected hits and misses"+
+				"\n\tgot: %d, %d"+
+				"\n\twant: %d, %d",
+			stats.Hits, stats.Misses, keys, keys,
+		)
+	}
+}
+
+func stripedConcurrent(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity   = 64
+		stripes    = 4
+		upperBound = capacity * 2
+		workers    = 8
+		accesses   = 4096
+	)
+	cache := newStriped(t, capacity, stripes)
+	exerciseConcurrently(t, cache, upperBound, workers, accesses)
+	if length := cache.Len(); length > capacity {
+		t.Errorf(
+			"cache exceeded capacity"+
+				"\n\tgot: %d"+
+				"\n\twant: <=%d",
+			length, capacity,
+		)
+	}
+}
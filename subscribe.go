@@ -0,0 +1,46 @@
+package clockpro
+
+import "sync"
+
+// Subscribe calls [Synced.Invalidate] for each key
+// received from invalidations, from a new goroutine,
+// until invalidations is closed or stop is called.
+// It is intended to connect the cache to a source of
+// remote invalidations, such as a message broker or
+// a database change stream, so that the instances of
+// a cache across processes do not serve values which
+// were modified elsewhere. Sources which deliver messages
+// to a callback may call [Synced.Invalidate] directly.
+func (s *Synced[Key, _]) Subscribe(invalidations <-chan Key) (stop func()) {
+	return subscribe(invalidations, s.Invalidate)
+}
+
+// Subscribe is like [Synced.Subscribe],
+// calling [Sharded.Invalidate].
+func (sc *Sharded[Key, _]) Subscribe(invalidations <-chan Key) (stop func()) {
+	return subscribe(invalidations, sc.Invalidate)
+}
+
+// subscribe calls invalidate with each key
+// received from invalidations, from a new goroutine,
+// until invalidations is closed or stop is called.
+func subscribe[Key comparable](invalidations <-chan Key, invalidate func(Key) bool) (stop func()) {
+	var (
+		done = make(chan struct{})
+		once sync.Once
+	)
+	go func() {
+		for {
+			select {
+			case key, ok := <-invalidations:
+				if !ok {
+					return
+				}
+				invalidate(key)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() { once.Do(func() { close(done) }) }
+}
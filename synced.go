@@ -0,0 +1,743 @@
+package clockpro
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// Synced wraps a [Cache] for concurrent use.
+	// Hits are served from a concurrent index
+	// without acquiring the cache's lock;
+	// the lock is only held for misses and modifications.
+	// Constructed by [NewSynced].
+	//
+	// Hits served without the lock only set the page's
+	// reference bit; they are counted in [Synced.Stats],
+	// but are not sampled for reuse distances, nor recorded.
+	//
+	// If constructed with [WithWriteBuffer], modifications
+	// made by [Synced.Set] and [Synced.SetWithTTL] are applied
+	// asynchronously, and if constructed with [WithWriteBuffer]
+	// or [WithSoftWatermark], the cache should be closed
+	// by [Synced.Close] when it is no longer needed.
+	Synced[Key comparable, Value any] struct {
+		cache   *Cache[Key, Value]
+		entries sync.Map             // Key -> *syncedEntry[Key, Value].
+		hot     *hotKeys[Key, Value] // Shared by a [Sharded] cache.
+		writes  chan syncedWrite[Key, Value]
+		notify  chan struct{}
+		trim    chan struct{}
+		stop    chan struct{}
+		workers sync.WaitGroup
+		closer  sync.Once
+		lookups unlockedLookups
+		mu      sync.Mutex
+		closed  atomic.Bool
+	}
+	syncedWrite[Key comparable, Value any] struct {
+		key   Key
+		value Value
+		ttl   time.Duration
+	}
+	// syncedEntry is an immutable snapshot of a resident page.
+	syncedEntry[Key comparable, Value any] struct {
+		page  *page[Key, Value]
+		value Value
+		// generation is the page's generation when the entry
+		// was stored, so that touches of an entry which is
+		// read after its page is released are ignored.
+		generation uint32
+		// deadline is the page's expiration time
+		// in Unix nanoseconds, or 0 if it does not expire.
+		deadline int64
+	}
+	// residency is notified when resident values change,
+	// so that they may be served to concurrent readers.
+	residency[Key comparable, Value any] interface {
+		stored(page *page[Key, Value], deadline int64)
+		dropped(key Key)
+	}
+)
+
+// NewSynced constructs a [Synced] cache.
+// Parameters are the same as [New].
+func NewSynced[Key comparable, Value any](capacity int, options ...Option[Key, Value]) (*Synced[Key, Value], error) {
+	var (
+		synced = new(Synced[Key, Value])
+		all    = append(options[:len(options):len(options)],
+			func(set *settings[Key, Value]) error {
+				set.residency = synced
+				return nil
+			})
+		cache, err = New(capacity, all...)
+	)
+	if err != nil {
+		return nil, err
+	}
+	synced.cache = cache
+	if cache.writeBuffer != 0 || cache.softWatermark != 0 {
+		synced.stop = make(chan struct{})
+	}
+	if size := cache.writeBuffer; size != 0 {
+		synced.writes = make(chan syncedWrite[Key, Value], size)
+		synced.notify = make(chan struct{}, 1)
+		synced.workers.Add(1)
+		go synced.drainWrites()
+	}
+	if cache.softWatermark != 0 {
+		synced.trim = make(chan struct{}, 1)
+		synced.workers.Add(1)
+		go synced.trimCold()
+	}
+	return synced, nil
+}
+
+// WithWriteBuffer allows [Synced] caches to buffer
+// up to size modifications, to be applied asynchronously,
+// in batches, by a separate goroutine.
+// Callers which would exceed the buffer apply
+// the batch themselves instead.
+// Until a modification is applied, hits may observe
+// the previous value of its key, but other operations
+// observe buffered modifications in order.
+// Also used by [NewSharded] and [NewActor];
+// ignored by other constructors.
+func WithWriteBuffer[Key comparable, Value any](size int) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if size < 1 {
+			return fmt.Errorf(
+				"%w: write buffer size must be >=1 but %d was provided",
+				ErrInvalidOption, size,
+			)
+		}
+		set.writeBuffer = size
+		return nil
+	}
+}
+
+// Get is like [Cache.Get].
+func (s *Synced[Key, Value]) Get(key Key) (Value, bool) {
+	if value, ok := s.lookup(key); ok {
+		return value, true
+	}
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.Get(key)
+}
+
+// GetMany is like [Cache.GetMany]. Keys which are
+// not served without the lock are looked up
+// while holding the lock once.
+func (s *Synced[Key, Value]) GetMany(keys []Key) (found map[Key]Value, missing []Key) {
+	found, missing = getMany(keys, s.lookup)
+	if len(missing) == 0 {
+		return found, nil
+	}
+	s.lock()
+	defer s.mu.Unlock()
+	locked, missing := s.cache.GetMany(missing)
+	for key, value := range locked {
+		found[key] = value
+	}
+	return found, missing
+}
+
+// GetWithExpiry is like [Cache.GetWithExpiry].
+func (s *Synced[Key, Value]) GetWithExpiry(key Key) (Value, time.Time, bool) {
+	if entry, ok := s.lookupEntry(key); ok {
+		return s.cache.decoded(entry.value), deadlineTime(entry.deadline), true
+	}
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.GetWithExpiry(key)
+}
+
+// lookup returns the value of key if it is resident,
+// without acquiring the lock.
+func (s *Synced[Key, Value]) lookup(key Key) (Value, bool) {
+	entry, ok := s.lookupEntry(key)
+	if !ok {
+		var zero Value
+		return zero, false
+	}
+	return s.cache.decoded(entry.value), true
+}
+
+// lookupEntry is like [Synced.lookup],
+// but returns the entry of key.
+func (s *Synced[Key, Value]) lookupEntry(key Key) (*syncedEntry[Key, Value], bool) {
+	if s.cache.maxIdle != 0 {
+		return nil, false // Extending the deadline requires the lock.
+	}
+	loaded, ok := s.entries.Load(key)
+	if !ok {
+		return nil, false
+	}
+	entry := loaded.(*syncedEntry[Key, Value])
+	if entry.deadline != 0 &&
+		entry.deadline <= s.cache.now().UnixNano() {
+		return nil, false // Expiration requires the lock.
+	}
+	entry.touch()
+	s.lookups.hits.Add(1)
+	return entry, true
+}
+
+// Load is like [Cache.Load], but fetch is called
+// without holding the lock, so it may use the cache.
+// If another caller stored a value for key
+// while fetch was running, that value is returned instead.
+func (s *Synced[Key, Value]) Load(key Key, fetch func() (Value, error)) (Value, error) {
+	return s.load(context.Background(), key, fetcher[Value]{plain: fetch})
+}
+
+// LoadContext is like [Cache.LoadContext] and [Synced.Load].
+func (s *Synced[Key, Value]) LoadContext(ctx context.Context, key Key, fetch func(context.Context) (Value, error)) (Value, error) {
+	return s.load(ctx, key, fetcher[Value]{withContext: fetch})
+}
+
+func (s *Synced[Key, Value]) load(ctx context.Context, key Key, fetch fetcher[Value]) (value Value, err error) {
+	ctx, end := s.cache.startLoad(ctx, key)
+	var hit bool
+	if end != nil {
+		defer func() { end(hit, err) }()
+	}
+	if value, hit = s.Get(key); hit {
+		return value, nil
+	}
+	if value, err = s.cache.fetch(ctx, key, fetch); err != nil {
+		return value, fetchError(err)
+	}
+	s.lock()
+	defer s.mu.Unlock()
+	actual, _ := s.cache.setIfAbsent(key, value)
+	return actual, nil
+}
+
+// GetOrSet is like [Cache.GetOrSet].
+func (s *Synced[Key, Value]) GetOrSet(key Key, value Value) (actual Value, loaded bool) {
+	if actual, ok := s.lookup(key); ok {
+		return actual, true
+	}
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.GetOrSet(key, value)
+}
+
+// SetIf is like [Cache.SetIf]. cond is called while
+// holding the lock, so that the value it is called with
+// is not modified before value is stored.
+// cond must not use the cache.
+// Unlike [Synced.Set], it is applied synchronously.
+func (s *Synced[Key, Value]) SetIf(key Key, value Value, cond func(old Value, exists bool) bool) bool {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.SetIf(key, value, cond)
+}
+
+// Compute is like [Cache.Compute]. f is called while
+// holding the lock, like the condition of [Synced.SetIf].
+func (s *Synced[Key, Value]) Compute(key Key, f func(old Value, exists bool) (value Value, keep bool)) (Value, bool) {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.Compute(key, f)
+}
+
+// Mutate is like [Cache.Mutate]. mutate is called while
+// holding the lock, like the condition of [Synced.SetIf].
+// Hits served without the lock observe the value
+// as it was before or after mutate, never during.
+func (s *Synced[Key, Value]) Mutate(key Key, mutate func(*Value)) bool {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.Mutate(key, mutate)
+}
+
+// Set is like [Cache.Set]. See [WithWriteBuffer].
+func (s *Synced[Key, Value]) Set(key Key, value Value) {
+	s.SetWithTTL(key, value, 0)
+}
+
+// SetWithTTL is like [Cache.SetWithTTL]. See [WithWriteBuffer].
+func (s *Synced[Key, Value]) SetWithTTL(key Key, value Value, ttl time.Duration) {
+	write := syncedWrite[Key, Value]{key: key, value: value, ttl: ttl}
+	if s.writes != nil && !s.closed.Load() {
+		select {
+		case s.writes <- write:
+			select {
+			case s.notify <- struct{}{}:
+			default: // Drainer already notified.
+			}
+			return
+		default: // Buffer is full; apply it here.
+		}
+	}
+	s.lock()
+	defer s.mu.Unlock()
+	s.cache.SetWithTTL(write.key, write.value, write.ttl)
+}
+
+// ExtendTTL is like [Cache.ExtendTTL].
+func (s *Synced[Key, _]) ExtendTTL(key Key, ttl time.Duration) bool {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.ExtendTTL(key, ttl)
+}
+
+// SetExpiry is like [Cache.SetExpiry].
+func (s *Synced[Key, _]) SetExpiry(key Key, deadline time.Time) bool {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.SetExpiry(key, deadline)
+}
+
+// SetVersioned is like [Cache.SetVersioned].
+// Unlike [Synced.Set], it is applied synchronously.
+func (s *Synced[Key, Value]) SetVersioned(key Key, value Value) uint64 {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.SetVersioned(key, value)
+}
+
+// GetVersioned is like [Cache.GetVersioned].
+// It always acquires the lock.
+func (s *Synced[Key, Value]) GetVersioned(key Key) (Value, uint64, bool) {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.GetVersioned(key)
+}
+
+// CompareAndSetVersion is like [Cache.CompareAndSetVersion].
+// The comparison and the modification are atomic.
+func (s *Synced[Key, Value]) CompareAndSetVersion(key Key, version uint64, value Value) bool {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.CompareAndSetVersion(key, version, value)
+}
+
+// Expire is like [Cache.Expire].
+// Without a [Janitor] (see [Synced.ExpireEvery]),
+// expired entries are evicted lazily, when they are
+// accessed or when the cache is modified, so their
+// values are retained while the cache is idle.
+func (s *Synced[_, _]) Expire() int {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.Expire()
+}
+
+// ExpireEvery starts a [Janitor] which calls
+// [Synced.Expire] every interval, until stop is called,
+// so that expired entries are evicted even
+// while the cache is not otherwise used.
+func (s *Synced[_, _]) ExpireEvery(interval time.Duration) (stop func()) {
+	return NewJanitor(s, interval).Stop
+}
+
+// Apply is like [Cache.Apply], holding the lock
+// for the whole batch. Sets are applied synchronously.
+func (s *Synced[Key, Value]) Apply(ops []Op[Key, Value]) []OpResult[Value] {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.Apply(ops)
+}
+
+func (s *Synced[_, _]) capacity() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cache.capacity
+}
+
+func (s *Synced[_, _]) resize(capacity int) {
+	s.lock()
+	defer s.mu.Unlock()
+	s.cache.resize(capacity)
+}
+
+// CheckInvariants is like [Cache.CheckInvariants],
+// and also checks the entries served without the lock.
+func (s *Synced[Key, Value]) CheckInvariants() error {
+	s.lock()
+	defer s.mu.Unlock()
+	return errors.Join(
+		s.cache.CheckInvariants(),
+		s.cache.checkEntries(func(yield func(Key, *syncedEntry[Key, Value]) bool) {
+			s.entries.Range(func(key, entry any) bool {
+				return yield(key.(Key), entry.(*syncedEntry[Key, Value]))
+			})
+		}),
+	)
+}
+
+// Flush applies any buffered modifications,
+// and then is like [Cache.Flush].
+// See [WithWriteBuffer].
+func (s *Synced[_, _]) Flush() {
+	s.applyWrites()
+	s.cache.Flush()
+}
+
+func (s *Synced[_, _]) applyWrites() {
+	s.lock()
+	s.mu.Unlock()
+}
+
+// Close stops the goroutines started by [WithWriteBuffer]
+// and [WithSoftWatermark], and applies any buffered modifications.
+// Subsequent modifications are applied synchronously.
+// Close always returns nil.
+func (s *Synced[_, _]) Close() error {
+	if s.stop == nil {
+		return nil
+	}
+	s.closer.Do(func() {
+		s.closed.Store(true)
+		close(s.stop)
+		s.workers.Wait()
+		s.Flush()
+	})
+	return nil
+}
+
+func (s *Synced[_, _]) drainWrites() {
+	defer s.workers.Done()
+	for {
+		select {
+		case <-s.notify:
+			s.applyWrites()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// lock acquires the lock, observes the lookups
+// served without it, and applies any buffered modifications.
+func (s *Synced[_, _]) lock() {
+	s.mu.Lock()
+	s.lookups.fold(&s.cache.stats.recent)
+	for {
+		select {
+		case write := <-s.writes:
+			s.cache.SetWithTTL(write.key, write.value, write.ttl)
+		default:
+			return
+		}
+	}
+}
+
+// Delete is like [Cache.Delete].
+func (s *Synced[Key, Value]) Delete(key Key) bool {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.Delete(key)
+}
+
+// Invalidate is like [Cache.Invalidate].
+func (s *Synced[Key, Value]) Invalidate(key Key) bool {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.Invalidate(key)
+}
+
+// SetTagged is like [Cache.SetTagged].
+func (s *Synced[Key, Value]) SetTagged(key Key, value Value, tags ...string) {
+	s.lock()
+	defer s.mu.Unlock()
+	s.cache.SetTagged(key, value, tags...)
+}
+
+// InvalidateTag is like [Cache.InvalidateTag].
+func (s *Synced[Key, Value]) InvalidateTag(tag string) int {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.InvalidateTag(tag)
+}
+
+// Remove is like [Cache.Remove].
+func (s *Synced[Key, Value]) Remove(key Key) (Value, bool) {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.Remove(key)
+}
+
+// Purge is like [Cache.Purge].
+// onPurge is called with the lock held.
+func (s *Synced[Key, Value]) Purge(onPurge func(Key, Value)) {
+	s.lock()
+	defer s.mu.Unlock()
+	s.cache.Purge(onPurge)
+}
+
+// Len is like [Cache.Len].
+func (s *Synced[_, _]) Len() int {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.Len()
+}
+
+// ResidentHot is like [Cache.ResidentHot].
+func (s *Synced[_, _]) ResidentHot() int {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.ResidentHot()
+}
+
+// ResidentCold is like [Cache.ResidentCold].
+func (s *Synced[_, _]) ResidentCold() int {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.ResidentCold()
+}
+
+// NonResident is like [Cache.NonResident].
+func (s *Synced[_, _]) NonResident() int {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.NonResident()
+}
+
+// Weight is like [Cache.Weight].
+func (s *Synced[_, _]) Weight() int {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.Weight()
+}
+
+// Keys is like [Cache.Keys], but iterates over
+// a snapshot of the keys taken when called.
+func (s *Synced[Key, _]) Keys() iter.Seq[Key] {
+	s.lock()
+	defer s.mu.Unlock()
+	return slices.Values(slices.Collect(s.cache.Keys()))
+}
+
+// TrackedKeys is like [Cache.TrackedKeys], but iterates
+// over a snapshot of the keys taken when called.
+func (s *Synced[Key, _]) TrackedKeys() iter.Seq2[Key, bool] {
+	s.lock()
+	defer s.mu.Unlock()
+	var (
+		keys     []Key
+		resident []bool
+	)
+	for key, isResident := range s.cache.TrackedKeys() {
+		keys = append(keys, key)
+		resident = append(resident, isResident)
+	}
+	return func(yield func(Key, bool) bool) {
+		for i, key := range keys {
+			if !yield(key, resident[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Range is like [Cache.Range], but calls f with
+// the entries which were resident when called,
+// after the lock is released, so that f may use the cache.
+func (s *Synced[Key, Value]) Range(f func(Key, Value) bool) {
+	s.lock()
+	keys, values := collectEntries(s.cache, nil)
+	s.mu.Unlock()
+	rangeEntries(keys, values, f)
+}
+
+// EntriesWhere is like [Cache.EntriesWhere], but iterates over
+// the matching entries which were resident when called.
+// match is called while the cache is locked, and must not use it;
+// yield is called after the lock is released.
+func (s *Synced[Key, Value]) EntriesWhere(match func(Key, Value) bool) iter.Seq2[Key, Value] {
+	s.lock()
+	keys, values := collectEntries(s.cache, match)
+	s.mu.Unlock()
+	return func(yield func(Key, Value) bool) {
+		rangeEntries(keys, values, yield)
+	}
+}
+
+// Snapshot is like [Cache.Snapshot].
+func (s *Synced[Key, Value]) Snapshot() *ReadOnlyView[Key, Value] {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.Snapshot()
+}
+
+// ExportWarmList is like [Cache.ExportWarmList].
+func (s *Synced[Key, Value]) ExportWarmList(n int) []Key {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.ExportWarmList(n)
+}
+
+// Evictions is like [Cache.Evictions].
+// The channel may be received from without the lock.
+func (s *Synced[Key, Value]) Evictions() <-chan Eviction[Key, Value] {
+	return s.cache.Evictions()
+}
+
+// MemoryUsage is like [Cache.MemoryUsage].
+func (s *Synced[_, _]) MemoryUsage() int {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.MemoryUsage()
+}
+
+// String is like [Cache.String].
+func (s *Synced[_, _]) String() string {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.String()
+}
+
+// Stats is like [Cache.Stats].
+func (s *Synced[_, _]) Stats() Stats {
+	s.lock()
+	defer s.mu.Unlock()
+	stats := s.cache.Stats()
+	s.lookups.addTotal(&stats)
+	return stats
+}
+
+// IntervalStats is like [Cache.IntervalStats],
+// including hits served without the lock.
+func (s *Synced[_, _]) IntervalStats() Stats {
+	s.lock()
+	defer s.mu.Unlock()
+	stats := s.cache.IntervalStats()
+	s.lookups.addInterval(&stats)
+	return stats
+}
+
+// ResetStats is like [Cache.ResetStats],
+// including hits served without the lock.
+func (s *Synced[_, _]) ResetStats() Stats {
+	s.lock()
+	defer s.mu.Unlock()
+	stats := s.cache.ResetStats()
+	s.lookups.addInterval(&stats)
+	s.lookups.reset()
+	return stats
+}
+
+// collectEntries returns the keys and values which
+// [Cache.EntriesWhere] yields, in the same order.
+func collectEntries[Key comparable, Value any](cache *Cache[Key, Value], match func(Key, Value) bool) ([]Key, []Value) {
+	var (
+		keys   []Key
+		values []Value
+	)
+	for key, value := range cache.EntriesWhere(match) {
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+	return keys, values
+}
+
+// rangeEntries calls f with each of the keys
+// and their values, until f returns false,
+// and reports whether f returned false.
+func rangeEntries[Key comparable, Value any](keys []Key, values []Value, f func(Key, Value) bool) bool {
+	for i, key := range keys {
+		if !f(key, values[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func newSyncedEntry[Key comparable, Value any](page *page[Key, Value], deadline int64) *syncedEntry[Key, Value] {
+	return &syncedEntry[Key, Value]{
+		page:       page,
+		value:      page.Value,
+		generation: page.Generation(),
+		deadline:   deadline,
+	}
+}
+
+// touch marks the entry's page as referenced,
+// concurrently with the owner of the cache.
+func (entry *syncedEntry[Key, Value]) touch() {
+	entry.page.Touch(entry.generation)
+}
+
+func (s *Synced[Key, Value]) stored(page *page[Key, Value], deadline int64) {
+	entry := newSyncedEntry(page, deadline)
+	s.entries.Store(page.Name, entry)
+	if s.hot != nil {
+		s.hot.stored(page.Name, entry)
+	}
+	if s.trim != nil && s.cache.aboveSoftWatermark() {
+		select {
+		case s.trim <- struct{}{}:
+		default: // Trimmer already notified.
+		}
+	}
+}
+
+func (s *Synced[Key, _]) dropped(key Key) {
+	s.entries.Delete(key)
+	if s.hot != nil {
+		s.hot.dropped(key)
+	}
+}
+
+// stored notifies the cache's residency observer,
+// if any, of the current value of key,
+// adds its page to the sampled pages,
+// and extends its idle deadline.
+func (c *Cache[Key, Value]) stored(key Key) {
+	if c.residency == nil && c.sampled == nil &&
+		c.maxIdle == 0 && c.views == nil {
+		return
+	}
+	page, ok := c.index.get(key)
+	if !ok || !page.Resident {
+		return
+	}
+	if c.sampled != nil {
+		c.sampled.add(page)
+	}
+	if c.maxIdle != 0 {
+		c.expiry.extend(key, c.now().UnixNano())
+	}
+	if c.residency != nil {
+		deadline, _ := c.expiry.deadline(key)
+		c.residency.stored(page, deadline)
+	}
+	if c.views != nil {
+		c.views.record(key, c.viewEntry(key, page.Value), c.capacity)
+	}
+}
+
+// dropped notifies the cache's residency observer,
+// if any, that the page is no longer resident,
+// removes it from the sampled pages,
+// and releases its value.
+func (c *Cache[Key, Value]) dropped(page *page[Key, Value]) {
+	c.release(page, c.returning)
+	c.journal.dropped(page.Name)
+	c.versions.drop(page.Name)
+	c.groups.drop(page.Name)
+	delete(c.tags, page.Name)
+	if c.weigh != nil {
+		c.weight -= c.weigh(page.Name, page.Value)
+	}
+	if c.sampled != nil {
+		c.sampled.remove(page)
+	}
+	if c.residency != nil {
+		c.residency.dropped(page.Name)
+	}
+	if c.views != nil {
+		c.views.record(page.Name, nil, c.capacity)
+	}
+}
@@ -0,0 +1,569 @@
+package clockpro_test
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestSynced(t *testing.T) {
+	t.Run("invalid capacity", syncedInvalidCapacity)
+	t.Run("matches cache", syncedMatchesCache)
+	t.Run("expiration", syncedExpiration)
+	t.Run("janitor", syncedJanitor)
+	t.Run("load stale", syncedLoadStale)
+	t.Run("subscribe", syncedSubscribe)
+	t.Run("compute", syncedCompute)
+	t.Run("soft watermark", syncedSoftWatermark)
+	t.Run("stats", syncedStats)
+	t.Run("range", syncedRange)
+	t.Run("load", syncedLoad)
+	t.Run("concurrent", syncedConcurrent)
+	t.Run("write buffer", syncedWriteBuffer)
+	t.Run("write buffer order", syncedWriteBufferOrder)
+	t.Run("write buffer concurrent", syncedWriteBufferConcurrent)
+}
+
+func newSynced(tb testing.TB, capacity int, options ...clockpro.Option[int, int]) *clockpro.Synced[int, int] {
+	tb.Helper()
+	cache, err := clockpro.NewSynced(capacity, options...)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return cache
+}
+
+// syncedRange checks that each concurrent cache
+// may be used by the function given to Range.
+func syncedRange(t *testing.T) {
+	t.Parallel()
+	const capacity = 64
+	type rangeCache interface {
+		Set(int, int)
+		Range(func(int, int) bool)
+	}
+	sharded, err := clockpro.NewSharded[int, int](capacity, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	striped, err := clockpro.NewStriped[int, int](capacity, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actor, err := clockpro.NewActor[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer actor.Close()
+	for _, test := range []struct {
+		name  string
+		cache rangeCache
+	}{
+		{"synced", newSynced(t, capacity)},
+		{"sharded", sharded},
+		{"striped", striped},
+		{"actor", actor},
+	} {
+		for key := range capacity / 2 {
+			test.cache.Set(key, key)
+		}
+		var ranged int
+		test.cache.Range(func(key, value int) bool {
+			if key != value {
+				t.Errorf("%s: key %d has value %d", test.name, key, value)
+			}
+			test.cache.Set(key, -key) // Must not deadlock.
+			ranged++
+			return true
+		})
+		if want := capacity / 2; ranged != want {
+			t.Errorf(
+				"%s: unexpected entry count"+
+					"\n\tgot: %d"+
+					"\n\twant: %d",
+				test.name, ranged, want)
+		}
+	}
+}
+
+func syncedInvalidCapacity(t *testing.T) {
+	t.Parallel()
+	cache, err := clockpro.NewSynced[int, int](1)
+	if cache != nil || err == nil {
+		t.Error("NewSynced did not return an error when passed an invalid capacity")
+	}
+}
+
+// syncedMatchesCache checks that references made
+// without the lock are observed by the policy
+// the same as references made through a [clockpro.Cache].
+func syncedMatchesCache(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity   = 32
+		upperBound = capacity * 3
+		accesses   = capacity * 64
+	)
+	var (
+		cache  = newCache[int, int](t, capacity)
+		synced = newSynced(t, capacity)
+		rng    = newReproducibleRNG()
+	)
+	for range accesses {
+		key := rng.Intn(upperBound)
+		_, cacheHit := cache.Get(key)
+		_, syncedHit := synced.Get(key)
+		if cacheHit != syncedHit {
+			t.Fatalf(
+				"hit mismatch for key %d"+
+					"\n\tgot: %t"+
+					"\n\twant: %t",
+				key, syncedHit, cacheHit,
+			)
+		}
+		if !cacheHit {
+			cache.Set(key, key)
+			synced.Set(key, key)
+		}
+	}
+	var (
+		got  = slices.Sorted(synced.Keys())
+		want = slices.Sorted(cache.Keys())
+	)
+	if !slices.Equal(got, want) {
+		t.Errorf(
+			"resident keys differ"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			got, want,
+		)
+	}
+}
+
+func syncedExpiration(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 4
+		key      = 1
+		ttl      = time.Second
+	)
+	var (
+		clock  = &fakeClock{now: time.Unix(0, 0)}
+		synced = newSynced(t, capacity,
+			clockpro.WithTimeSource[int, int](clock.Now),
+		)
+	)
+	synced.SetWithTTL(key, key, ttl)
+	clock.advance(ttl - 1)
+	mustGet(t, synced, key)
+	clock.advance(1)
+	mustMiss(t, synced, key, "expiration")
+	checkSize(t, synced, 0, "after expiration")
+	synced.SetWithTTL(key, key, ttl)
+	synced.Set(key, key) // Clears the TTL.
+	clock.advance(ttl)
+	mustGet(t, synced, key)
+}
+
+// syncedLoadStale expects the value of an expired entry
+// to be returned while its refresh is delayed, and to be
+// replaced once the refresh completes.
+func syncedCompute(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity   = 4
+		key        = 1
+		workers    = 8
+		increments = 256
+	)
+	var (
+		synced = newSynced(t, capacity)
+		wg     sync.WaitGroup
+	)
+	for range workers {
+		wg.Go(func() {
+			for range increments {
+				synced.Compute(key, func(old int, _ bool) (int, bool) {
+					return old + 1, true
+				})
+			}
+		})
+	}
+	wg.Wait()
+	checkGet(t, synced, key, workers*increments, "after concurrent increments")
+}
+
+func syncedSubscribe(t *testing.T) {
+	t.Parallel()
+	const capacity = 4
+	var (
+		synced        = newSynced(t, capacity)
+		invalidations = make(chan int)
+		stop          = synced.Subscribe(invalidations)
+	)
+	defer stop()
+	for key := range capacity {
+		synced.Set(key, key)
+	}
+	invalidations <- 1
+	invalidations <- capacity // Received after 1 is invalidated.
+	if _, ok := synced.Get(1); ok {
+		t.Error("invalidated key is resident")
+	}
+	for key, resident := range synced.TrackedKeys() {
+		if want := key != 1; resident != want {
+			t.Errorf(
+				"unexpected residency of key %d"+
+					"\n\tgot: %t"+
+					"\n\twant: %t",
+				key, resident, want)
+		}
+	}
+}
+
+func syncedLoadStale(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 4
+		key      = 1
+		ttl      = time.Second
+	)
+	var (
+		clock  = &fakeClock{now: time.Unix(0, 0)}
+		synced = newSynced(t, capacity,
+			clockpro.WithTimeSource[int, int](clock.Now),
+			clockpro.WithRetainedValues[int, int](),
+		)
+		ctx     = context.Background()
+		release = make(chan struct{})
+		slow    = func(context.Context) (int, error) {
+			<-release
+			return 2, nil
+		}
+	)
+	synced.SetWithTTL(key, 1, ttl)
+	clock.advance(ttl)
+	value, stale, err := synced.LoadStale(ctx, key, time.Millisecond, slow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != 1 || !stale {
+		t.Errorf(
+			"unexpected result while refreshing"+
+				"\n\tgot: %d, %t"+
+				"\n\twant: %d, %t",
+			value, stale, 1, true)
+	}
+	close(release)
+	for deadline := time.Now().Add(time.Minute); ; {
+		if value, ok := synced.Get(key); ok {
+			if value != 2 {
+				t.Errorf("unexpected refreshed value: %d", value)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("stale value was not refreshed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	// Keys without a stale value wait for fetch.
+	value, stale, err = synced.LoadStale(ctx, key+1, time.Nanosecond,
+		func(context.Context) (int, error) {
+			time.Sleep(time.Millisecond)
+			return 3, nil
+		})
+	if value != 3 || stale || err != nil {
+		t.Errorf(
+			"unexpected result without a stale value"+
+				"\n\tgot: %d, %t, %v"+
+				"\n\twant: %d, %t, %v",
+			value, stale, err, 3, false, nil)
+	}
+}
+
+// syncedJanitor expects expired entries to be evicted
+// while the cache is idle.
+func syncedJanitor(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 4
+		ttl      = time.Millisecond
+	)
+	synced := newSynced(t, capacity)
+	for key := range capacity {
+		synced.SetWithTTL(key, key, ttl)
+	}
+	stop := synced.ExpireEvery(ttl)
+	defer stop()
+	for deadline := time.Now().Add(time.Minute); synced.Len() != 0; {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d expired entries were not evicted", synced.Len())
+		}
+		time.Sleep(ttl)
+	}
+	stop()
+	stop()
+}
+
+func syncedSoftWatermark(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 64
+		soft     = capacity / 2
+	)
+	for _, fraction := range []float64{0, 1} {
+		if _, err := clockpro.NewSynced(capacity,
+			clockpro.WithSoftWatermark[int, int](fraction),
+		); !errors.Is(err, clockpro.ErrInvalidOption) {
+			t.Errorf(
+				"expected error to match"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				err, clockpro.ErrInvalidOption)
+		}
+	}
+	synced := newSynced(t, capacity,
+		clockpro.WithSoftWatermark[int, int](float64(soft)/capacity),
+	)
+	defer synced.Close()
+	for key := range capacity * 2 {
+		synced.Set(key, key)
+	}
+	for deadline := time.Now().Add(time.Minute); synced.Len() > soft; {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d resident pages were not trimmed to %d", synced.Len(), soft)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := synced.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := synced.CheckInvariants(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func syncedStats(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 4
+		key      = 1
+		hits     = 3
+	)
+	synced := newSynced(t, capacity)
+	mustMiss(t, synced, key, "empty cache")
+	synced.Set(key, key)
+	for range hits {
+		mustGet(t, synced, key)
+	}
+	stats := synced.Stats()
+	if stats.Hits != hits || stats.Misses != 1 {
+		t.Errorf(
+			"unexpected hits and misses"+
+				"\n\tgot: %d, %d"+
+				"\n\twant: %d, %d",
+			stats.Hits, stats.Misses, hits, 1,
+		)
+	}
+	if !synced.Delete(key) {
+		t.Fatal("Delete did not report a resident value")
+	}
+	mustMiss(t, synced, key, "deletion")
+}
+
+func syncedLoad(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 4
+		key      = 1
+	)
+	var (
+		synced  = newSynced(t, capacity)
+		fetches int
+		fetch   = func() (int, error) {
+			fetches++
+			if fetches == 1 {
+				synced.Set(key, -key) // Stored while fetching.
+			}
+			return key, nil
+		}
+	)
+	got, err := synced.Load(key, fetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := -key; got != want {
+		t.Errorf(
+			"Load did not return the value stored during fetch"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			got, want,
+		)
+	}
+	if _, err := synced.Load(key, fetch); err != nil {
+		t.Fatal(err)
+	}
+	if fetches != 1 {
+		t.Errorf(
+			"Load fetched a resident value"+
+				"\n\tgot: %d fetches"+
+				"\n\twant: %d",
+			fetches, 1,
+		)
+	}
+}
+
+func syncedConcurrent(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity   = 64
+		upperBound = capacity * 2
+		workers    = 8
+		accesses   = 4096
+	)
+	synced := newSynced(t, capacity,
+		clockpro.WithTimeSource[int, int](time.Now),
+	)
+	exerciseConcurrently(t, synced, upperBound, workers, accesses)
+	if length := synced.Len(); length > capacity {
+		t.Errorf(
+			"cache exceeded capacity"+
+				"\n\tgot: %d"+
+				"\n\twant: <=%d",
+			length, capacity,
+		)
+	}
+}
+
+type concurrentCache interface {
+	testCache[int, int]
+	SetWithTTL(int, int, time.Duration)
+	Delete(int) bool
+}
+
+func exerciseConcurrently(tb testing.TB, cache concurrentCache, upperBound, workers, accesses int) {
+	tb.Helper()
+	var wg sync.WaitGroup
+	for worker := range workers {
+		wg.Go(func() {
+			rng := rand.New(rand.NewSource(int64(worker)))
+			for range accesses {
+				key := rng.Intn(upperBound)
+				switch rng.Intn(16) {
+				case 0:
+					cache.Delete(key)
+				case 1:
+					cache.SetWithTTL(key, key, time.Millisecond)
+				default:
+					if value, ok := cache.Get(key); !ok {
+						cache.Set(key, key)
+					} else if value != key {
+						tb.Errorf(
+							"wrong value for key %d"+
+								"\n\tgot: %d"+
+								"\n\twant: %d",
+							key, value, key,
+						)
+						return
+					}
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func syncedWriteBuffer(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 16
+		buffer   = 4
+	)
+	if _, err := clockpro.NewSynced(capacity,
+		clockpro.WithWriteBuffer[int, int](0),
+	); !errors.Is(err, clockpro.ErrInvalidOption) {
+		t.Errorf(
+			"unexpected error for empty buffer"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			err, clockpro.ErrInvalidOption,
+		)
+	}
+	synced := newSynced(t, capacity,
+		clockpro.WithWriteBuffer[int, int](buffer),
+	)
+	for key := range capacity {
+		synced.Set(key, key)
+	}
+	synced.Flush()
+	checkSize(t, synced, capacity, "after flush")
+	for key := range capacity {
+		checkGet(t, synced, key, key, "flushed key")
+	}
+	if err := synced.Close(); err != nil {
+		t.Fatal(err)
+	}
+	synced.Set(capacity, capacity) // Applied synchronously.
+	checkGet(t, synced, capacity, capacity, "set after close")
+}
+
+func syncedWriteBufferOrder(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 16
+		buffer   = capacity
+		key      = 1
+	)
+	synced := newSynced(t, capacity,
+		clockpro.WithWriteBuffer[int, int](buffer),
+	)
+	defer synced.Close()
+	for value := range buffer {
+		synced.Set(key, value)
+	}
+	synced.Delete(key) // Must follow the buffered sets.
+	mustMiss(t, synced, key, "deletion after buffered sets")
+	synced.Set(key, key)
+	if got, loaded := synced.GetOrSet(key, -key); !loaded || got != key {
+		t.Errorf(
+			"GetOrSet did not observe buffered set"+
+				"\n\tgot: %d, %t"+
+				"\n\twant: %d, %t",
+			got, loaded, key, true,
+		)
+	}
+}
+
+func syncedWriteBufferConcurrent(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity   = 64
+		buffer     = 8
+		upperBound = capacity * 2
+		workers    = 8
+		accesses   = 4096
+	)
+	synced := newSynced(t, capacity,
+		clockpro.WithWriteBuffer[int, int](buffer),
+		clockpro.WithTimeSource[int, int](time.Now),
+	)
+	exerciseConcurrently(t, synced, upperBound, workers, accesses)
+	if err := synced.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if length := synced.Len(); length > capacity {
+		t.Errorf(
+			"cache exceeded capacity"+
+				"\n\tgot: %d"+
+				"\n\twant: <=%d",
+			length, capacity,
+		)
+	}
+}
@@ -0,0 +1,17 @@
+package clockpro
+
+// SetWithTag is like [Cache.Set], but also associates
+// tag with the entry, until it is no longer resident
+// or is tagged again. Tags are opaque to the cache,
+// and are reported within [EntryInfo], such as by
+// [Cache.Export], [Cache.Pages], and [Hooks.OnRelease].
+// [Cache.Set] retains the tag of a resident entry.
+func (c *Cache[Key, Value]) SetWithTag(key Key, value Value, tag any) {
+	c.set(key, value, 0)
+	if page, ok := c.index.get(key); ok && page.Resident {
+		if c.tags == nil {
+			c.tags = make(map[Key]any)
+		}
+		c.tags[key] = tag
+	}
+}
@@ -0,0 +1,56 @@
+package clockpro_test
+
+import (
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestTag(t *testing.T) {
+	t.Parallel()
+	const capacity = 2
+	var (
+		released = make(map[int]any)
+		hooks    = clockpro.Hooks[int, int]{
+			OnRelease: func(key, _ int, info clockpro.EntryInfo) {
+				released[key] = info.Tag
+			},
+		}
+		cache, err = clockpro.New(capacity, clockpro.WithHooks(hooks))
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags := func() map[int]any {
+		tags := make(map[int]any)
+		for key, info := range cache.Pages() {
+			tags[key] = info.Tag
+		}
+		return tags
+	}
+	checkTag := func(name string, got, want any) {
+		t.Helper()
+		if got != want {
+			t.Errorf(
+				"unexpected tag %s"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				name, got, want,
+			)
+		}
+	}
+	cache.SetWithTag(1, 1, "one")
+	cache.SetWithTag(2, 2, "two")
+	cache.Set(1, 10)
+	checkTag("retained by Set", tags()[1], "one")
+	checkTag("released by Set", released[1], "one")
+	cache.Export(func(key, _ int, info clockpro.EntryInfo) bool {
+		checkTag("exported", info.Tag, tags()[key])
+		return true
+	})
+	cache.Set(3, 3) // Evicts 2.
+	checkTag("released by eviction", released[2], "two")
+	checkTag("of test page", tags()[2], nil)
+	cache.Set(2, 2)
+	checkTag("of resurrected page", tags()[2], nil)
+}
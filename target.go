@@ -0,0 +1,101 @@
+package clockpro
+
+import "fmt"
+
+// WithAdaptationRateLimit bounds how far the cold target
+// may move from where it was at the start of each window of
+// window operations (lookups and modifications), to step pages
+// in either direction, so that workloads which alternate between
+// phases (such as scans and loops) do not swing the target
+// between its extremes. Moves of [WithShiftDetection]
+// are bounded likewise; [Cache.SetColdTarget] is not.
+func WithAdaptationRateLimit[Key comparable, Value any](step, window int) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if step < 1 || window < 1 {
+			return fmt.Errorf(
+				"%w: adaptation step and window must be >=1 but %d and %d were provided",
+				ErrInvalidOption, step, window,
+			)
+		}
+		set.adaptationStep = step
+		set.adaptationWindow = window
+		return nil
+	}
+}
+
+// limitAdaptation bounds delta such that the cold target
+// remains within step of its origin in the current window,
+// starting a new window if the current one elapsed.
+func (c *Cache[_, _]) limitAdaptation(delta int) int {
+	if c.adaptationStart == 0 ||
+		c.operations-c.adaptationStart >= uint64(c.adaptationWindow) {
+		c.adaptationStart = max(c.operations, 1)
+		c.adaptationOrigin = c.coldTarget
+	}
+	var (
+		lowest  = c.adaptationOrigin - c.adaptationStep
+		highest = c.adaptationOrigin + c.adaptationStep
+	)
+	return min(max(c.coldTarget+delta, lowest), highest) - c.coldTarget
+}
+
+// ColdTarget returns the amount of resident
+// cold pages which the cache currently targets.
+func (c *Cache[_, _]) ColdTarget() int { return c.coldTarget }
+
+// SetColdTarget overrides the cold target with n,
+// which must be within the bounds of the cold target
+// (see [WithColdTargetBounds]). Unless adaptation is
+// paused (see [Cache.PauseAdaptation]), the cache continues
+// to adapt the target from n. Hot pages above the new
+// hot target are demoted as cold pages are promoted.
+func (c *Cache[_, _]) SetColdTarget(n int) error {
+	if n < c.coldMinimum || n > c.coldMaximum {
+		return fmt.Errorf(
+			"%w: cold target must be within [%d, %d] but %d was requested",
+			ErrInvalidTarget, c.coldMinimum, c.coldMaximum, n,
+		)
+	}
+	c.setColdTarget(n)
+	return nil
+}
+
+// PauseAdaptation stops the cache from adapting its
+// cold target until [Cache.ResumeAdaptation] is called,
+// such as to pin the target set by [Cache.SetColdTarget]
+// during an incident or an experiment.
+// The target is still kept within its bounds
+// if the capacity of the cache changes.
+func (c *Cache[_, _]) PauseAdaptation() { c.paused = true }
+
+// ResumeAdaptation resumes the adaptation
+// stopped by [Cache.PauseAdaptation].
+func (c *Cache[_, _]) ResumeAdaptation() { c.paused = false }
+
+// ColdTarget is like [Cache.ColdTarget].
+func (s *Synced[_, _]) ColdTarget() int {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.ColdTarget()
+}
+
+// SetColdTarget is like [Cache.SetColdTarget].
+func (s *Synced[_, _]) SetColdTarget(n int) error {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.SetColdTarget(n)
+}
+
+// PauseAdaptation is like [Cache.PauseAdaptation].
+func (s *Synced[_, _]) PauseAdaptation() {
+	s.lock()
+	defer s.mu.Unlock()
+	s.cache.PauseAdaptation()
+}
+
+// ResumeAdaptation is like [Cache.ResumeAdaptation].
+func (s *Synced[_, _]) ResumeAdaptation() {
+	s.lock()
+	defer s.mu.Unlock()
+	s.cache.ResumeAdaptation()
+}
@@ -0,0 +1,162 @@
+package clockpro_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestColdTarget(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 16
+		rounds   = 8
+	)
+	var highest int // Since the last check.
+	cache, err := clockpro.New(capacity,
+		clockpro.WithAdaptationRecorder[int, int](func(sample clockpro.AdaptationSample) {
+			highest = max(highest, sample.ColdTarget)
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, invalid := range []int{0, capacity} {
+		if err := cache.SetColdTarget(invalid); !errors.Is(err, clockpro.ErrInvalidTarget) {
+			t.Errorf(
+				"unexpected error for target %d"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				invalid, err, clockpro.ErrInvalidTarget)
+		}
+	}
+	checkTarget := func(want int, context string) {
+		t.Helper()
+		if got := cache.ColdTarget(); got != want || highest > want {
+			t.Errorf(
+				"unexpected cold target %s"+
+					"\n\tgot: %d (highest %d)"+
+					"\n\twant: %d",
+				context, got, highest, want)
+		}
+		highest = 0
+	}
+	// Accessing more keys than the capacity
+	// resurrects test pages, growing the target.
+	rng := newReproducibleRNG()
+	access := func() {
+		for range capacity * rounds {
+			key := rng.Intn(capacity * 2)
+			if _, ok := cache.Get(key); !ok {
+				cache.Set(key, key)
+			}
+		}
+	}
+	const pinned = 1
+	if err := cache.SetColdTarget(pinned); err != nil {
+		t.Fatal(err)
+	}
+	checkTarget(pinned, "after SetColdTarget")
+	cache.PauseAdaptation()
+	access()
+	checkTarget(pinned, "while paused")
+	cache.ResumeAdaptation()
+	access()
+	if highest <= pinned {
+		t.Error("cold target did not adapt once resumed")
+	}
+	if err := cache.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAdaptationRateLimit(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 64
+		step     = 2
+		window   = 1 << 20 // Longer than the workload.
+	)
+	if _, err := clockpro.New(capacity,
+		clockpro.WithAdaptationRateLimit[int, int](0, window),
+	); !errors.Is(err, clockpro.ErrInvalidOption) {
+		t.Errorf("unexpected error for step 0: %v", err)
+	}
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		initial   = cache.ColdTarget()
+		unlimited = shiftedColdTarget(t, capacity)
+		limited   = shiftedColdTarget(t, capacity,
+			clockpro.WithAdaptationRateLimit[int, int](step, window),
+		)
+	)
+	if unlimited <= initial+step {
+		t.Fatalf("workload did not move the cold target beyond %d: %d", initial+step, unlimited)
+	}
+	if limited > initial+step {
+		t.Errorf(
+			"cold target moved beyond the limit"+
+				"\n\tgot: %d"+
+				"\n\twant: <=%d",
+			limited, initial+step)
+	}
+}
+
+// TestDemotionBatching checks that shrinking the hot target
+// does not demote every excess hot page within a single
+// operation, but that the excess is demoted eventually.
+func TestDemotionBatching(t *testing.T) {
+	t.Parallel()
+	const capacity = 256
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rng := newReproducibleRNG()
+	access := func(keys int) {
+		key := rng.Intn(keys)
+		if _, ok := cache.Get(key); !ok {
+			cache.Set(key, key)
+		}
+	}
+	for range capacity * 16 { // Fits, so most pages become hot.
+		access(capacity)
+	}
+	if err := cache.SetColdTarget(capacity / 2); err != nil {
+		t.Fatal(err)
+	}
+	var (
+		hotTarget = capacity - cache.ColdTarget()
+		excess    = cache.ResidentHot() - hotTarget
+		demotions = cache.Stats().Demotions
+	)
+	if excess < capacity/4 {
+		t.Fatalf("workload did not fill the hot region: %d excess pages", excess)
+	}
+	for range capacity * 16 {
+		access(capacity * 2)
+		current := cache.Stats().Demotions
+		if demoted := int(current - demotions); demoted >= excess {
+			t.Fatalf(
+				"single operation demoted every excess page"+
+					"\n\tgot: %d"+
+					"\n\twant: <%d",
+				demoted, excess)
+		}
+		demotions = current
+	}
+	if hot, target := cache.ResidentHot(), capacity-cache.ColdTarget(); hot > target {
+		t.Errorf(
+			"excess hot pages were not demoted"+
+				"\n\tgot: %d"+
+				"\n\twant: <=%d",
+			hot, target)
+	}
+	if err := cache.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,173 @@
+package clockpro_test
+
+import (
+	"errors"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/djdv/go-clockpro"
+)
+
+type tortureCache interface {
+	concurrentCache
+	Load(int, func() (int, error)) (int, error)
+	Remove(int) (int, bool)
+	Apply([]intOp) []intResult
+	Purge(func(int, int))
+	CheckInvariants() error
+	Snapshot() *clockpro.ReadOnlyView[int, int]
+}
+
+// TestTorture exercises the concurrent variants from
+// many goroutines with every kind of operation,
+// while checking their invariants periodically.
+// Intended to be run with the race detector.
+func TestTorture(t *testing.T) {
+	const (
+		capacity = 128
+		shards   = 4
+	)
+	for _, test := range []struct {
+		name string
+		new  func(testing.TB) tortureCache
+	}{
+		{"synced", func(tb testing.TB) tortureCache {
+			return newSynced(tb, capacity)
+		}},
+		{"synced write buffer", func(tb testing.TB) tortureCache {
+			cache := newSynced(tb, capacity,
+				clockpro.WithWriteBuffer[int, int](capacity/8),
+			)
+			tb.Cleanup(func() { cache.Close() })
+			return cache
+		}},
+		{"sharded", func(tb testing.TB) tortureCache {
+			return newSharded(tb, capacity, shards)
+		}},
+		{"sharded ghosts", func(tb testing.TB) tortureCache {
+			return newSharded(tb, capacity, shards,
+				clockpro.WithGhostSketch[int, int](),
+				clockpro.WithScanDetection[int, int](capacity/shards),
+			)
+		}},
+		{"striped", func(tb testing.TB) tortureCache {
+			return newStriped(tb, capacity, shards)
+		}},
+		{"actor", func(tb testing.TB) tortureCache {
+			return newActor(tb, capacity)
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			torture(t, test.new(t), capacity)
+		})
+	}
+}
+
+func torture(t *testing.T, cache tortureCache, capacity int) {
+	const (
+		accesses = 1 << 12
+		maxTTL   = int64(time.Millisecond)
+		batch    = 8
+	)
+	var (
+		workers    = runtime.GOMAXPROCS(0) * 2
+		upperBound = capacity * 2
+		errFetch   = errors.New("fetch failed")
+		wg         sync.WaitGroup
+		checker    sync.WaitGroup
+		done       atomic.Bool
+	)
+	checker.Go(func() {
+		for !done.Load() {
+			if err := cache.CheckInvariants(); err != nil {
+				t.Error(err)
+				return
+			}
+			for key, value := range cache.Snapshot().All() {
+				if value != key {
+					t.Errorf(
+						"wrong value in view for key %d"+
+							"\n\tgot: %d"+
+							"\n\twant: %d",
+						key, value, key,
+					)
+					return
+				}
+			}
+			time.Sleep(time.Millisecond)
+		}
+	})
+	for worker := range workers {
+		wg.Go(func() {
+			rng := rand.New(rand.NewSource(int64(worker)))
+			for range accesses {
+				key := rng.Intn(upperBound)
+				switch rng.Intn(16) {
+				case 0:
+					cache.Delete(key)
+				case 1:
+					cache.Remove(key)
+				case 2:
+					cache.SetWithTTL(key, key, time.Duration(rng.Int63n(maxTTL)))
+				case 3:
+					_, err := cache.Load(key, func() (int, error) {
+						if rng.Intn(4) == 0 {
+							return 0, errFetch
+						}
+						return key, nil
+					})
+					if err != nil && !errors.Is(err, errFetch) {
+						t.Error(err)
+						return
+					}
+				case 4:
+					ops := make([]intOp, batch)
+					for i := range ops {
+						key := rng.Intn(upperBound)
+						ops[i] = intOp{
+							Kind:  clockpro.OpKind(rng.Intn(3) + 1),
+							Key:   key,
+							Value: key,
+						}
+					}
+					cache.Apply(ops)
+				case 5:
+					if rng.Intn(accesses) == 0 {
+						cache.Purge(nil)
+					}
+				default:
+					if value, ok := cache.Get(key); !ok {
+						cache.Set(key, key)
+					} else if value != key {
+						t.Errorf(
+							"wrong value for key %d"+
+								"\n\tgot: %d"+
+								"\n\twant: %d",
+							key, value, key,
+						)
+						return
+					}
+				}
+			}
+		})
+	}
+	wg.Wait()
+	done.Store(true)
+	checker.Wait()
+	if err := cache.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+	if length := cache.Len(); length > capacity {
+		t.Errorf(
+			"cache exceeded capacity"+
+				"\n\tgot: %d"+
+				"\n\twant: <=%d",
+			length, capacity,
+		)
+	}
+}
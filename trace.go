@@ -0,0 +1,70 @@
+package clockpro
+
+import "strconv"
+
+type (
+	// Hand identifies one of the hands of the clock.
+	Hand uint8
+	// Trace describes a page that a hand
+	// passed over, or acted upon.
+	Trace[Key comparable] struct {
+		Key  Key
+		Hand Hand
+		// Kind is the action taken by the hand,
+		// or 0 if it passed over the page.
+		Kind DecisionKind
+	}
+)
+
+const (
+	// HandHot clears the references of hot pages,
+	// removes cold and test pages from the stack,
+	// and demotes the hot page it stops at.
+	HandHot Hand = iota + 1
+	// HandCold passes over hot and test pages,
+	// promotes or restacks referenced cold pages,
+	// and evicts the cold page it stops at.
+	HandCold
+	// HandTest passes over resident pages,
+	// and removes the test page it stops at
+	// when the metadata exceeds its limit.
+	HandTest
+)
+
+// WithTracer calls trace synchronously
+// each time a hand of the clock moves over a page,
+// in the order that the pages are visited.
+// Transitions which are not made by a hand,
+// such as those of [Cache.Invalidate], resurrections,
+// or the removal of evicted pages outside of the stack,
+// are not traced.
+// trace must not call back into the cache.
+func WithTracer[Key comparable, Value any](trace func(Trace[Key])) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		set.tracer = trace
+		return nil
+	}
+}
+
+func (c *Cache[Key, _]) trace(hand Hand, kind DecisionKind, key Key) {
+	if trace := c.tracer; trace != nil {
+		trace(Trace[Key]{
+			Key:  key,
+			Hand: hand,
+			Kind: kind,
+		})
+	}
+}
+
+func (hand Hand) String() string {
+	switch hand {
+	case HandHot:
+		return "hot"
+	case HandCold:
+		return "cold"
+	case HandTest:
+		return "test"
+	default:
+		return "Hand(" + strconv.Itoa(int(hand)) + ")"
+	}
+}
@@ -0,0 +1,83 @@
+package clockpro_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestTracer(t *testing.T) {
+	t.Parallel()
+	const capacity = 4
+	type trace = clockpro.Trace[int]
+	var (
+		traces []trace
+		cache  = func() *clockpro.Cache[int, int] {
+			cache, err := clockpro.New(capacity,
+				clockpro.WithTracer[int, int](func(event trace) {
+					traces = append(traces, event)
+				}),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return cache
+		}()
+		pass = clockpro.DecisionKind(0)
+	)
+	for _, test := range []struct {
+		name     string
+		sequence func()
+		want     []trace
+	}{
+		{
+			"fill",
+			func() { fill(cache, 0, capacity) },
+			nil,
+		},
+		{
+			"evict",
+			func() { cache.Set(4, 4) },
+			[]trace{
+				{Key: 3, Hand: clockpro.HandCold, Kind: clockpro.DecisionEvict},
+				{Key: 0, Hand: clockpro.HandCold, Kind: pass},
+				{Key: 1, Hand: clockpro.HandCold, Kind: pass},
+				{Key: 2, Hand: clockpro.HandCold, Kind: pass},
+				{Key: 3, Hand: clockpro.HandCold, Kind: pass},
+			},
+		},
+		{
+			"promote",
+			func() {
+				cache.Get(0)
+				cache.Get(4)
+				cache.Set(5, 5)
+			},
+			[]trace{
+				{Key: 0, Hand: clockpro.HandHot, Kind: clockpro.DecisionClear},
+				{Key: 4, Hand: clockpro.HandCold, Kind: clockpro.DecisionClear},
+				{Key: 4, Hand: clockpro.HandCold, Kind: clockpro.DecisionPromote},
+				{Key: 1, Hand: clockpro.HandHot, Kind: clockpro.DecisionDemote},
+				{Key: 0, Hand: clockpro.HandCold, Kind: pass},
+				{Key: 4, Hand: clockpro.HandCold, Kind: pass},
+				{Key: 1, Hand: clockpro.HandCold, Kind: clockpro.DecisionEvict},
+				{Key: 2, Hand: clockpro.HandCold, Kind: pass},
+				{Key: 3, Hand: clockpro.HandCold, Kind: pass},
+				{Key: 0, Hand: clockpro.HandCold, Kind: pass},
+				{Key: 4, Hand: clockpro.HandCold, Kind: pass},
+			},
+		},
+	} {
+		traces = nil
+		test.sequence()
+		if !slices.Equal(traces, test.want) {
+			t.Errorf(
+				"%s: unexpected traces"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				test.name, traces, test.want,
+			)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package clockpro
+
+import "iter"
+
+// Tracker is a [Cache] of keys without values,
+// which tracks the membership of keys, such as to
+// decide which keys are admitted to a larger store
+// by the frequency and recency of their accesses.
+// Tracker is not safe for concurrent use.
+type Tracker[Key comparable] struct {
+	cache *Cache[Key, struct{}]
+}
+
+// NewTracker constructs a [Tracker] which tracks up to
+// capacity resident keys, and the metadata of the keys
+// which were evicted recently, like [New].
+func NewTracker[Key comparable](capacity int, options ...Option[Key, struct{}]) (*Tracker[Key], error) {
+	cache, err := New(capacity, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &Tracker[Key]{cache: cache}, nil
+}
+
+// Access is like [Cache.Get] followed by [Cache.Set]
+// if it missed, so that key becomes resident,
+// and reports whether key was resident.
+func (tr *Tracker[Key]) Access(key Key) bool {
+	if _, ok := tr.cache.Get(key); ok {
+		return true
+	}
+	tr.cache.Set(key, struct{}{})
+	return false
+}
+
+// Contains is like [Cache.Get],
+// and reports whether key is resident.
+func (tr *Tracker[Key]) Contains(key Key) bool {
+	_, ok := tr.cache.Get(key)
+	return ok
+}
+
+// Add is like [Cache.Set].
+func (tr *Tracker[Key]) Add(key Key) { tr.cache.Set(key, struct{}{}) }
+
+// Remove is like [Cache.Delete].
+func (tr *Tracker[Key]) Remove(key Key) bool { return tr.cache.Delete(key) }
+
+// Len is like [Cache.Len].
+func (tr *Tracker[_]) Len() int { return tr.cache.Len() }
+
+// Keys is like [Cache.Keys].
+func (tr *Tracker[Key]) Keys() iter.Seq[Key] { return tr.cache.Keys() }
+
+// Stats is like [Cache.Stats].
+func (tr *Tracker[_]) Stats() Stats { return tr.cache.Stats() }
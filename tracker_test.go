@@ -0,0 +1,45 @@
+package clockpro_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestTracker(t *testing.T) {
+	t.Parallel()
+	const capacity = 2
+	tracker, err := clockpro.NewTracker[int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hits []bool
+	for _, key := range []int{1, 2, 1, 3, 2} {
+		hits = append(hits, tracker.Access(key))
+	}
+	if want := []bool{false, false, true, false, false}; !slices.Equal(hits, want) {
+		t.Errorf(
+			"unexpected accesses"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			hits, want,
+		)
+	}
+	if !tracker.Contains(1) {
+		t.Error("expected frequently accessed key to be resident")
+	}
+	if !tracker.Remove(1) || tracker.Contains(1) {
+		t.Error("expected removed key to not be resident")
+	}
+	tracker.Add(4)
+	got := slices.Sorted(tracker.Keys())
+	if want := []int{2, 4}; !slices.Equal(got, want) || tracker.Len() != len(want) {
+		t.Errorf(
+			"unexpected resident keys"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			got, want,
+		)
+	}
+}
@@ -0,0 +1,46 @@
+package clockpro
+
+import "fmt"
+
+// WithEncode transforms values before they are stored,
+// such as to compress, clone, or canonicalize them.
+// Values are decoded by the function given to [WithDecode]
+// (if any) before they are returned by the cache.
+// [Hooks], the copyValue function of [Cache.Clone],
+// and the size function of [WithSizeAwareEviction]
+// receive the encoded values.
+func WithEncode[Key comparable, Value any](encode func(Value) Value) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if encode == nil {
+			return fmt.Errorf("%w: encode function must not be nil", ErrInvalidOption)
+		}
+		set.encode = encode
+		return nil
+	}
+}
+
+// WithDecode transforms stored values each time
+// they are returned by the cache. See [WithEncode].
+func WithDecode[Key comparable, Value any](decode func(Value) Value) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if decode == nil {
+			return fmt.Errorf("%w: decode function must not be nil", ErrInvalidOption)
+		}
+		set.decode = decode
+		return nil
+	}
+}
+
+func (c *Cache[Key, Value]) encoded(value Value) Value {
+	if encode := c.encode; encode != nil {
+		return encode(value)
+	}
+	return value
+}
+
+func (c *Cache[Key, Value]) decoded(value Value) Value {
+	if decode := c.decode; decode != nil {
+		return decode(value)
+	}
+	return value
+}
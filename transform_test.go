@@ -0,0 +1,146 @@
+package clockpro_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestTransform(t *testing.T) {
+	t.Run("invalid", transformInvalid)
+	t.Run("round trip", transformRoundTrip)
+	t.Run("synced", transformSynced)
+	t.Run("merge", transformMerge)
+	t.Run("eviction decodes lazily", transformEviction)
+}
+
+// offset encodes values by adding to them,
+// so that undecoded values are distinguishable.
+const offset = 1000
+
+func transformOptions(inserted *[]int) []clockpro.Option[int, int] {
+	return []clockpro.Option[int, int]{
+		clockpro.WithEncode[int, int](func(value int) int { return value + offset }),
+		clockpro.WithDecode[int, int](func(value int) int { return value - offset }),
+		clockpro.WithHooks(clockpro.Hooks[int, int]{
+			OnInsert: func(_, value int) {
+				if inserted != nil {
+					*inserted = append(*inserted, value)
+				}
+			},
+		}),
+	}
+}
+
+func newTransformCache(tb testing.TB, capacity int, inserted *[]int) *clockpro.Cache[int, int] {
+	tb.Helper()
+	cache, err := clockpro.New(capacity, transformOptions(inserted)...)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return cache
+}
+
+func transformInvalid(t *testing.T) {
+	t.Parallel()
+	for _, option := range []clockpro.Option[int, int]{
+		clockpro.WithEncode[int, int](nil),
+		clockpro.WithDecode[int, int](nil),
+	} {
+		if _, err := clockpro.New(2, option); !errors.Is(err, clockpro.ErrInvalidOption) {
+			t.Errorf(
+				"expected error to match"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				err, clockpro.ErrInvalidOption)
+		}
+	}
+}
+
+func transformRoundTrip(t *testing.T) {
+	t.Parallel()
+	const capacity = 4
+	var (
+		inserted []int
+		cache    = newTransformCache(t, capacity, &inserted)
+	)
+	addIncrementingInts(cache, capacity)
+	for key := 1; key <= capacity; key++ {
+		checkGet(t, cache, key, key, "decoded value")
+		if want := key + offset; inserted[key-1] != want {
+			t.Errorf(
+				"hook did not receive the encoded value"+
+					"\n\tgot: %d"+
+					"\n\twant: %d",
+				inserted[key-1], want,
+			)
+		}
+	}
+	if actual, loaded := cache.GetOrSet(1, 0); !loaded || actual != 1 {
+		t.Errorf("GetOrSet returned an undecoded value: %d", actual)
+	}
+	cache.Export(func(key, value int, _ clockpro.EntryInfo) bool {
+		if value != key {
+			t.Errorf("Export returned an undecoded value: %d", value)
+		}
+		return true
+	})
+	for key, value := range cache.Snapshot().All() {
+		if value != key {
+			t.Errorf("Snapshot returned an undecoded value: %d", value)
+		}
+	}
+	if value, ok := cache.Remove(1); !ok || value != 1 {
+		t.Errorf("Remove returned an undecoded value: %d", value)
+	}
+	_, evicted, ok := cache.SetGetEvicted(capacity+1, capacity+1)
+	if ok && evicted > capacity {
+		t.Errorf("SetGetEvicted returned an undecoded value: %d", evicted)
+	}
+}
+
+func transformSynced(t *testing.T) {
+	t.Parallel()
+	const capacity = 4
+	cache := newSynced(t, capacity, transformOptions(nil)...)
+	addIncrementingInts(cache, capacity)
+	for key := 1; key <= capacity; key++ {
+		checkGet(t, cache, key, key, "decoded value")
+	}
+}
+
+func transformMerge(t *testing.T) {
+	t.Parallel()
+	const capacity = 4
+	var (
+		cache = newTransformCache(t, capacity, nil)
+		other = newTransformCache(t, capacity, nil)
+	)
+	cache.Set(1, 1)
+	other.Set(1, 2)
+	other.Set(2, 2)
+	cache.Merge(other, func(a, b int) int { return a + b })
+	checkGet(t, cache, 1, 3, "merged value")
+	checkGet(t, cache, 2, 2, "imported value")
+}
+
+func transformEviction(t *testing.T) {
+	t.Parallel()
+	const capacity = 4
+	var (
+		decoded    int
+		decode     = func(value int) int { decoded++; return value }
+		cache, err = clockpro.New(capacity, clockpro.WithDecode[int, int](decode))
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addIncrementingInts(cache, capacity*4)
+	if decoded != 0 {
+		t.Errorf("expected evicted values to not be decoded, but %d were", decoded)
+	}
+	if _, value, evicted := cache.SetGetEvicted(-1, -1); !evicted || decoded != 1 {
+		t.Errorf("expected the returned value (%d) to be decoded once, but %d were", value, decoded)
+	}
+}
@@ -0,0 +1,125 @@
+package clockpro
+
+import (
+	"fmt"
+	"time"
+)
+
+// CapacityTuning configures [Cache.TuneCapacity].
+type CapacityTuning struct {
+	// Minimum and Maximum bound the capacity.
+	Minimum, Maximum int
+	// Step is the amount of capacity added
+	// or removed by each adjustment.
+	Step int
+	// Gain is the fraction of reused accesses, within (0,1],
+	// which must be estimated to hit per Step of additional
+	// capacity, up to any capacity within the bounds,
+	// for the cache to grow by a Step. The cache shrinks
+	// if fewer than half as many are estimated to hit within
+	// the last Step of its capacity.
+	Gain float64
+}
+
+// TuneCapacity adjusts the capacity of the cache by the
+// marginal gain in hits of a larger or smaller capacity,
+// as estimated by the reuse distances recorded since it
+// was last called, and returns the resulting capacity.
+// Since the gain of larger capacities is averaged over
+// each Step, the cache grows toward a capacity which would
+// fit a working set, even if the next Step alone would not.
+// Resident pages are evicted if the cache shrinks.
+// The cache must be constructed with [WithReuseDistances].
+func (c *Cache[Key, Value]) TuneCapacity(tuning CapacityTuning) (int, error) {
+	if c.reuse == nil {
+		return c.capacity, fmt.Errorf(
+			"%w: tuning capacity requires reuse distances",
+			ErrInvalidOption,
+		)
+	}
+	if err := tuning.validate(); err != nil {
+		return c.capacity, err
+	}
+	var (
+		distances = c.stats.total.reuseDistances.sub(&c.tuned)
+		capacity  = c.capacity
+		grown     = min(capacity+tuning.Step, tuning.Maximum)
+		shrunk    = max(capacity-tuning.Step, tuning.Minimum)
+	)
+	c.tuned = c.stats.total.reuseDistances
+	if bounded := min(max(capacity, tuning.Minimum), tuning.Maximum); bounded != capacity {
+		c.resize(bounded)
+		return bounded, nil
+	}
+	if distances.Count() == 0 {
+		return capacity, nil
+	}
+	var (
+		hits   = distances.below(uint64(capacity))
+		loss   = hits - distances.below(uint64(shrunk))
+		target = capacity
+	)
+	for steps, larger := 1, grown; larger != capacity; steps++ {
+		gain := distances.below(uint64(larger)) - hits
+		if gain/float64(steps) >= tuning.Gain {
+			target = grown
+			break
+		}
+		if larger == tuning.Maximum {
+			break
+		}
+		larger = min(larger+tuning.Step, tuning.Maximum)
+	}
+	if target == capacity && shrunk != capacity && loss < tuning.Gain/2 {
+		target = shrunk
+	}
+	if target != capacity {
+		c.resize(target)
+	}
+	return target, nil
+}
+
+// TuneCapacity is like [Cache.TuneCapacity].
+func (s *Synced[_, _]) TuneCapacity(tuning CapacityTuning) (int, error) {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.cache.TuneCapacity(tuning)
+}
+
+// TuneCapacityEvery calls [Synced.TuneCapacity] every interval,
+// from a new goroutine, until stop is called.
+// Tuning is validated before the goroutine is started.
+func (s *Synced[_, _]) TuneCapacityEvery(interval time.Duration, tuning CapacityTuning) (stop func(), err error) {
+	if s.cache.reuse == nil {
+		return nil, fmt.Errorf(
+			"%w: tuning capacity requires reuse distances",
+			ErrInvalidOption,
+		)
+	}
+	if err := tuning.validate(); err != nil {
+		return nil, err
+	}
+	return every(interval, func() { s.TuneCapacity(tuning) }), nil
+}
+
+func (tuning CapacityTuning) validate() error {
+	if tuning.Minimum < MinimumCapacity || tuning.Maximum < tuning.Minimum {
+		return fmt.Errorf(
+			"%w: capacity bounds must be within [%d,∞) but [%d,%d] was provided",
+			ErrInvalidOption, MinimumCapacity, tuning.Minimum, tuning.Maximum,
+		)
+	}
+	if tuning.Step < 1 {
+		return fmt.Errorf(
+			"%w: capacity step must be >=1 but %d was provided",
+			ErrInvalidOption, tuning.Step,
+		)
+	}
+	if !(tuning.Gain > 0 && tuning.Gain <= 1) {
+		return fmt.Errorf(
+			"%w: capacity gain must be within (0,1] but %f was provided",
+			ErrInvalidOption, tuning.Gain,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package clockpro_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestTuneCapacity(t *testing.T) {
+	t.Run("invalid", tuneInvalid)
+	t.Run("grow", tuneGrow)
+	t.Run("shrink", tuneShrink)
+}
+
+const tuneCapacity = 64
+
+var validTuning = clockpro.CapacityTuning{
+	Minimum: tuneCapacity / 4,
+	Maximum: tuneCapacity * 4,
+	Step:    tuneCapacity / 4,
+	Gain:    0.1,
+}
+
+func newTuned(t *testing.T) *clockpro.Cache[int, int] {
+	t.Helper()
+	cache, err := clockpro.New(tuneCapacity,
+		clockpro.WithReuseDistances[int, int](1, validTuning.Maximum),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cache
+}
+
+func tuneInvalid(t *testing.T) {
+	t.Parallel()
+	untuned := newCache[int, int](t, tuneCapacity).(*clockpro.Cache[int, int])
+	if _, err := untuned.TuneCapacity(validTuning); !errors.Is(err, clockpro.ErrInvalidOption) {
+		t.Errorf(
+			"expected error to match"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			err, clockpro.ErrInvalidOption)
+	}
+	cache := newTuned(t)
+	for _, test := range []struct {
+		name   string
+		modify func(*clockpro.CapacityTuning)
+	}{
+		{"minimum", func(ct *clockpro.CapacityTuning) { ct.Minimum = 1 }},
+		{"maximum", func(ct *clockpro.CapacityTuning) { ct.Maximum = ct.Minimum - 1 }},
+		{"step", func(ct *clockpro.CapacityTuning) { ct.Step = 0 }},
+		{"gain", func(ct *clockpro.CapacityTuning) { ct.Gain = 0 }},
+	} {
+		tuning := validTuning
+		test.modify(&tuning)
+		if _, err := cache.TuneCapacity(tuning); !errors.Is(err, clockpro.ErrInvalidOption) {
+			t.Errorf(
+				"%s: expected error to match"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				test.name, err, clockpro.ErrInvalidOption)
+		}
+	}
+}
+
+// tuneLoop repeatedly accesses keys in a loop,
+// tuning the capacity after each pass,
+// and returns the final capacity.
+func tuneLoop(t *testing.T, cache *clockpro.Cache[int, int], keys int) int {
+	t.Helper()
+	var capacity int
+	for range 16 {
+		for key := range keys {
+			if _, ok := cache.Get(key); !ok {
+				cache.Set(key, key)
+			}
+		}
+		var err error
+		if capacity, err = cache.TuneCapacity(validTuning); err != nil {
+			t.Fatal(err)
+		}
+		if err := cache.CheckInvariants(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return capacity
+}
+
+func tuneGrow(t *testing.T) {
+	t.Parallel()
+	const keys = tuneCapacity * 2
+	cache := newTuned(t)
+	if capacity := tuneLoop(t, cache, keys); capacity < keys {
+		t.Errorf(
+			"capacity did not grow to fit the loop"+
+				"\n\tgot: %d"+
+				"\n\twant: >=%d",
+			capacity, keys)
+	}
+	if misses := cache.Stats().Misses; misses == 0 {
+		t.Error("expected misses before the capacity grew")
+	}
+}
+
+func tuneShrink(t *testing.T) {
+	t.Parallel()
+	const keys = tuneCapacity / 8
+	cache := newTuned(t)
+	if capacity := tuneLoop(t, cache, keys); capacity != validTuning.Minimum {
+		t.Errorf(
+			"capacity did not shrink to its minimum"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			capacity, validTuning.Minimum)
+	}
+	checkSize(t, cache, keys, "after shrinking")
+}
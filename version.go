@@ -0,0 +1,95 @@
+package clockpro
+
+import "maps"
+
+// versions numbers the values of resident entries.
+// Numbers are assigned on demand, and are discarded
+// whenever the value of their entry is replaced or dropped,
+// so that a number identifies a single value.
+type versions[Key comparable] struct {
+	numbers map[Key]uint64
+	// last is the most recently assigned number.
+	// Numbers are never reused, even across purges.
+	last uint64
+}
+
+// SetVersioned is like [Cache.Set], but also returns
+// the version of value, which may be compared by
+// [Cache.CompareAndSetVersion]. Version 0 is never
+// assigned, and is returned if value was not stored.
+func (c *Cache[Key, Value]) SetVersioned(key Key, value Value) (version uint64) {
+	c.Set(key, value)
+	if page, ok := c.index.get(key); !ok || !page.Resident {
+		return 0 // Admission was denied.
+	}
+	return c.versions.next(key)
+}
+
+// GetVersioned is like [Cache.Get], but also returns
+// the version of the value. Values which were not set
+// by [Cache.SetVersioned] are assigned a version
+// when they are first retrieved by GetVersioned.
+func (c *Cache[Key, Value]) GetVersioned(key Key) (value Value, version uint64, ok bool) {
+	if value, ok = c.Get(key); !ok {
+		return value, 0, false
+	}
+	return value, c.versions.get(key), true
+}
+
+// CompareAndSetVersion replaces the value of key with value,
+// like [Cache.Set], only if key is resident and its value
+// has the version returned by [Cache.SetVersioned]
+// or [Cache.GetVersioned]. Any other modification of key,
+// or its eviction, changes its version, so that a writer
+// which refreshes a value from its source does not replace
+// a newer value which was set concurrently.
+// CompareAndSetVersion reports whether value was stored.
+func (c *Cache[Key, Value]) CompareAndSetVersion(key Key, version uint64, value Value) bool {
+	page, ok := c.index.get(key)
+	if !ok || !page.Resident {
+		return false
+	}
+	if c.expired(key) {
+		c.expirePage(page)
+		return false
+	}
+	if current, ok := c.versions.numbers[key]; !ok || current != version {
+		return false
+	}
+	c.SetVersioned(key, value)
+	return true
+}
+
+// get returns the number of key's value,
+// assigning one if it has none.
+func (vs *versions[Key]) get(key Key) uint64 {
+	if number, ok := vs.numbers[key]; ok {
+		return number
+	}
+	return vs.next(key)
+}
+
+// next assigns a new number to key's value.
+func (vs *versions[Key]) next(key Key) uint64 {
+	if vs.numbers == nil {
+		vs.numbers = make(map[Key]uint64)
+	}
+	vs.last++
+	vs.numbers[key] = vs.last
+	return vs.last
+}
+
+func (vs *versions[Key]) drop(key Key) {
+	delete(vs.numbers, key)
+}
+
+func (vs *versions[Key]) reset() {
+	clear(vs.numbers)
+}
+
+func (vs *versions[Key]) clone() versions[Key] {
+	return versions[Key]{
+		numbers: maps.Clone(vs.numbers),
+		last:    vs.last,
+	}
+}
@@ -0,0 +1,112 @@
+package clockpro_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestVersion(t *testing.T) {
+	t.Run("compare and set", versionCompareAndSet)
+	t.Run("concurrent", versionConcurrent)
+}
+
+func versionCompareAndSet(t *testing.T) {
+	t.Parallel()
+	const capacity = 2
+	cache, err := clockpro.New[int, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkVersioned := func(key, wantValue int, wantVersion uint64, context string) {
+		t.Helper()
+		value, version, ok := cache.GetVersioned(key)
+		if !ok || value != wantValue || version != wantVersion {
+			t.Errorf(
+				"unexpected versioned value %s"+
+					"\n\tgot: %d, %d, %t"+
+					"\n\twant: %d, %d, %t",
+				context, value, version, ok, wantValue, wantVersion, true,
+			)
+		}
+	}
+	checkSwap := func(key int, version uint64, value int, want bool, context string) {
+		t.Helper()
+		if got := cache.CompareAndSetVersion(key, version, value); got != want {
+			t.Errorf(
+				"unexpected result of compare and set %s"+
+					"\n\tgot: %t"+
+					"\n\twant: %t",
+				context, got, want,
+			)
+		}
+	}
+	first := cache.SetVersioned(1, 1)
+	if first == 0 {
+		t.Fatal("stored value was not versioned")
+	}
+	checkVersioned(1, 1, first, "after SetVersioned")
+	checkSwap(1, first, 2, true, "with current version")
+	_, second, _ := cache.GetVersioned(1)
+	if second == first {
+		t.Error("version was not replaced by compare and set")
+	}
+	checkSwap(1, first, 3, false, "with replaced version")
+	checkVersioned(1, 2, second, "after failed compare and set")
+	cache.Set(1, 4)
+	checkSwap(1, second, 5, false, "after Set")
+	// Values stored without a version are assigned one.
+	cache.Set(2, 2)
+	_, third, _ := cache.GetVersioned(2)
+	checkSwap(2, third, 3, true, "with assigned version")
+	checkSwap(capacity+1, third, 3, false, "of missing key")
+	// Evicted values lose their version.
+	_, fourth, _ := cache.GetVersioned(1)
+	cache.Set(capacity+1, 0)
+	cache.Set(capacity+2, 0)
+	cache.Set(1, 4)
+	checkSwap(1, fourth, 5, false, "after eviction")
+	if _, _, ok := cache.GetVersioned(capacity * 4); ok {
+		t.Error("missing key was found")
+	}
+}
+
+func versionConcurrent(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity   = 4
+		key        = 1
+		workers    = 8
+		increments = 256
+	)
+	var (
+		synced  = newSynced(t, capacity)
+		wg      sync.WaitGroup
+		swapped sync.Map // Worker -> count.
+	)
+	synced.SetVersioned(key, 0)
+	for worker := range workers {
+		wg.Go(func() {
+			var count int
+			for range increments {
+				value, version, ok := synced.GetVersioned(key)
+				if !ok {
+					t.Error("key was evicted")
+					return
+				}
+				if synced.CompareAndSetVersion(key, version, value+1) {
+					count++
+				}
+			}
+			swapped.Store(worker, count)
+		})
+	}
+	wg.Wait()
+	var want int
+	swapped.Range(func(_, count any) bool {
+		want += count.(int)
+		return true
+	})
+	checkGet(t, synced, key, want, "after concurrent increments")
+}
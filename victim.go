@@ -0,0 +1,74 @@
+package clockpro
+
+import "fmt"
+
+// victimSelection configures the choice of
+// which cold page to evict. See [WithSizeAwareEviction].
+type victimSelection[Key comparable, Value any] struct {
+	size   func(Key, Value) int
+	window int
+}
+
+// WithSizeAwareEviction biases evictions toward large,
+// infrequently used cold pages, approximating GreedyDual-Size.
+// Rather than evicting the first evictable page under the cold hand,
+// the evictable pages among the next window pages are compared,
+// and the page with the lowest ratio of frequency to size is evicted.
+// Pages within their test period count as more frequently used
+// than pages outside of it. Ties are broken in favour of the cold hand.
+// Caches which select victims by size cannot be checked by [Replay].
+func WithSizeAwareEviction[Key comparable, Value any](size func(Key, Value) int, window int) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if size == nil {
+			return fmt.Errorf(
+				"%w: size function must not be nil",
+				ErrInvalidOption,
+			)
+		}
+		if window < 1 {
+			return fmt.Errorf(
+				"%w: eviction window must be >=1 but %d was provided",
+				ErrInvalidOption, window,
+			)
+		}
+		set.victims = &victimSelection[Key, Value]{
+			size:   size,
+			window: window,
+		}
+		return nil
+	}
+}
+
+// victim returns the page which should be evicted next.
+func (c *Cache[Key, Value]) victim() *page[Key, Value] {
+	victim := c.cold
+	if c.victims == nil {
+		return victim
+	}
+	var (
+		bestPriority = c.victims.priority(victim)
+		page         = c.clock.Next(victim)
+	)
+	for range c.victims.window - 1 {
+		if page == c.cold {
+			break
+		}
+		if !page.LIR && page.Resident && !referenced(page) {
+			if priority := c.victims.priority(page); priority < bestPriority {
+				victim, bestPriority = page, priority
+			}
+		}
+		page = c.clock.Next(page)
+	}
+	return victim
+}
+
+// priority returns the GreedyDual-Size priority of a page,
+// which is lower for pages that should be evicted first.
+func (vs *victimSelection[Key, Value]) priority(page *page[Key, Value]) float64 {
+	frequency := 1.0
+	if page.Stacked {
+		frequency = 2
+	}
+	return frequency / float64(max(vs.size(page.Name, page.Value), 1))
+}
@@ -0,0 +1,72 @@
+package clockpro_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestSizeAwareEviction(t *testing.T) {
+	t.Run("invalid", sizeAwareInvalid)
+	t.Run("evicts large pages", sizeAwareEvictsLarge)
+}
+
+func sizeAwareInvalid(t *testing.T) {
+	t.Parallel()
+	size := func(int, int) int { return 1 }
+	for _, option := range []clockpro.Option[int, int]{
+		clockpro.WithSizeAwareEviction[int, int](nil, 1),
+		clockpro.WithSizeAwareEviction(size, 0),
+	} {
+		if _, err := clockpro.New(2, option); !errors.Is(err, clockpro.ErrInvalidOption) {
+			t.Errorf(
+				"expected error to match"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				err, clockpro.ErrInvalidOption)
+		}
+	}
+}
+
+func sizeAwareEvictsLarge(t *testing.T) {
+	t.Parallel()
+	const (
+		capacity = 8
+		large    = 12
+	)
+	for _, test := range []struct {
+		name    string
+		options []clockpro.Option[int, int]
+		evicted int
+	}{
+		{"disabled", nil, capacity},
+		{"enabled", []clockpro.Option[int, int]{
+			clockpro.WithSizeAwareEviction(func(key, _ int) int {
+				if key == large {
+					return 100
+				}
+				return 1
+			}, capacity),
+		}, large},
+	} {
+		cache, err := clockpro.New(capacity, test.options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addIncrementingInts(cache, capacity) // 1-7 hot, 8 cold.
+		for key := 1; key < capacity-1; key++ {
+			cache.Delete(key)
+		}
+		for key := capacity + 1; cache.Len() < capacity; key++ {
+			cache.Set(key, key) // Cold, while cold pages exist.
+		}
+		cache.Set(capacity*2, capacity*2)
+		if keys := slices.Collect(cache.Keys()); slices.Contains(keys, test.evicted) {
+			t.Errorf(
+				"%s: expected %d to be evicted, residents: %v",
+				test.name, test.evicted, keys)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package clockpro
+
+import "fmt"
+
+// trimBatch is the most pages evicted by the trimmer
+// of a [Synced] cache each time it acquires the lock.
+const trimBatch = 16
+
+// WithSoftWatermark starts a goroutine for [Synced] caches,
+// which evicts cold pages while the resident pages exceed
+// the fraction of the capacity given, within (0,1).
+// The capacity remains a hard limit, which is enforced
+// by the method which inserts a page, as usual; evicting
+// ahead of it smooths bursts of insertions, which then
+// find room without evicting. The trimmer evicts in batches,
+// releasing the lock between them.
+// Also used by [NewSharded], for each shard;
+// ignored by other constructors.
+func WithSoftWatermark[Key comparable, Value any](fraction float64) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if !(fraction > 0 && fraction < 1) {
+			return fmt.Errorf(
+				"%w: soft watermark must be within (0,1) but %f was provided",
+				ErrInvalidOption, fraction,
+			)
+		}
+		set.softWatermark = fraction
+		return nil
+	}
+}
+
+// softLimit returns the amount of resident
+// pages allowed by the soft watermark.
+func (c *Cache[_, _]) softLimit() int {
+	return max(int(float64(c.capacity)*c.softWatermark), 1)
+}
+
+func (c *Cache[_, _]) aboveSoftWatermark() bool {
+	return c.Len() > c.softLimit()
+}
+
+func (s *Synced[_, _]) trimCold() {
+	defer s.workers.Done()
+	for {
+		select {
+		case <-s.trim:
+			for s.trimBatch() {
+			}
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// trimBatch evicts a batch of pages above the soft watermark,
+// and reports whether more remain.
+func (s *Synced[_, _]) trimBatch() bool {
+	s.lock()
+	defer s.mu.Unlock()
+	excess := s.cache.Len() - s.cache.softLimit()
+	if excess <= 0 {
+		return false
+	}
+	evicted := s.cache.EvictN(min(excess, trimBatch))
+	return evicted != 0 && excess > evicted
+}
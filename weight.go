@@ -0,0 +1,66 @@
+package clockpro
+
+import "fmt"
+
+// WithMaxWeight limits the total weight of the resident values,
+// as measured by weigh, in addition to the capacity, which limits
+// the amount of entries. Once the total exceeds maximum, entries
+// are evicted as if by [Cache.EvictN] until it does not.
+// A value which weighs more than maximum is not stored,
+// and any previous value of its key is removed.
+// weigh must return the same weight for a value each time,
+// and is called with values as they are stored
+// (see [WithCompression]).
+func WithMaxWeight[Key comparable, Value any](weigh func(Key, Value) int, maximum int) Option[Key, Value] {
+	return func(set *settings[Key, Value]) error {
+		if weigh == nil {
+			return fmt.Errorf(
+				"%w: weight function must not be nil",
+				ErrInvalidOption,
+			)
+		}
+		if maximum < 1 {
+			return fmt.Errorf(
+				"%w: maximum weight must be >=1 but %d was provided",
+				ErrInvalidOption, maximum,
+			)
+		}
+		set.weigh = weigh
+		set.maxWeight = maximum
+		return nil
+	}
+}
+
+// Weight returns the total weight of the resident values,
+// as measured by the function given to [WithMaxWeight],
+// or 0 if the cache was not constructed with it.
+func (c *Cache[_, _]) Weight() int { return c.weight }
+
+// overweight reports whether value
+// may never be stored by the cache.
+func (c *Cache[Key, Value]) overweight(key Key, value Value) bool {
+	return c.weigh != nil && c.weigh(key, value) > c.maxWeight
+}
+
+// trimWeight evicts pages until the
+// total weight is within its maximum.
+func (c *Cache[_, _]) trimWeight() {
+	for c.weigh != nil && c.weight > c.maxWeight && c.EvictN(1) != 0 {
+	}
+}
+
+// becameResident records that the value of page became resident.
+func (c *Cache[Key, Value]) becameResident(page *page[Key, Value]) {
+	c.forgetRestored(page.Name)
+	c.journal.inserted(page.Name)
+	if c.weigh != nil {
+		c.weight += c.weigh(page.Name, page.Value)
+	}
+}
+
+// reweigh accounts for the value of page being replaced.
+func (c *Cache[Key, Value]) reweigh(page *page[Key, Value], value Value) {
+	if c.weigh != nil {
+		c.weight += c.weigh(page.Name, value) - c.weigh(page.Name, page.Value)
+	}
+}
@@ -0,0 +1,115 @@
+package clockpro_test
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/djdv/go-clockpro"
+)
+
+func TestMaxWeight(t *testing.T) {
+	t.Run("invalid", weightInvalid)
+	t.Run("limits", weightLimits)
+	t.Run("overweight", weightOverweight)
+}
+
+const (
+	weightCapacity = 16
+	maxWeight      = 64
+)
+
+func newWeighted(t *testing.T) *clockpro.Cache[int, []byte] {
+	t.Helper()
+	cache, err := clockpro.New(weightCapacity,
+		clockpro.WithMaxWeight(func(_ int, value []byte) int {
+			return len(value)
+		}, maxWeight),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cache
+}
+
+func weightInvalid(t *testing.T) {
+	t.Parallel()
+	for _, option := range []clockpro.Option[int, int]{
+		clockpro.WithMaxWeight[int, int](nil, 1),
+		clockpro.WithMaxWeight(func(int, int) int { return 1 }, 0),
+	} {
+		if _, err := clockpro.New(weightCapacity, option); !errors.Is(err, clockpro.ErrInvalidOption) {
+			t.Errorf(
+				"expected error to match"+
+					"\n\tgot: %v"+
+					"\n\twant: %v",
+				err, clockpro.ErrInvalidOption)
+		}
+	}
+}
+
+// weightLimits expects small values to be limited by
+// the capacity, and large values by the maximum weight.
+func weightLimits(t *testing.T) {
+	t.Parallel()
+	var (
+		cache = newWeighted(t)
+		rng   = rand.New(rand.NewSource(rngSeed))
+	)
+	for key := range 1 << 10 {
+		size := 1
+		if key%2 == 0 {
+			size = rng.Intn(maxWeight/2) + 1
+		}
+		switch rng.Intn(4) {
+		case 0:
+			cache.Get(rng.Intn(key + 1))
+		case 1:
+			cache.Delete(rng.Intn(key + 1))
+		default:
+			cache.Set(rng.Intn(key/2+1), make([]byte, size))
+		}
+		if err := cache.CheckInvariants(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cache.Purge(nil)
+	for key := range weightCapacity * 2 {
+		cache.Set(key, make([]byte, 1))
+	}
+	checkSize(t, cache, weightCapacity, "after setting light values")
+	cache.Set(-1, make([]byte, maxWeight-1))
+	if weight := cache.Weight(); weight > maxWeight {
+		t.Errorf(
+			"weight exceeded its maximum"+
+				"\n\tgot: %d"+
+				"\n\twant: <=%d",
+			weight, maxWeight)
+	}
+}
+
+func weightOverweight(t *testing.T) {
+	t.Parallel()
+	cache := newWeighted(t)
+	cache.Set(1, make([]byte, 1))
+	cache.Set(2, make([]byte, 2))
+	if outcome := cache.SetReport(1, make([]byte, maxWeight+1)); outcome != clockpro.SetRejected {
+		t.Errorf(
+			"overweight value was not rejected"+
+				"\n\tgot: %v"+
+				"\n\twant: %v",
+			outcome, clockpro.SetRejected)
+	}
+	mustMiss(t, cache, 1, "overweight value")
+	mustGet(t, cache, 2)
+	if got, want := cache.Weight(), 2; got != want {
+		t.Errorf(
+			"unexpected weight"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			got, want)
+	}
+	if err := cache.CheckInvariants(); err != nil {
+		t.Fatal(err)
+	}
+}
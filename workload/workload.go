@@ -0,0 +1,87 @@
+// Package workload generates reproducible sequences of keys
+// which model common cache access patterns.
+// Sequences generated with the same parameters
+// (including the seed) are identical.
+package workload
+
+import "math/rand"
+
+// Sequential returns length keys which cycle
+// through [0, universe) in order, modeling scans.
+// It panics if universe <= 0.
+func Sequential(universe, length int) []int {
+	if universe <= 0 {
+		panic("workload: universe must be positive")
+	}
+	keys := make([]int, length)
+	for i := range keys {
+		keys[i] = i % universe
+	}
+	return keys
+}
+
+// Looping returns length keys, each drawn from
+// the working set [0, working) with probability hotRatio,
+// and from the rest of [0, universe) otherwise.
+// It panics if working <= 0.
+func Looping(seed int64, working, universe, length int, hotRatio float64) []int {
+	if working <= 0 {
+		panic("workload: working set must be positive")
+	}
+	var (
+		keys     = make([]int, length)
+		rng      = newRNG(seed)
+		coldSize = max(1, universe-working)
+	)
+	for i := range keys {
+		if rng.Float64() < hotRatio {
+			keys[i] = rng.Intn(working)
+		} else {
+			keys[i] = working + rng.Intn(coldSize)
+		}
+	}
+	return keys
+}
+
+// Zipf returns length keys within [0, universe),
+// drawn from a Zipf distribution where key k
+// is accessed proportionally to (bias+k)^(-skew),
+// modeling skewed popularity.
+// It panics if universe < 2, skew <= 1, or bias < 1.
+func Zipf(seed int64, universe, length int, skew, bias float64) []int {
+	if universe < 2 {
+		panic("workload: universe must be at least 2")
+	}
+	var (
+		keys = make([]int, length)
+		zipf = rand.NewZipf(newRNG(seed), skew, bias, uint64(universe-1))
+	)
+	if zipf == nil {
+		panic("workload: skew must be > 1 and bias must be >= 1")
+	}
+	for i := range keys {
+		keys[i] = int(zipf.Uint64())
+	}
+	return keys
+}
+
+// Uniform returns length keys drawn uniformly
+// from [0, universe), modeling random access.
+// It panics if universe <= 0.
+func Uniform(seed int64, universe, length int) []int {
+	if universe <= 0 {
+		panic("workload: universe must be positive")
+	}
+	var (
+		keys = make([]int, length)
+		rng  = newRNG(seed)
+	)
+	for i := range keys {
+		keys[i] = rng.Intn(universe)
+	}
+	return keys
+}
+
+func newRNG(seed int64) *rand.Rand {
+	return rand.New(rand.NewSource(seed))
+}
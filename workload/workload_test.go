@@ -0,0 +1,152 @@
+package workload_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/djdv/go-clockpro/workload"
+)
+
+const (
+	seed     = 1
+	universe = 256
+	length   = 1 << 12
+)
+
+func TestWorkload(t *testing.T) {
+	t.Run("sequential", sequential)
+	t.Run("looping", looping)
+	t.Run("zipf", zipf)
+	t.Run("uniform", uniform)
+	t.Run("invalid", invalid)
+}
+
+func sequential(t *testing.T) {
+	t.Parallel()
+	keys := workload.Sequential(universe, length)
+	checkKeys(t, keys, universe)
+	for i, key := range keys {
+		if want := i % universe; key != want {
+			t.Fatalf(
+				"unexpected key at %d"+
+					"\n\tgot: %d"+
+					"\n\twant: %d",
+				i, key, want,
+			)
+		}
+	}
+}
+
+func looping(t *testing.T) {
+	t.Parallel()
+	const (
+		working   = universe / 4
+		hotRatio  = 0.9
+		tolerance = 0.05
+	)
+	generate := func() []int {
+		return workload.Looping(seed, working, universe, length, hotRatio)
+	}
+	keys := checkReproducible(t, generate)
+	checkKeys(t, keys, universe)
+	var hot int
+	for _, key := range keys {
+		if key < working {
+			hot++
+		}
+	}
+	if got := float64(hot) / length; got < hotRatio-tolerance || got > hotRatio+tolerance {
+		t.Errorf(
+			"unexpected ratio of working set keys"+
+				"\n\tgot: %f"+
+				"\n\twant: %f±%f",
+			got, hotRatio, tolerance,
+		)
+	}
+}
+
+func zipf(t *testing.T) {
+	t.Parallel()
+	const (
+		skew = 1.2
+		bias = 1.0
+	)
+	generate := func() []int {
+		return workload.Zipf(seed, universe, length, skew, bias)
+	}
+	keys := checkReproducible(t, generate)
+	checkKeys(t, keys, universe)
+	counts := make([]int, universe)
+	for _, key := range keys {
+		counts[key]++
+	}
+	if counts[0] <= counts[universe/2] {
+		t.Errorf(
+			"distribution is not skewed toward low keys"+
+				"\n\tgot: %d accesses of 0 and %d of %d",
+			counts[0], counts[universe/2], universe/2,
+		)
+	}
+}
+
+func uniform(t *testing.T) {
+	t.Parallel()
+	generate := func() []int {
+		return workload.Uniform(seed, universe, length)
+	}
+	keys := checkReproducible(t, generate)
+	checkKeys(t, keys, universe)
+	if other := workload.Uniform(seed+1, universe, length); slices.Equal(keys, other) {
+		t.Error("different seeds produced identical sequences")
+	}
+}
+
+func invalid(t *testing.T) {
+	t.Parallel()
+	for name, generate := range map[string]func(){
+		"sequential": func() { workload.Sequential(0, length) },
+		"looping":    func() { workload.Looping(seed, 0, universe, length, 0.5) },
+		"zipf":       func() { workload.Zipf(seed, universe, length, 1, 1) },
+		"uniform":    func() { workload.Uniform(seed, 0, length) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic for invalid parameters")
+				}
+			}()
+			generate()
+		})
+	}
+}
+
+func checkReproducible(tb testing.TB, generate func() []int) []int {
+	tb.Helper()
+	keys := generate()
+	if !slices.Equal(keys, generate()) {
+		tb.Fatal("sequences generated with identical parameters differ")
+	}
+	return keys
+}
+
+func checkKeys(tb testing.TB, keys []int, universe int) {
+	tb.Helper()
+	if len(keys) != length {
+		tb.Fatalf(
+			"unexpected length"+
+				"\n\tgot: %d"+
+				"\n\twant: %d",
+			len(keys), length,
+		)
+	}
+	for _, key := range keys {
+		if key < 0 || key >= universe {
+			tb.Fatalf(
+				"key out of range"+
+					"\n\tgot: %d"+
+					"\n\twant: [0, %d)",
+				key, universe,
+			)
+		}
+	}
+}